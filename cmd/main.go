@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"syscall"
+	"time"
 
 	"crawshaw.io/littleboss"
 	"github.com/golang/glog"
@@ -14,7 +17,15 @@ import (
 
 	"github.com/GeertJohan/go.rice"
 	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/inputs"
+	"github.com/myntra/cortex/pkg/inputs/k8s"
+	"github.com/myntra/cortex/pkg/inputs/kafka"
+	"github.com/myntra/cortex/pkg/inputs/snmp"
+	"github.com/myntra/cortex/pkg/inputs/syslog"
+	"github.com/myntra/cortex/pkg/inputs/tail"
 	"github.com/myntra/cortex/pkg/service"
+	"github.com/myntra/cortex/pkg/store"
 )
 
 var (
@@ -35,6 +46,14 @@ var (
 	cfg *config.Config
 )
 
+// bindRetry is how long the syslog and snmp inputs retry to bind their ports. littleboss only hands the tcp listener
+// flags over to the new process on reload, the process being replaced holds the input ports until its context is
+// cancelled.
+const bindRetry = 30 * time.Second
+
+// shutdownTimeout is how long the service waits for the in flight requests once littleboss stops the process
+const shutdownTimeout = 5 * time.Second
+
 func usage() {
 	fmt.Fprintf(os.Stderr, "usage: \n\n ./cortex -stderrthreshold=INFO -log_dir=$(pwd) -id=node1 lb=start & \n "+
 		"./cortex-new-version -stderrthreshold=INFO  -log_dir=$(pwd) -id=node1 -lb=reload \n ./cortex -lb=stop \n \n")
@@ -46,15 +65,33 @@ func usage() {
 func init() {
 	flag.Usage = usage
 	cfg = &config.Config{
-		NodeID:               "",
-		Dir:                  "./data",
-		JoinAddr:             "",
-		DefaultDwell:         3 * 60 * 1000,   // 3 minutes
-		DefaultMaxDwell:      6 * 60 * 1000,   // 6 minutes
-		DefaultDwellDeadline: 2.5 * 60 * 1000, // 2.5 minutes
-		MaxHistory:           1000,
-		FlushInterval:        1000,
-		SnapshotInterval:     30,
+		NodeID:                "",
+		Dir:                   "./data",
+		JoinAddr:              "",
+		DefaultDwell:          3 * 60 * 1000,   // 3 minutes
+		DefaultMaxDwell:       6 * 60 * 1000,   // 6 minutes
+		DefaultDwellDeadline:  2.5 * 60 * 1000, // 2.5 minutes
+		MaxHistory:            1000,
+		MaxHistoryPerRule:     100,
+		MaxHistoryAge:         7 * 24 * 60 * 60 * 1000, // 7 days
+		ExpireInterval:        60,
+		MaxResolvedIncidents:  store.DefaultMaxResolvedIncidents,
+		MaxIncidentAge:        7 * 24 * 60 * 60 * 1000, // 7 days
+		ExecutionWorkers:      store.DefaultExecutionWorkers,
+		ExecutionQueueSize:    store.DefaultExecutionQueueSize,
+		FlushInterval:         1000,
+		SnapshotInterval:      30,
+		AlertmanagerEventType: sinks.DefaultAlertmanagerEventType,
+		IcingaEventType:       sinks.DefaultIcingaEventType,
+		Site247EventType:      sinks.DefaultSite247EventType,
+		AzureEventType:        sinks.DefaultAzureEventType,
+		KafkaGroup:            "cortex",
+		KafkaVersion:          "1.0.0",
+		SyslogFormat:          "auto",
+		SyslogEventType:       syslog.DefaultEventType,
+		TailEventType:         tail.DefaultEventType,
+		K8sEventTypes:         "Warning",
+		K8sResync:             600,
 	}
 }
 
@@ -79,7 +116,7 @@ func main() {
 	glog.Infof("raft addr %v, http addr %v\n", flagRaft.String(), flagHTTP.String())
 
 	lb.Run(func(ctx context.Context) {
-		run(context.Background(), flagRaft, flagHTTP)
+		run(ctx, flagRaft, flagHTTP)
 	})
 
 	glog.Info("cortex exited")
@@ -108,7 +145,91 @@ func run(ctx context.Context, flagRaft, flagHTTP *littleboss.ListenerFlag) {
 		}
 	}()
 
+	if cfg.KafkaBrokers != "" {
+		consumer, err := kafka.New(cfg, svc.Node(), svc.Sinks())
+		if err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+		go consumer.Run(ctx)
+	}
+
+	var syslogReceiver *syslog.Receiver
+	if syslog.Enabled(cfg) {
+		err = listenRetry(ctx, "syslog", func() error {
+			syslogReceiver, err = syslog.New(cfg, inputs.NewForwarder(svc.Node()))
+			return err
+		})
+		if err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+		if err := syslogReceiver.Start(); err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+	}
+
+	var snmpReceiver *snmp.Receiver
+	if cfg.SNMPTrapAddr != "" {
+		snmpReceiver, err = snmp.New(cfg, inputs.NewForwarder(svc.Node()))
+		if err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+		if err := listenRetry(ctx, "snmp", snmpReceiver.Start); err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+	}
+
+	if k8s.Enabled(cfg) {
+		watcher, err := k8s.New(cfg, svc.Node())
+		if err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+		go watcher.Run(ctx)
+	}
+
+	if tail.Enabled(cfg) {
+		tailer, err := tail.New(cfg, inputs.NewForwarder(svc.Node()), svc.Node())
+		if err != nil {
+			glog.Error(err)
+			os.Exit(1)
+		}
+		go tailer.Run(ctx)
+	}
+
 	<-ctx.Done()
-	svc.Shutdown(ctx)
+	if snmpReceiver != nil {
+		snmpReceiver.Shutdown()
+	}
+	if syslogReceiver != nil {
+		if err := syslogReceiver.Shutdown(); err != nil {
+			glog.Errorf("error shutting down the syslog receiver %v", err)
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	svc.Shutdown(shutdownCtx)
+}
 
+// listenRetry calls listen until the address is no longer in use, for up to bindRetry
+func listenRetry(ctx context.Context, name string, listen func() error) error {
+	deadline := time.Now().Add(bindRetry)
+	for {
+		err := listen()
+		if err == nil || !errors.Is(err, syscall.EADDRINUSE) || time.Now().After(deadline) {
+			return err
+		}
+
+		glog.Infof("%s address in use, retrying %v", name, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
 }
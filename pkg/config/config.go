@@ -13,24 +13,70 @@ import (
 
 // Config is required for initializing the service
 type Config struct {
-	NodeID               string `config:"id"`
-	Dir                  string `config:"dir"`
-	JoinAddr             string `config:"join"`
-	FlushInterval        uint64 `config:"flush_interval"`
-	SnapshotInterval     int    `config:"snapshot_interval"`
-	DefaultDwell         uint64 `config:"dwell"`
-	DefaultDwellDeadline uint64 `config:"dwell_deadline"`
-	DefaultMaxDwell      uint64 `config:"max_dwell"`
-	MaxHistory           int    `config:"max_history"`
-	Version              string `config:"version"`
-	Commit               string `config:"commit"`
-	Date                 string `config:"date"`
-	EnableFileServer     bool
+	NodeID                string `config:"id"`
+	Dir                   string `config:"dir"`
+	JoinAddr              string `config:"join"`
+	FlushInterval         uint64 `config:"flush_interval"`
+	SnapshotInterval      int    `config:"snapshot_interval"`
+	DefaultDwell          uint64 `config:"dwell"`
+	DefaultDwellDeadline  uint64 `config:"dwell_deadline"`
+	DefaultMaxDwell       uint64 `config:"max_dwell"`
+	MaxHistory            int    `config:"max_history"`
+	MaxHistoryPerRule     int    `config:"max_history_per_rule"`
+	MaxHistoryAge         uint64 `config:"max_history_age"`
+	ExpireInterval        int    `config:"expire_interval"`
+	MaxResolvedIncidents  int    `config:"max_resolved_incidents"`
+	MaxIncidentAge        uint64 `config:"max_incident_age"`
+	ExecutionWorkers      int    `config:"execution_workers"`
+	ExecutionQueueSize    int    `config:"execution_queue_size"`
+	AlertmanagerEventType string `config:"alertmanager_event_type"`
+	IcingaEventType       string `config:"icinga_event_type"`
+	Site247EventType      string `config:"site247_event_type"`
+	AzureEventType        string `config:"azure_event_type"`
+	KafkaBrokers          string `config:"kafka_brokers"` // comma separated, the consumer is disabled if empty
+	KafkaTopics           string `config:"kafka_topics"`  // comma separated
+	KafkaGroup            string `config:"kafka_group"`
+	KafkaVersion          string `config:"kafka_version"`
+	KafkaSink             string `config:"kafka_sink"` // sink decoding the messages, cloudevents json if empty
+	SyslogUDPAddr         string `config:"syslog_udp"`
+	SyslogTCPAddr         string `config:"syslog_tcp"`
+	SyslogTLSAddr         string `config:"syslog_tls"`
+	SyslogTLSCert         string `config:"syslog_tls_cert"`
+	SyslogTLSKey          string `config:"syslog_tls_key"`
+	SyslogFormat          string `config:"syslog_format"` // auto, rfc5424 or rfc3164
+	SyslogEventType       string `config:"syslog_event_type"`
+	SNMPTrapAddr          string `config:"snmp_trap"`
+	SNMPCommunity         string `config:"snmp_community"`     // v2c traps are rejected if empty
+	SNMPUser              string `config:"snmp_user"`          // v3 traps are rejected if empty
+	SNMPAuthProtocol      string `config:"snmp_auth_protocol"` // md5 or sha
+	SNMPAuthPassphrase    string `config:"snmp_auth_passphrase"`
+	SNMPPrivProtocol      string `config:"snmp_priv_protocol"` // des or aes
+	SNMPPrivPassphrase    string `config:"snmp_priv_passphrase"`
+	SNMPMIB               string `config:"snmp_mib"`           // mib map file, see snmp.LoadMIB
+	TailPaths             string `config:"tail_paths"`         // comma separated globs, the tailer is disabled if empty
+	TailPattern           string `config:"tail_pattern"`       // regex with named groups, lines not matching are skipped
+	TailEventType         string `config:"tail_event_type"`    // template of the named groups and the file name
+	TailTimeFormat        string `config:"tail_time_format"`   // layout of the event_time group
+	TailOffsets           string `config:"tail_offsets"`       // offsets file, <dir>/tail_offsets.json if empty
+	TailShared            bool   `config:"tail_shared"`        // the files are on a shared mount, tailed by the leader only
+	K8sCluster            string `config:"k8s_cluster"`        // cluster name of the event types, the watcher is disabled if empty
+	KubeConfig            string `config:"kubeconfig"`         // the in-cluster config is used if empty
+	K8sNamespace          string `config:"k8s_namespace"`      // all namespaces if empty
+	K8sEventTypes         string `config:"k8s_event_types"`    // comma separated, e.g. Warning. all types if empty
+	K8sResync             int    `config:"k8s_resync"`         // seconds
+	SchemaValidation      string `config:"schema_validation"`  // reject or tag, reject if empty
+	NormalizeMappings     string `config:"normalize_mappings"` // mappings file, see sinks.LoadNormalizeMappings
+	Version               string `config:"version"`
+	Commit                string `config:"commit"`
+	Date                  string `config:"date"`
+	EnableFileServer      bool
 
 	RaftAddr     string
 	HTTPAddr     string
+	GRPCAddr     string `config:"grpc"` // the grpc server is disabled if empty
 	RaftListener net.Listener
 	HTTPListener net.Listener
+	GRPCListener net.Listener // listens on GRPCAddr if nil
 }
 
 // Validate the config
@@ -71,6 +117,22 @@ func (c *Config) Validate() error {
 			"eg: -http :8081. the http port should be the next port relative to the raft port")
 	}
 
+	if c.GRPCAddr != "" {
+		gf := strings.SplitAfter(c.GRPCAddr, ":")
+		if len(gf) != 2 || gf[0] != ":" {
+			return fmt.Errorf("invalid grpc address. eg: -grpc :8082")
+		}
+
+		grpcPort, err := strconv.Atoi(gf[1])
+		if err != nil {
+			return fmt.Errorf("invalid grpc address. eg: -grpc :8082")
+		}
+
+		if grpcPort-raftPort != 2 {
+			return fmt.Errorf("invalid grpc address. eg: -raft :8080 -grpc :8082. the grpc port should be the raft port + 2")
+		}
+	}
+
 	if c.RaftListener == nil {
 		return fmt.Errorf("raft listener is nil")
 	}
@@ -104,6 +166,44 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max_dwell is not set")
 	}
 
+	if c.MaxHistory < 0 || c.MaxHistoryPerRule < 0 {
+		return fmt.Errorf("max_history and max_history_per_rule can't be negative")
+	}
+
+	if c.MaxResolvedIncidents < 0 {
+		return fmt.Errorf("max_resolved_incidents can't be negative")
+	}
+
+	if c.ExpireInterval < 0 {
+		return fmt.Errorf("expire_interval can't be negative")
+	}
+
+	if c.ExecutionWorkers < 0 {
+		return fmt.Errorf("execution_workers can't be negative")
+	}
+
+	if c.ExecutionQueueSize < 0 {
+		return fmt.Errorf("execution_queue_size can't be negative")
+	}
+
+	if c.KafkaBrokers != "" && (c.KafkaTopics == "" || c.KafkaGroup == "") {
+		return fmt.Errorf("kafka_topics and kafka_group must be set with kafka_brokers")
+	}
+
+	if c.SyslogTLSAddr != "" && (c.SyslogTLSCert == "" || c.SyslogTLSKey == "") {
+		return fmt.Errorf("syslog_tls_cert and syslog_tls_key must be set with syslog_tls")
+	}
+
+	if c.SNMPTrapAddr != "" && c.SNMPCommunity == "" && c.SNMPUser == "" {
+		return fmt.Errorf("snmp_community or snmp_user must be set with snmp_trap")
+	}
+
+	switch c.SchemaValidation {
+	case "", "reject", "tag":
+	default:
+		return fmt.Errorf("invalid schema_validation %v, must be reject or tag", c.SchemaValidation)
+	}
+
 	return nil
 
 }
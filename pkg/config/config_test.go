@@ -36,4 +36,16 @@ func TestConfig(t *testing.T) {
 	err = cfg.Validate()
 	require.NoError(t, err)
 
+	cfg.GRPCAddr = ":8881"
+	require.Error(t, cfg.Validate())
+
+	cfg.GRPCAddr = ":8880"
+	require.NoError(t, cfg.Validate())
+
+	cfg.SchemaValidation = "drop"
+	require.Error(t, cfg.Validate())
+
+	cfg.SchemaValidation = "tag"
+	require.NoError(t, cfg.Validate())
+
 }
@@ -0,0 +1,1891 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: cortex/v1/cortex.proto
+
+package cortexpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Event is a cloudevents.io v0.1 event
+type Event struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	EventType          string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	EventTypeVersion   string                 `protobuf:"bytes,2,opt,name=event_type_version,json=eventTypeVersion,proto3" json:"event_type_version,omitempty"`
+	CloudEventsVersion string                 `protobuf:"bytes,3,opt,name=cloud_events_version,json=cloudEventsVersion,proto3" json:"cloud_events_version,omitempty"`
+	Source             string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	EventId            string                 `protobuf:"bytes,5,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventTime          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`
+	SchemaUrl          string                 `protobuf:"bytes,7,opt,name=schema_url,json=schemaUrl,proto3" json:"schema_url,omitempty"`
+	ContentType        string                 `protobuf:"bytes,8,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Extensions         *structpb.Value        `protobuf:"bytes,9,opt,name=extensions,proto3" json:"extensions,omitempty"`
+	Data               *structpb.Value        `protobuf:"bytes,10,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *Event) GetEventTypeVersion() string {
+	if x != nil {
+		return x.EventTypeVersion
+	}
+	return ""
+}
+
+func (x *Event) GetCloudEventsVersion() string {
+	if x != nil {
+		return x.CloudEventsVersion
+	}
+	return ""
+}
+
+func (x *Event) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Event) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Event) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *Event) GetSchemaUrl() string {
+	if x != nil {
+		return x.SchemaUrl
+	}
+	return ""
+}
+
+func (x *Event) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Event) GetExtensions() *structpb.Value {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+func (x *Event) GetData() *structpb.Value {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type PublishEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventsRequest) Reset() {
+	*x = PublishEventsRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventsRequest) ProtoMessage() {}
+
+func (x *PublishEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventsRequest.ProtoReflect.Descriptor instead.
+func (*PublishEventsRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublishEventsRequest) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type PublishEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// one ack per event of the request, in the same order
+	Acks          []*EventAck `protobuf:"bytes,1,rep,name=acks,proto3" json:"acks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventsResponse) Reset() {
+	*x = PublishEventsResponse{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventsResponse) ProtoMessage() {}
+
+func (x *PublishEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventsResponse.ProtoReflect.Descriptor instead.
+func (*PublishEventsResponse) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PublishEventsResponse) GetAcks() []*EventAck {
+	if x != nil {
+		return x.Acks
+	}
+	return nil
+}
+
+// EventAck acknowledges an event once it is stashed in the buckets of the matching rules
+type EventAck struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	EventId      string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	MatchedRules []string               `protobuf:"bytes,2,rep,name=matched_rules,json=matchedRules,proto3" json:"matched_rules,omitempty"`
+	Buckets      []*StashResult         `protobuf:"bytes,3,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	// set if stashing the event for a rule failed
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventAck) Reset() {
+	*x = EventAck{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventAck) ProtoMessage() {}
+
+func (x *EventAck) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventAck.ProtoReflect.Descriptor instead.
+func (*EventAck) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EventAck) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *EventAck) GetMatchedRules() []string {
+	if x != nil {
+		return x.MatchedRules
+	}
+	return nil
+}
+
+func (x *EventAck) GetBuckets() []*StashResult {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *EventAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// StashResult is the outcome of stashing an event for a matching rule
+type StashResult struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	RuleId              string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Joined              bool                   `protobuf:"varint,2,opt,name=joined,proto3" json:"joined,omitempty"`
+	NewBucket           bool                   `protobuf:"varint,3,opt,name=new_bucket,json=newBucket,proto3" json:"new_bucket,omitempty"`
+	Deduplicated        bool                   `protobuf:"varint,4,opt,name=deduplicated,proto3" json:"deduplicated,omitempty"`
+	Late                bool                   `protobuf:"varint,5,opt,name=late,proto3" json:"late,omitempty"`
+	Reexecuted          bool                   `protobuf:"varint,6,opt,name=reexecuted,proto3" json:"reexecuted,omitempty"`
+	BucketCreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=bucket_created_at,json=bucketCreatedAt,proto3" json:"bucket_created_at,omitempty"`
+	ReexecutionDeferred bool                   `protobuf:"varint,8,opt,name=reexecution_deferred,json=reexecutionDeferred,proto3" json:"reexecution_deferred,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *StashResult) Reset() {
+	*x = StashResult{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StashResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StashResult) ProtoMessage() {}
+
+func (x *StashResult) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StashResult.ProtoReflect.Descriptor instead.
+func (*StashResult) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StashResult) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *StashResult) GetJoined() bool {
+	if x != nil {
+		return x.Joined
+	}
+	return false
+}
+
+func (x *StashResult) GetNewBucket() bool {
+	if x != nil {
+		return x.NewBucket
+	}
+	return false
+}
+
+func (x *StashResult) GetDeduplicated() bool {
+	if x != nil {
+		return x.Deduplicated
+	}
+	return false
+}
+
+func (x *StashResult) GetLate() bool {
+	if x != nil {
+		return x.Late
+	}
+	return false
+}
+
+func (x *StashResult) GetReexecuted() bool {
+	if x != nil {
+		return x.Reexecuted
+	}
+	return false
+}
+
+func (x *StashResult) GetBucketCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BucketCreatedAt
+	}
+	return nil
+}
+
+func (x *StashResult) GetReexecutionDeferred() bool {
+	if x != nil {
+		return x.ReexecutionDeferred
+	}
+	return false
+}
+
+// Rule mirrors the json rule of the rest api. Durations are in milliseconds
+type Rule struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Title                string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Id                   string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ScriptId             string                 `protobuf:"bytes,3,opt,name=script_id,json=scriptId,proto3" json:"script_id,omitempty"`
+	HookEndpoint         string                 `protobuf:"bytes,4,opt,name=hook_endpoint,json=hookEndpoint,proto3" json:"hook_endpoint,omitempty"`
+	HookRetry            int32                  `protobuf:"varint,5,opt,name=hook_retry,json=hookRetry,proto3" json:"hook_retry,omitempty"`
+	EventTypePatterns    []string               `protobuf:"bytes,6,rep,name=event_type_patterns,json=eventTypePatterns,proto3" json:"event_type_patterns,omitempty"`
+	Dwell                uint64                 `protobuf:"varint,7,opt,name=dwell,proto3" json:"dwell,omitempty"`
+	DwellDeadline        uint64                 `protobuf:"varint,8,opt,name=dwell_deadline,json=dwellDeadline,proto3" json:"dwell_deadline,omitempty"`
+	MaxDwell             uint64                 `protobuf:"varint,9,opt,name=max_dwell,json=maxDwell,proto3" json:"max_dwell,omitempty"`
+	Disabled             bool                   `protobuf:"varint,10,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	GroupKey             string                 `protobuf:"bytes,11,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	ResolvePatterns      []string               `protobuf:"bytes,12,rep,name=resolve_patterns,json=resolvePatterns,proto3" json:"resolve_patterns,omitempty"`
+	IncidentHookEndpoint string                 `protobuf:"bytes,13,opt,name=incident_hook_endpoint,json=incidentHookEndpoint,proto3" json:"incident_hook_endpoint,omitempty"`
+	Priority             int32                  `protobuf:"varint,14,opt,name=priority,proto3" json:"priority,omitempty"`
+	MaxConcurrency       int32                  `protobuf:"varint,15,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	WindowTime           string                 `protobuf:"bytes,16,opt,name=window_time,json=windowTime,proto3" json:"window_time,omitempty"`
+	AllowedLateness      uint64                 `protobuf:"varint,17,opt,name=allowed_lateness,json=allowedLateness,proto3" json:"allowed_lateness,omitempty"`
+	LatePolicy           string                 `protobuf:"bytes,18,opt,name=late_policy,json=latePolicy,proto3" json:"late_policy,omitempty"`
+	WindowType           string                 `protobuf:"bytes,19,opt,name=window_type,json=windowType,proto3" json:"window_type,omitempty"`
+	WindowSize           uint64                 `protobuf:"varint,20,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
+	WindowHop            uint64                 `protobuf:"varint,21,opt,name=window_hop,json=windowHop,proto3" json:"window_hop,omitempty"`
+	SessionGap           uint64                 `protobuf:"varint,22,opt,name=session_gap,json=sessionGap,proto3" json:"session_gap,omitempty"`
+	UpdatePolicy         string                 `protobuf:"bytes,23,opt,name=update_policy,json=updatePolicy,proto3" json:"update_policy,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Rule) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Rule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Rule) GetScriptId() string {
+	if x != nil {
+		return x.ScriptId
+	}
+	return ""
+}
+
+func (x *Rule) GetHookEndpoint() string {
+	if x != nil {
+		return x.HookEndpoint
+	}
+	return ""
+}
+
+func (x *Rule) GetHookRetry() int32 {
+	if x != nil {
+		return x.HookRetry
+	}
+	return 0
+}
+
+func (x *Rule) GetEventTypePatterns() []string {
+	if x != nil {
+		return x.EventTypePatterns
+	}
+	return nil
+}
+
+func (x *Rule) GetDwell() uint64 {
+	if x != nil {
+		return x.Dwell
+	}
+	return 0
+}
+
+func (x *Rule) GetDwellDeadline() uint64 {
+	if x != nil {
+		return x.DwellDeadline
+	}
+	return 0
+}
+
+func (x *Rule) GetMaxDwell() uint64 {
+	if x != nil {
+		return x.MaxDwell
+	}
+	return 0
+}
+
+func (x *Rule) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *Rule) GetGroupKey() string {
+	if x != nil {
+		return x.GroupKey
+	}
+	return ""
+}
+
+func (x *Rule) GetResolvePatterns() []string {
+	if x != nil {
+		return x.ResolvePatterns
+	}
+	return nil
+}
+
+func (x *Rule) GetIncidentHookEndpoint() string {
+	if x != nil {
+		return x.IncidentHookEndpoint
+	}
+	return ""
+}
+
+func (x *Rule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Rule) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *Rule) GetWindowTime() string {
+	if x != nil {
+		return x.WindowTime
+	}
+	return ""
+}
+
+func (x *Rule) GetAllowedLateness() uint64 {
+	if x != nil {
+		return x.AllowedLateness
+	}
+	return 0
+}
+
+func (x *Rule) GetLatePolicy() string {
+	if x != nil {
+		return x.LatePolicy
+	}
+	return ""
+}
+
+func (x *Rule) GetWindowType() string {
+	if x != nil {
+		return x.WindowType
+	}
+	return ""
+}
+
+func (x *Rule) GetWindowSize() uint64 {
+	if x != nil {
+		return x.WindowSize
+	}
+	return 0
+}
+
+func (x *Rule) GetWindowHop() uint64 {
+	if x != nil {
+		return x.WindowHop
+	}
+	return 0
+}
+
+func (x *Rule) GetSessionGap() uint64 {
+	if x != nil {
+		return x.SessionGap
+	}
+	return 0
+}
+
+func (x *Rule) GetUpdatePolicy() string {
+	if x != nil {
+		return x.UpdatePolicy
+	}
+	return ""
+}
+
+type GetRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRulesRequest) Reset() {
+	*x = GetRulesRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRulesRequest) ProtoMessage() {}
+
+func (x *GetRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRulesRequest.ProtoReflect.Descriptor instead.
+func (*GetRulesRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{6}
+}
+
+type GetRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*Rule                `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRulesResponse) Reset() {
+	*x = GetRulesResponse{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRulesResponse) ProtoMessage() {}
+
+func (x *GetRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRulesResponse.ProtoReflect.Descriptor instead.
+func (*GetRulesResponse) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRulesResponse) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type GetRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuleRequest) Reset() {
+	*x = GetRuleRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuleRequest) ProtoMessage() {}
+
+func (x *GetRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuleRequest.ProtoReflect.Descriptor instead.
+func (*GetRuleRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetRuleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RemoveRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRuleRequest) Reset() {
+	*x = RemoveRuleRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRuleRequest) ProtoMessage() {}
+
+func (x *RemoveRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRuleRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRuleRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RemoveRuleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Script struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Script) Reset() {
+	*x = Script{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Script) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Script) ProtoMessage() {}
+
+func (x *Script) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Script.ProtoReflect.Descriptor instead.
+func (*Script) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Script) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Script) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetScriptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScriptsRequest) Reset() {
+	*x = GetScriptsRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScriptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScriptsRequest) ProtoMessage() {}
+
+func (x *GetScriptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScriptsRequest.ProtoReflect.Descriptor instead.
+func (*GetScriptsRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{11}
+}
+
+type GetScriptsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScriptsResponse) Reset() {
+	*x = GetScriptsResponse{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScriptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScriptsResponse) ProtoMessage() {}
+
+func (x *GetScriptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScriptsResponse.ProtoReflect.Descriptor instead.
+func (*GetScriptsResponse) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetScriptsResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetScriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScriptRequest) Reset() {
+	*x = GetScriptRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScriptRequest) ProtoMessage() {}
+
+func (x *GetScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScriptRequest.ProtoReflect.Descriptor instead.
+func (*GetScriptRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetScriptRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RemoveScriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveScriptRequest) Reset() {
+	*x = RemoveScriptRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveScriptRequest) ProtoMessage() {}
+
+func (x *RemoveScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveScriptRequest.ProtoReflect.Descriptor instead.
+func (*RemoveScriptRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RemoveScriptRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Bucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *Rule                  `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Events        []*Event               `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	FlushLock     bool                   `protobuf:"varint,3,opt,name=flush_lock,json=flushLock,proto3" json:"flush_lock,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LateEvents    int32                  `protobuf:"varint,6,opt,name=late_events,json=lateEvents,proto3" json:"late_events,omitempty"`
+	Extension     uint64                 `protobuf:"varint,7,opt,name=extension,proto3" json:"extension,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bucket) Reset() {
+	*x = Bucket{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket) ProtoMessage() {}
+
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Bucket) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *Bucket) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Bucket) GetFlushLock() bool {
+	if x != nil {
+		return x.FlushLock
+	}
+	return false
+}
+
+func (x *Bucket) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Bucket) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Bucket) GetLateEvents() int32 {
+	if x != nil {
+		return x.LateEvents
+	}
+	return 0
+}
+
+func (x *Bucket) GetExtension() uint64 {
+	if x != nil {
+		return x.Extension
+	}
+	return 0
+}
+
+type ExecutionRecord struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bucket         *Bucket                `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	ScriptResult   *structpb.Value        `protobuf:"bytes,3,opt,name=script_result,json=scriptResult,proto3" json:"script_result,omitempty"`
+	ScriptStatus   string                 `protobuf:"bytes,4,opt,name=script_status,json=scriptStatus,proto3" json:"script_status,omitempty"`
+	HookStatusCode int32                  `protobuf:"varint,5,opt,name=hook_status_code,json=hookStatusCode,proto3" json:"hook_status_code,omitempty"`
+	LateEvents     int32                  `protobuf:"varint,6,opt,name=late_events,json=lateEvents,proto3" json:"late_events,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExecutionRecord) Reset() {
+	*x = ExecutionRecord{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionRecord) ProtoMessage() {}
+
+func (x *ExecutionRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionRecord.ProtoReflect.Descriptor instead.
+func (*ExecutionRecord) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExecutionRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExecutionRecord) GetBucket() *Bucket {
+	if x != nil {
+		return x.Bucket
+	}
+	return nil
+}
+
+func (x *ExecutionRecord) GetScriptResult() *structpb.Value {
+	if x != nil {
+		return x.ScriptResult
+	}
+	return nil
+}
+
+func (x *ExecutionRecord) GetScriptStatus() string {
+	if x != nil {
+		return x.ScriptStatus
+	}
+	return ""
+}
+
+func (x *ExecutionRecord) GetHookStatusCode() int32 {
+	if x != nil {
+		return x.HookStatusCode
+	}
+	return 0
+}
+
+func (x *ExecutionRecord) GetLateEvents() int32 {
+	if x != nil {
+		return x.LateEvents
+	}
+	return 0
+}
+
+func (x *ExecutionRecord) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// GetRuleExecutionsRequest filters and paginates the executions of a rule
+type GetRuleExecutionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	RuleId string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	// inclusive, unset is unbounded
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// inclusive, unset is unbounded
+	To *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// 0 matches all
+	HookStatusCode int32 `protobuf:"varint,4,opt,name=hook_status_code,json=hookStatusCode,proto3" json:"hook_status_code,omitempty"`
+	// none, ok or error. empty matches all
+	ScriptStatus string `protobuf:"bytes,5,opt,name=script_status,json=scriptStatus,proto3" json:"script_status,omitempty"`
+	// asc or desc by created_at. defaults to desc
+	Order string `protobuf:"bytes,6,opt,name=order,proto3" json:"order,omitempty"`
+	Limit int32  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page
+	Cursor        string `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuleExecutionsRequest) Reset() {
+	*x = GetRuleExecutionsRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuleExecutionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuleExecutionsRequest) ProtoMessage() {}
+
+func (x *GetRuleExecutionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuleExecutionsRequest.ProtoReflect.Descriptor instead.
+func (*GetRuleExecutionsRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetRuleExecutionsRequest) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *GetRuleExecutionsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetRuleExecutionsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetRuleExecutionsRequest) GetHookStatusCode() int32 {
+	if x != nil {
+		return x.HookStatusCode
+	}
+	return 0
+}
+
+func (x *GetRuleExecutionsRequest) GetScriptStatus() string {
+	if x != nil {
+		return x.ScriptStatus
+	}
+	return ""
+}
+
+func (x *GetRuleExecutionsRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *GetRuleExecutionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetRuleExecutionsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetRuleExecutionsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Records []*ExecutionRecord     `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// empty if there are no more records
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRuleExecutionsResponse) Reset() {
+	*x = GetRuleExecutionsResponse{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRuleExecutionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRuleExecutionsResponse) ProtoMessage() {}
+
+func (x *GetRuleExecutionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRuleExecutionsResponse.ProtoReflect.Descriptor instead.
+func (*GetRuleExecutionsResponse) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetRuleExecutionsResponse) GetRecords() []*ExecutionRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *GetRuleExecutionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetExecutionQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionQueueRequest) Reset() {
+	*x = GetExecutionQueueRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionQueueRequest) ProtoMessage() {}
+
+func (x *GetExecutionQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionQueueRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutionQueueRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{19}
+}
+
+type ExecutionQueue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       int32                  `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
+	QueueCapacity int32                  `protobuf:"varint,2,opt,name=queue_capacity,json=queueCapacity,proto3" json:"queue_capacity,omitempty"`
+	Queued        int32                  `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`
+	Running       int32                  `protobuf:"varint,4,opt,name=running,proto3" json:"running,omitempty"`
+	Executed      uint64                 `protobuf:"varint,5,opt,name=executed,proto3" json:"executed,omitempty"`
+	Deferred      uint64                 `protobuf:"varint,6,opt,name=deferred,proto3" json:"deferred,omitempty"`
+	Backpressure  bool                   `protobuf:"varint,7,opt,name=backpressure,proto3" json:"backpressure,omitempty"`
+	QueuedByRule  map[string]int32       `protobuf:"bytes,8,rep,name=queued_by_rule,json=queuedByRule,proto3" json:"queued_by_rule,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	RunningByRule map[string]int32       `protobuf:"bytes,9,rep,name=running_by_rule,json=runningByRule,proto3" json:"running_by_rule,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecutionQueue) Reset() {
+	*x = ExecutionQueue{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionQueue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionQueue) ProtoMessage() {}
+
+func (x *ExecutionQueue) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionQueue.ProtoReflect.Descriptor instead.
+func (*ExecutionQueue) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExecutionQueue) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *ExecutionQueue) GetQueueCapacity() int32 {
+	if x != nil {
+		return x.QueueCapacity
+	}
+	return 0
+}
+
+func (x *ExecutionQueue) GetQueued() int32 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *ExecutionQueue) GetRunning() int32 {
+	if x != nil {
+		return x.Running
+	}
+	return 0
+}
+
+func (x *ExecutionQueue) GetExecuted() uint64 {
+	if x != nil {
+		return x.Executed
+	}
+	return 0
+}
+
+func (x *ExecutionQueue) GetDeferred() uint64 {
+	if x != nil {
+		return x.Deferred
+	}
+	return 0
+}
+
+func (x *ExecutionQueue) GetBackpressure() bool {
+	if x != nil {
+		return x.Backpressure
+	}
+	return false
+}
+
+func (x *ExecutionQueue) GetQueuedByRule() map[string]int32 {
+	if x != nil {
+		return x.QueuedByRule
+	}
+	return nil
+}
+
+func (x *ExecutionQueue) GetRunningByRule() map[string]int32 {
+	if x != nil {
+		return x.RunningByRule
+	}
+	return nil
+}
+
+type GetRetentionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRetentionRequest) Reset() {
+	*x = GetRetentionRequest{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetentionRequest) ProtoMessage() {}
+
+func (x *GetRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetentionRequest.ProtoReflect.Descriptor instead.
+func (*GetRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{21}
+}
+
+type Retention struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MaxRecords        int32                  `protobuf:"varint,1,opt,name=max_records,json=maxRecords,proto3" json:"max_records,omitempty"`
+	MaxRecordsPerRule int32                  `protobuf:"varint,2,opt,name=max_records_per_rule,json=maxRecordsPerRule,proto3" json:"max_records_per_rule,omitempty"`
+	// milliseconds
+	MaxAge        uint64 `protobuf:"varint,3,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Retention) Reset() {
+	*x = Retention{}
+	mi := &file_cortex_v1_cortex_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Retention) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Retention) ProtoMessage() {}
+
+func (x *Retention) ProtoReflect() protoreflect.Message {
+	mi := &file_cortex_v1_cortex_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Retention.ProtoReflect.Descriptor instead.
+func (*Retention) Descriptor() ([]byte, []int) {
+	return file_cortex_v1_cortex_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Retention) GetMaxRecords() int32 {
+	if x != nil {
+		return x.MaxRecords
+	}
+	return 0
+}
+
+func (x *Retention) GetMaxRecordsPerRule() int32 {
+	if x != nil {
+		return x.MaxRecordsPerRule
+	}
+	return 0
+}
+
+func (x *Retention) GetMaxAge() uint64 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
+}
+
+var File_cortex_v1_cortex_proto protoreflect.FileDescriptor
+
+const file_cortex_v1_cortex_proto_rawDesc = "" +
+	"\n" +
+	"\x16cortex/v1/cortex.proto\x12\tcortex.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9a\x03\n" +
+	"\x05Event\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12,\n" +
+	"\x12event_type_version\x18\x02 \x01(\tR\x10eventTypeVersion\x120\n" +
+	"\x14cloud_events_version\x18\x03 \x01(\tR\x12cloudEventsVersion\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x19\n" +
+	"\bevent_id\x18\x05 \x01(\tR\aeventId\x129\n" +
+	"\n" +
+	"event_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\teventTime\x12\x1d\n" +
+	"\n" +
+	"schema_url\x18\a \x01(\tR\tschemaUrl\x12!\n" +
+	"\fcontent_type\x18\b \x01(\tR\vcontentType\x126\n" +
+	"\n" +
+	"extensions\x18\t \x01(\v2\x16.google.protobuf.ValueR\n" +
+	"extensions\x12*\n" +
+	"\x04data\x18\n" +
+	" \x01(\v2\x16.google.protobuf.ValueR\x04data\"@\n" +
+	"\x14PublishEventsRequest\x12(\n" +
+	"\x06events\x18\x01 \x03(\v2\x10.cortex.v1.EventR\x06events\"@\n" +
+	"\x15PublishEventsResponse\x12'\n" +
+	"\x04acks\x18\x01 \x03(\v2\x13.cortex.v1.EventAckR\x04acks\"\x92\x01\n" +
+	"\bEventAck\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12#\n" +
+	"\rmatched_rules\x18\x02 \x03(\tR\fmatchedRules\x120\n" +
+	"\abuckets\x18\x03 \x03(\v2\x16.cortex.v1.StashResultR\abuckets\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xb0\x02\n" +
+	"\vStashResult\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12\x16\n" +
+	"\x06joined\x18\x02 \x01(\bR\x06joined\x12\x1d\n" +
+	"\n" +
+	"new_bucket\x18\x03 \x01(\bR\tnewBucket\x12\"\n" +
+	"\fdeduplicated\x18\x04 \x01(\bR\fdeduplicated\x12\x12\n" +
+	"\x04late\x18\x05 \x01(\bR\x04late\x12\x1e\n" +
+	"\n" +
+	"reexecuted\x18\x06 \x01(\bR\n" +
+	"reexecuted\x12F\n" +
+	"\x11bucket_created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0fbucketCreatedAt\x121\n" +
+	"\x14reexecution_deferred\x18\b \x01(\bR\x13reexecutionDeferred\"\x8a\x06\n" +
+	"\x04Rule\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1b\n" +
+	"\tscript_id\x18\x03 \x01(\tR\bscriptId\x12#\n" +
+	"\rhook_endpoint\x18\x04 \x01(\tR\fhookEndpoint\x12\x1d\n" +
+	"\n" +
+	"hook_retry\x18\x05 \x01(\x05R\thookRetry\x12.\n" +
+	"\x13event_type_patterns\x18\x06 \x03(\tR\x11eventTypePatterns\x12\x14\n" +
+	"\x05dwell\x18\a \x01(\x04R\x05dwell\x12%\n" +
+	"\x0edwell_deadline\x18\b \x01(\x04R\rdwellDeadline\x12\x1b\n" +
+	"\tmax_dwell\x18\t \x01(\x04R\bmaxDwell\x12\x1a\n" +
+	"\bdisabled\x18\n" +
+	" \x01(\bR\bdisabled\x12\x1b\n" +
+	"\tgroup_key\x18\v \x01(\tR\bgroupKey\x12)\n" +
+	"\x10resolve_patterns\x18\f \x03(\tR\x0fresolvePatterns\x124\n" +
+	"\x16incident_hook_endpoint\x18\r \x01(\tR\x14incidentHookEndpoint\x12\x1a\n" +
+	"\bpriority\x18\x0e \x01(\x05R\bpriority\x12'\n" +
+	"\x0fmax_concurrency\x18\x0f \x01(\x05R\x0emaxConcurrency\x12\x1f\n" +
+	"\vwindow_time\x18\x10 \x01(\tR\n" +
+	"windowTime\x12)\n" +
+	"\x10allowed_lateness\x18\x11 \x01(\x04R\x0fallowedLateness\x12\x1f\n" +
+	"\vlate_policy\x18\x12 \x01(\tR\n" +
+	"latePolicy\x12\x1f\n" +
+	"\vwindow_type\x18\x13 \x01(\tR\n" +
+	"windowType\x12\x1f\n" +
+	"\vwindow_size\x18\x14 \x01(\x04R\n" +
+	"windowSize\x12\x1d\n" +
+	"\n" +
+	"window_hop\x18\x15 \x01(\x04R\twindowHop\x12\x1f\n" +
+	"\vsession_gap\x18\x16 \x01(\x04R\n" +
+	"sessionGap\x12#\n" +
+	"\rupdate_policy\x18\x17 \x01(\tR\fupdatePolicy\"\x11\n" +
+	"\x0fGetRulesRequest\"9\n" +
+	"\x10GetRulesResponse\x12%\n" +
+	"\x05rules\x18\x01 \x03(\v2\x0f.cortex.v1.RuleR\x05rules\" \n" +
+	"\x0eGetRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"#\n" +
+	"\x11RemoveRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\x06Script\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\x13\n" +
+	"\x11GetScriptsRequest\"&\n" +
+	"\x12GetScriptsResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\"\n" +
+	"\x10GetScriptRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"%\n" +
+	"\x13RemoveScriptRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xab\x02\n" +
+	"\x06Bucket\x12#\n" +
+	"\x04rule\x18\x01 \x01(\v2\x0f.cortex.v1.RuleR\x04rule\x12(\n" +
+	"\x06events\x18\x02 \x03(\v2\x10.cortex.v1.EventR\x06events\x12\x1d\n" +
+	"\n" +
+	"flush_lock\x18\x03 \x01(\bR\tflushLock\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1f\n" +
+	"\vlate_events\x18\x06 \x01(\x05R\n" +
+	"lateEvents\x12\x1c\n" +
+	"\textension\x18\a \x01(\x04R\textension\"\xb4\x02\n" +
+	"\x0fExecutionRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x06bucket\x18\x02 \x01(\v2\x11.cortex.v1.BucketR\x06bucket\x12;\n" +
+	"\rscript_result\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\fscriptResult\x12#\n" +
+	"\rscript_status\x18\x04 \x01(\tR\fscriptStatus\x12(\n" +
+	"\x10hook_status_code\x18\x05 \x01(\x05R\x0ehookStatusCode\x12\x1f\n" +
+	"\vlate_events\x18\x06 \x01(\x05R\n" +
+	"lateEvents\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa2\x02\n" +
+	"\x18GetRuleExecutionsRequest\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12(\n" +
+	"\x10hook_status_code\x18\x04 \x01(\x05R\x0ehookStatusCode\x12#\n" +
+	"\rscript_status\x18\x05 \x01(\tR\fscriptStatus\x12\x14\n" +
+	"\x05order\x18\x06 \x01(\tR\x05order\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\b \x01(\tR\x06cursor\"r\n" +
+	"\x19GetRuleExecutionsResponse\x124\n" +
+	"\arecords\x18\x01 \x03(\v2\x1a.cortex.v1.ExecutionRecordR\arecords\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x1a\n" +
+	"\x18GetExecutionQueueRequest\"\x8b\x04\n" +
+	"\x0eExecutionQueue\x12\x18\n" +
+	"\aworkers\x18\x01 \x01(\x05R\aworkers\x12%\n" +
+	"\x0equeue_capacity\x18\x02 \x01(\x05R\rqueueCapacity\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\x05R\x06queued\x12\x18\n" +
+	"\arunning\x18\x04 \x01(\x05R\arunning\x12\x1a\n" +
+	"\bexecuted\x18\x05 \x01(\x04R\bexecuted\x12\x1a\n" +
+	"\bdeferred\x18\x06 \x01(\x04R\bdeferred\x12\"\n" +
+	"\fbackpressure\x18\a \x01(\bR\fbackpressure\x12Q\n" +
+	"\x0equeued_by_rule\x18\b \x03(\v2+.cortex.v1.ExecutionQueue.QueuedByRuleEntryR\fqueuedByRule\x12T\n" +
+	"\x0frunning_by_rule\x18\t \x03(\v2,.cortex.v1.ExecutionQueue.RunningByRuleEntryR\rrunningByRule\x1a?\n" +
+	"\x11QueuedByRuleEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a@\n" +
+	"\x12RunningByRuleEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x15\n" +
+	"\x13GetRetentionRequest\"v\n" +
+	"\tRetention\x12\x1f\n" +
+	"\vmax_records\x18\x01 \x01(\x05R\n" +
+	"maxRecords\x12/\n" +
+	"\x14max_records_per_rule\x18\x02 \x01(\x05R\x11maxRecordsPerRule\x12\x17\n" +
+	"\amax_age\x18\x03 \x01(\x04R\x06maxAge2\xfa\a\n" +
+	"\x06Cortex\x12V\n" +
+	"\rPublishEvents\x12\x1f.cortex.v1.PublishEventsRequest\x1a .cortex.v1.PublishEventsResponse(\x010\x01\x12C\n" +
+	"\bGetRules\x12\x1a.cortex.v1.GetRulesRequest\x1a\x1b.cortex.v1.GetRulesResponse\x125\n" +
+	"\aGetRule\x12\x19.cortex.v1.GetRuleRequest\x1a\x0f.cortex.v1.Rule\x12+\n" +
+	"\aAddRule\x12\x0f.cortex.v1.Rule\x1a\x0f.cortex.v1.Rule\x12.\n" +
+	"\n" +
+	"UpdateRule\x12\x0f.cortex.v1.Rule\x1a\x0f.cortex.v1.Rule\x12B\n" +
+	"\n" +
+	"RemoveRule\x12\x1c.cortex.v1.RemoveRuleRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
+	"\n" +
+	"GetScripts\x12\x1c.cortex.v1.GetScriptsRequest\x1a\x1d.cortex.v1.GetScriptsResponse\x12;\n" +
+	"\tGetScript\x12\x1b.cortex.v1.GetScriptRequest\x1a\x11.cortex.v1.Script\x126\n" +
+	"\tAddScript\x12\x11.cortex.v1.Script\x1a\x16.google.protobuf.Empty\x129\n" +
+	"\fUpdateScript\x12\x11.cortex.v1.Script\x1a\x16.google.protobuf.Empty\x12F\n" +
+	"\fRemoveScript\x12\x1e.cortex.v1.RemoveScriptRequest\x1a\x16.google.protobuf.Empty\x12^\n" +
+	"\x11GetRuleExecutions\x12#.cortex.v1.GetRuleExecutionsRequest\x1a$.cortex.v1.GetRuleExecutionsResponse\x12S\n" +
+	"\x11GetExecutionQueue\x12#.cortex.v1.GetExecutionQueueRequest\x1a\x19.cortex.v1.ExecutionQueue\x12D\n" +
+	"\fGetRetention\x12\x1e.cortex.v1.GetRetentionRequest\x1a\x14.cortex.v1.Retention\x12=\n" +
+	"\x0fUpdateRetention\x12\x14.cortex.v1.Retention\x1a\x14.cortex.v1.RetentionB'Z%github.com/myntra/cortex/pkg/cortexpbb\x06proto3"
+
+var (
+	file_cortex_v1_cortex_proto_rawDescOnce sync.Once
+	file_cortex_v1_cortex_proto_rawDescData []byte
+)
+
+func file_cortex_v1_cortex_proto_rawDescGZIP() []byte {
+	file_cortex_v1_cortex_proto_rawDescOnce.Do(func() {
+		file_cortex_v1_cortex_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cortex_v1_cortex_proto_rawDesc), len(file_cortex_v1_cortex_proto_rawDesc)))
+	})
+	return file_cortex_v1_cortex_proto_rawDescData
+}
+
+var file_cortex_v1_cortex_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_cortex_v1_cortex_proto_goTypes = []any{
+	(*Event)(nil),                     // 0: cortex.v1.Event
+	(*PublishEventsRequest)(nil),      // 1: cortex.v1.PublishEventsRequest
+	(*PublishEventsResponse)(nil),     // 2: cortex.v1.PublishEventsResponse
+	(*EventAck)(nil),                  // 3: cortex.v1.EventAck
+	(*StashResult)(nil),               // 4: cortex.v1.StashResult
+	(*Rule)(nil),                      // 5: cortex.v1.Rule
+	(*GetRulesRequest)(nil),           // 6: cortex.v1.GetRulesRequest
+	(*GetRulesResponse)(nil),          // 7: cortex.v1.GetRulesResponse
+	(*GetRuleRequest)(nil),            // 8: cortex.v1.GetRuleRequest
+	(*RemoveRuleRequest)(nil),         // 9: cortex.v1.RemoveRuleRequest
+	(*Script)(nil),                    // 10: cortex.v1.Script
+	(*GetScriptsRequest)(nil),         // 11: cortex.v1.GetScriptsRequest
+	(*GetScriptsResponse)(nil),        // 12: cortex.v1.GetScriptsResponse
+	(*GetScriptRequest)(nil),          // 13: cortex.v1.GetScriptRequest
+	(*RemoveScriptRequest)(nil),       // 14: cortex.v1.RemoveScriptRequest
+	(*Bucket)(nil),                    // 15: cortex.v1.Bucket
+	(*ExecutionRecord)(nil),           // 16: cortex.v1.ExecutionRecord
+	(*GetRuleExecutionsRequest)(nil),  // 17: cortex.v1.GetRuleExecutionsRequest
+	(*GetRuleExecutionsResponse)(nil), // 18: cortex.v1.GetRuleExecutionsResponse
+	(*GetExecutionQueueRequest)(nil),  // 19: cortex.v1.GetExecutionQueueRequest
+	(*ExecutionQueue)(nil),            // 20: cortex.v1.ExecutionQueue
+	(*GetRetentionRequest)(nil),       // 21: cortex.v1.GetRetentionRequest
+	(*Retention)(nil),                 // 22: cortex.v1.Retention
+	nil,                               // 23: cortex.v1.ExecutionQueue.QueuedByRuleEntry
+	nil,                               // 24: cortex.v1.ExecutionQueue.RunningByRuleEntry
+	(*timestamppb.Timestamp)(nil),     // 25: google.protobuf.Timestamp
+	(*structpb.Value)(nil),            // 26: google.protobuf.Value
+	(*emptypb.Empty)(nil),             // 27: google.protobuf.Empty
+}
+var file_cortex_v1_cortex_proto_depIdxs = []int32{
+	25, // 0: cortex.v1.Event.event_time:type_name -> google.protobuf.Timestamp
+	26, // 1: cortex.v1.Event.extensions:type_name -> google.protobuf.Value
+	26, // 2: cortex.v1.Event.data:type_name -> google.protobuf.Value
+	0,  // 3: cortex.v1.PublishEventsRequest.events:type_name -> cortex.v1.Event
+	3,  // 4: cortex.v1.PublishEventsResponse.acks:type_name -> cortex.v1.EventAck
+	4,  // 5: cortex.v1.EventAck.buckets:type_name -> cortex.v1.StashResult
+	25, // 6: cortex.v1.StashResult.bucket_created_at:type_name -> google.protobuf.Timestamp
+	5,  // 7: cortex.v1.GetRulesResponse.rules:type_name -> cortex.v1.Rule
+	5,  // 8: cortex.v1.Bucket.rule:type_name -> cortex.v1.Rule
+	0,  // 9: cortex.v1.Bucket.events:type_name -> cortex.v1.Event
+	25, // 10: cortex.v1.Bucket.updated_at:type_name -> google.protobuf.Timestamp
+	25, // 11: cortex.v1.Bucket.created_at:type_name -> google.protobuf.Timestamp
+	15, // 12: cortex.v1.ExecutionRecord.bucket:type_name -> cortex.v1.Bucket
+	26, // 13: cortex.v1.ExecutionRecord.script_result:type_name -> google.protobuf.Value
+	25, // 14: cortex.v1.ExecutionRecord.created_at:type_name -> google.protobuf.Timestamp
+	25, // 15: cortex.v1.GetRuleExecutionsRequest.from:type_name -> google.protobuf.Timestamp
+	25, // 16: cortex.v1.GetRuleExecutionsRequest.to:type_name -> google.protobuf.Timestamp
+	16, // 17: cortex.v1.GetRuleExecutionsResponse.records:type_name -> cortex.v1.ExecutionRecord
+	23, // 18: cortex.v1.ExecutionQueue.queued_by_rule:type_name -> cortex.v1.ExecutionQueue.QueuedByRuleEntry
+	24, // 19: cortex.v1.ExecutionQueue.running_by_rule:type_name -> cortex.v1.ExecutionQueue.RunningByRuleEntry
+	1,  // 20: cortex.v1.Cortex.PublishEvents:input_type -> cortex.v1.PublishEventsRequest
+	6,  // 21: cortex.v1.Cortex.GetRules:input_type -> cortex.v1.GetRulesRequest
+	8,  // 22: cortex.v1.Cortex.GetRule:input_type -> cortex.v1.GetRuleRequest
+	5,  // 23: cortex.v1.Cortex.AddRule:input_type -> cortex.v1.Rule
+	5,  // 24: cortex.v1.Cortex.UpdateRule:input_type -> cortex.v1.Rule
+	9,  // 25: cortex.v1.Cortex.RemoveRule:input_type -> cortex.v1.RemoveRuleRequest
+	11, // 26: cortex.v1.Cortex.GetScripts:input_type -> cortex.v1.GetScriptsRequest
+	13, // 27: cortex.v1.Cortex.GetScript:input_type -> cortex.v1.GetScriptRequest
+	10, // 28: cortex.v1.Cortex.AddScript:input_type -> cortex.v1.Script
+	10, // 29: cortex.v1.Cortex.UpdateScript:input_type -> cortex.v1.Script
+	14, // 30: cortex.v1.Cortex.RemoveScript:input_type -> cortex.v1.RemoveScriptRequest
+	17, // 31: cortex.v1.Cortex.GetRuleExecutions:input_type -> cortex.v1.GetRuleExecutionsRequest
+	19, // 32: cortex.v1.Cortex.GetExecutionQueue:input_type -> cortex.v1.GetExecutionQueueRequest
+	21, // 33: cortex.v1.Cortex.GetRetention:input_type -> cortex.v1.GetRetentionRequest
+	22, // 34: cortex.v1.Cortex.UpdateRetention:input_type -> cortex.v1.Retention
+	2,  // 35: cortex.v1.Cortex.PublishEvents:output_type -> cortex.v1.PublishEventsResponse
+	7,  // 36: cortex.v1.Cortex.GetRules:output_type -> cortex.v1.GetRulesResponse
+	5,  // 37: cortex.v1.Cortex.GetRule:output_type -> cortex.v1.Rule
+	5,  // 38: cortex.v1.Cortex.AddRule:output_type -> cortex.v1.Rule
+	5,  // 39: cortex.v1.Cortex.UpdateRule:output_type -> cortex.v1.Rule
+	27, // 40: cortex.v1.Cortex.RemoveRule:output_type -> google.protobuf.Empty
+	12, // 41: cortex.v1.Cortex.GetScripts:output_type -> cortex.v1.GetScriptsResponse
+	10, // 42: cortex.v1.Cortex.GetScript:output_type -> cortex.v1.Script
+	27, // 43: cortex.v1.Cortex.AddScript:output_type -> google.protobuf.Empty
+	27, // 44: cortex.v1.Cortex.UpdateScript:output_type -> google.protobuf.Empty
+	27, // 45: cortex.v1.Cortex.RemoveScript:output_type -> google.protobuf.Empty
+	18, // 46: cortex.v1.Cortex.GetRuleExecutions:output_type -> cortex.v1.GetRuleExecutionsResponse
+	20, // 47: cortex.v1.Cortex.GetExecutionQueue:output_type -> cortex.v1.ExecutionQueue
+	22, // 48: cortex.v1.Cortex.GetRetention:output_type -> cortex.v1.Retention
+	22, // 49: cortex.v1.Cortex.UpdateRetention:output_type -> cortex.v1.Retention
+	35, // [35:50] is the sub-list for method output_type
+	20, // [20:35] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_cortex_v1_cortex_proto_init() }
+func file_cortex_v1_cortex_proto_init() {
+	if File_cortex_v1_cortex_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cortex_v1_cortex_proto_rawDesc), len(file_cortex_v1_cortex_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cortex_v1_cortex_proto_goTypes,
+		DependencyIndexes: file_cortex_v1_cortex_proto_depIdxs,
+		MessageInfos:      file_cortex_v1_cortex_proto_msgTypes,
+	}.Build()
+	File_cortex_v1_cortex_proto = out.File
+	file_cortex_v1_cortex_proto_goTypes = nil
+	file_cortex_v1_cortex_proto_depIdxs = nil
+}
@@ -0,0 +1,669 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: cortex/v1/cortex.proto
+
+package cortexpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Cortex_PublishEvents_FullMethodName     = "/cortex.v1.Cortex/PublishEvents"
+	Cortex_GetRules_FullMethodName          = "/cortex.v1.Cortex/GetRules"
+	Cortex_GetRule_FullMethodName           = "/cortex.v1.Cortex/GetRule"
+	Cortex_AddRule_FullMethodName           = "/cortex.v1.Cortex/AddRule"
+	Cortex_UpdateRule_FullMethodName        = "/cortex.v1.Cortex/UpdateRule"
+	Cortex_RemoveRule_FullMethodName        = "/cortex.v1.Cortex/RemoveRule"
+	Cortex_GetScripts_FullMethodName        = "/cortex.v1.Cortex/GetScripts"
+	Cortex_GetScript_FullMethodName         = "/cortex.v1.Cortex/GetScript"
+	Cortex_AddScript_FullMethodName         = "/cortex.v1.Cortex/AddScript"
+	Cortex_UpdateScript_FullMethodName      = "/cortex.v1.Cortex/UpdateScript"
+	Cortex_RemoveScript_FullMethodName      = "/cortex.v1.Cortex/RemoveScript"
+	Cortex_GetRuleExecutions_FullMethodName = "/cortex.v1.Cortex/GetRuleExecutions"
+	Cortex_GetExecutionQueue_FullMethodName = "/cortex.v1.Cortex/GetExecutionQueue"
+	Cortex_GetRetention_FullMethodName      = "/cortex.v1.Cortex/GetRetention"
+	Cortex_UpdateRetention_FullMethodName   = "/cortex.v1.Cortex/UpdateRetention"
+)
+
+// CortexClient is the client API for Cortex service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CortexClient interface {
+	// PublishEvents ingests batches of events. Each request is answered by a response acknowledging its
+	// events in order, once they are stashed in the buckets of the matching rules.
+	PublishEvents(ctx context.Context, opts ...grpc.CallOption) (Cortex_PublishEventsClient, error)
+	GetRules(ctx context.Context, in *GetRulesRequest, opts ...grpc.CallOption) (*GetRulesResponse, error)
+	GetRule(ctx context.Context, in *GetRuleRequest, opts ...grpc.CallOption) (*Rule, error)
+	// AddRule generates the rule id if it is empty
+	AddRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*Rule, error)
+	// UpdateRule merges the empty fields of the rule with the existing rule
+	UpdateRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*Rule, error)
+	RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetScripts(ctx context.Context, in *GetScriptsRequest, opts ...grpc.CallOption) (*GetScriptsResponse, error)
+	GetScript(ctx context.Context, in *GetScriptRequest, opts ...grpc.CallOption) (*Script, error)
+	AddScript(ctx context.Context, in *Script, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateScript(ctx context.Context, in *Script, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveScript(ctx context.Context, in *RemoveScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetRuleExecutions(ctx context.Context, in *GetRuleExecutionsRequest, opts ...grpc.CallOption) (*GetRuleExecutionsResponse, error)
+	GetExecutionQueue(ctx context.Context, in *GetExecutionQueueRequest, opts ...grpc.CallOption) (*ExecutionQueue, error)
+	GetRetention(ctx context.Context, in *GetRetentionRequest, opts ...grpc.CallOption) (*Retention, error)
+	UpdateRetention(ctx context.Context, in *Retention, opts ...grpc.CallOption) (*Retention, error)
+}
+
+type cortexClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCortexClient(cc grpc.ClientConnInterface) CortexClient {
+	return &cortexClient{cc}
+}
+
+func (c *cortexClient) PublishEvents(ctx context.Context, opts ...grpc.CallOption) (Cortex_PublishEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Cortex_ServiceDesc.Streams[0], Cortex_PublishEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cortexPublishEventsClient{stream}
+	return x, nil
+}
+
+type Cortex_PublishEventsClient interface {
+	Send(*PublishEventsRequest) error
+	Recv() (*PublishEventsResponse, error)
+	grpc.ClientStream
+}
+
+type cortexPublishEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cortexPublishEventsClient) Send(m *PublishEventsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cortexPublishEventsClient) Recv() (*PublishEventsResponse, error) {
+	m := new(PublishEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cortexClient) GetRules(ctx context.Context, in *GetRulesRequest, opts ...grpc.CallOption) (*GetRulesResponse, error) {
+	out := new(GetRulesResponse)
+	err := c.cc.Invoke(ctx, Cortex_GetRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) GetRule(ctx context.Context, in *GetRuleRequest, opts ...grpc.CallOption) (*Rule, error) {
+	out := new(Rule)
+	err := c.cc.Invoke(ctx, Cortex_GetRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) AddRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*Rule, error) {
+	out := new(Rule)
+	err := c.cc.Invoke(ctx, Cortex_AddRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) UpdateRule(ctx context.Context, in *Rule, opts ...grpc.CallOption) (*Rule, error) {
+	out := new(Rule)
+	err := c.cc.Invoke(ctx, Cortex_UpdateRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Cortex_RemoveRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) GetScripts(ctx context.Context, in *GetScriptsRequest, opts ...grpc.CallOption) (*GetScriptsResponse, error) {
+	out := new(GetScriptsResponse)
+	err := c.cc.Invoke(ctx, Cortex_GetScripts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) GetScript(ctx context.Context, in *GetScriptRequest, opts ...grpc.CallOption) (*Script, error) {
+	out := new(Script)
+	err := c.cc.Invoke(ctx, Cortex_GetScript_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) AddScript(ctx context.Context, in *Script, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Cortex_AddScript_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) UpdateScript(ctx context.Context, in *Script, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Cortex_UpdateScript_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) RemoveScript(ctx context.Context, in *RemoveScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Cortex_RemoveScript_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) GetRuleExecutions(ctx context.Context, in *GetRuleExecutionsRequest, opts ...grpc.CallOption) (*GetRuleExecutionsResponse, error) {
+	out := new(GetRuleExecutionsResponse)
+	err := c.cc.Invoke(ctx, Cortex_GetRuleExecutions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) GetExecutionQueue(ctx context.Context, in *GetExecutionQueueRequest, opts ...grpc.CallOption) (*ExecutionQueue, error) {
+	out := new(ExecutionQueue)
+	err := c.cc.Invoke(ctx, Cortex_GetExecutionQueue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) GetRetention(ctx context.Context, in *GetRetentionRequest, opts ...grpc.CallOption) (*Retention, error) {
+	out := new(Retention)
+	err := c.cc.Invoke(ctx, Cortex_GetRetention_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cortexClient) UpdateRetention(ctx context.Context, in *Retention, opts ...grpc.CallOption) (*Retention, error) {
+	out := new(Retention)
+	err := c.cc.Invoke(ctx, Cortex_UpdateRetention_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CortexServer is the server API for Cortex service.
+// All implementations must embed UnimplementedCortexServer
+// for forward compatibility
+type CortexServer interface {
+	// PublishEvents ingests batches of events. Each request is answered by a response acknowledging its
+	// events in order, once they are stashed in the buckets of the matching rules.
+	PublishEvents(Cortex_PublishEventsServer) error
+	GetRules(context.Context, *GetRulesRequest) (*GetRulesResponse, error)
+	GetRule(context.Context, *GetRuleRequest) (*Rule, error)
+	// AddRule generates the rule id if it is empty
+	AddRule(context.Context, *Rule) (*Rule, error)
+	// UpdateRule merges the empty fields of the rule with the existing rule
+	UpdateRule(context.Context, *Rule) (*Rule, error)
+	RemoveRule(context.Context, *RemoveRuleRequest) (*emptypb.Empty, error)
+	GetScripts(context.Context, *GetScriptsRequest) (*GetScriptsResponse, error)
+	GetScript(context.Context, *GetScriptRequest) (*Script, error)
+	AddScript(context.Context, *Script) (*emptypb.Empty, error)
+	UpdateScript(context.Context, *Script) (*emptypb.Empty, error)
+	RemoveScript(context.Context, *RemoveScriptRequest) (*emptypb.Empty, error)
+	GetRuleExecutions(context.Context, *GetRuleExecutionsRequest) (*GetRuleExecutionsResponse, error)
+	GetExecutionQueue(context.Context, *GetExecutionQueueRequest) (*ExecutionQueue, error)
+	GetRetention(context.Context, *GetRetentionRequest) (*Retention, error)
+	UpdateRetention(context.Context, *Retention) (*Retention, error)
+	mustEmbedUnimplementedCortexServer()
+}
+
+// UnimplementedCortexServer must be embedded to have forward compatible implementations.
+type UnimplementedCortexServer struct {
+}
+
+func (UnimplementedCortexServer) PublishEvents(Cortex_PublishEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishEvents not implemented")
+}
+func (UnimplementedCortexServer) GetRules(context.Context, *GetRulesRequest) (*GetRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRules not implemented")
+}
+func (UnimplementedCortexServer) GetRule(context.Context, *GetRuleRequest) (*Rule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRule not implemented")
+}
+func (UnimplementedCortexServer) AddRule(context.Context, *Rule) (*Rule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRule not implemented")
+}
+func (UnimplementedCortexServer) UpdateRule(context.Context, *Rule) (*Rule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRule not implemented")
+}
+func (UnimplementedCortexServer) RemoveRule(context.Context, *RemoveRuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRule not implemented")
+}
+func (UnimplementedCortexServer) GetScripts(context.Context, *GetScriptsRequest) (*GetScriptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScripts not implemented")
+}
+func (UnimplementedCortexServer) GetScript(context.Context, *GetScriptRequest) (*Script, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScript not implemented")
+}
+func (UnimplementedCortexServer) AddScript(context.Context, *Script) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddScript not implemented")
+}
+func (UnimplementedCortexServer) UpdateScript(context.Context, *Script) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateScript not implemented")
+}
+func (UnimplementedCortexServer) RemoveScript(context.Context, *RemoveScriptRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveScript not implemented")
+}
+func (UnimplementedCortexServer) GetRuleExecutions(context.Context, *GetRuleExecutionsRequest) (*GetRuleExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuleExecutions not implemented")
+}
+func (UnimplementedCortexServer) GetExecutionQueue(context.Context, *GetExecutionQueueRequest) (*ExecutionQueue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExecutionQueue not implemented")
+}
+func (UnimplementedCortexServer) GetRetention(context.Context, *GetRetentionRequest) (*Retention, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetention not implemented")
+}
+func (UnimplementedCortexServer) UpdateRetention(context.Context, *Retention) (*Retention, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRetention not implemented")
+}
+func (UnimplementedCortexServer) mustEmbedUnimplementedCortexServer() {}
+
+// UnsafeCortexServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CortexServer will
+// result in compilation errors.
+type UnsafeCortexServer interface {
+	mustEmbedUnimplementedCortexServer()
+}
+
+func RegisterCortexServer(s grpc.ServiceRegistrar, srv CortexServer) {
+	s.RegisterService(&Cortex_ServiceDesc, srv)
+}
+
+func _Cortex_PublishEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CortexServer).PublishEvents(&cortexPublishEventsServer{stream})
+}
+
+type Cortex_PublishEventsServer interface {
+	Send(*PublishEventsResponse) error
+	Recv() (*PublishEventsRequest, error)
+	grpc.ServerStream
+}
+
+type cortexPublishEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cortexPublishEventsServer) Send(m *PublishEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cortexPublishEventsServer) Recv() (*PublishEventsRequest, error) {
+	m := new(PublishEventsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Cortex_GetRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetRules(ctx, req.(*GetRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_GetRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetRule(ctx, req.(*GetRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_AddRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Rule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).AddRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_AddRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).AddRule(ctx, req.(*Rule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_UpdateRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Rule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).UpdateRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_UpdateRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).UpdateRule(ctx, req.(*Rule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_RemoveRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).RemoveRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_RemoveRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).RemoveRule(ctx, req.(*RemoveRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_GetScripts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScriptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetScripts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetScripts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetScripts(ctx, req.(*GetScriptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_GetScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetScript(ctx, req.(*GetScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_AddScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Script)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).AddScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_AddScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).AddScript(ctx, req.(*Script))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_UpdateScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Script)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).UpdateScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_UpdateScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).UpdateScript(ctx, req.(*Script))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_RemoveScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).RemoveScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_RemoveScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).RemoveScript(ctx, req.(*RemoveScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_GetRuleExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuleExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetRuleExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetRuleExecutions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetRuleExecutions(ctx, req.(*GetRuleExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_GetExecutionQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExecutionQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetExecutionQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetExecutionQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetExecutionQueue(ctx, req.(*GetExecutionQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_GetRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).GetRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_GetRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).GetRetention(ctx, req.(*GetRetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cortex_UpdateRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Retention)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CortexServer).UpdateRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cortex_UpdateRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CortexServer).UpdateRetention(ctx, req.(*Retention))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Cortex_ServiceDesc is the grpc.ServiceDesc for Cortex service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Cortex_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cortex.v1.Cortex",
+	HandlerType: (*CortexServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRules",
+			Handler:    _Cortex_GetRules_Handler,
+		},
+		{
+			MethodName: "GetRule",
+			Handler:    _Cortex_GetRule_Handler,
+		},
+		{
+			MethodName: "AddRule",
+			Handler:    _Cortex_AddRule_Handler,
+		},
+		{
+			MethodName: "UpdateRule",
+			Handler:    _Cortex_UpdateRule_Handler,
+		},
+		{
+			MethodName: "RemoveRule",
+			Handler:    _Cortex_RemoveRule_Handler,
+		},
+		{
+			MethodName: "GetScripts",
+			Handler:    _Cortex_GetScripts_Handler,
+		},
+		{
+			MethodName: "GetScript",
+			Handler:    _Cortex_GetScript_Handler,
+		},
+		{
+			MethodName: "AddScript",
+			Handler:    _Cortex_AddScript_Handler,
+		},
+		{
+			MethodName: "UpdateScript",
+			Handler:    _Cortex_UpdateScript_Handler,
+		},
+		{
+			MethodName: "RemoveScript",
+			Handler:    _Cortex_RemoveScript_Handler,
+		},
+		{
+			MethodName: "GetRuleExecutions",
+			Handler:    _Cortex_GetRuleExecutions_Handler,
+		},
+		{
+			MethodName: "GetExecutionQueue",
+			Handler:    _Cortex_GetExecutionQueue_Handler,
+		},
+		{
+			MethodName: "GetRetention",
+			Handler:    _Cortex_GetRetention_Handler,
+		},
+		{
+			MethodName: "UpdateRetention",
+			Handler:    _Cortex_UpdateRetention_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PublishEvents",
+			Handler:       _Cortex_PublishEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "cortex/v1/cortex.proto",
+}
@@ -0,0 +1,4 @@
+// Package cortexpb is the go code generated from the grpc api definitions in proto/cortex/v1
+package cortexpb
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=github.com/myntra/cortex/pkg/cortexpb --go-grpc_out=. --go-grpc_opt=module=github.com/myntra/cortex/pkg/cortexpb cortex/v1/cortex.proto
@@ -0,0 +1,294 @@
+package enrichment
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sort"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/incidents"
+)
+
+const (
+	// MatchExact looks up the rows keyed by the value
+	MatchExact = "exact"
+	// MatchCIDR looks up the row of the most specific network, keyed in CIDR notation, containing the value
+	MatchCIDR = "cidr"
+)
+
+const (
+	// TargetExtensions adds the fields to the event's extensions
+	TargetExtensions = "extensions"
+	// TargetData adds the fields to the event's data
+	TargetData = "data"
+)
+
+//go:generate msgp
+
+// Table is a lookup table whose rows are keyed by the value of the key column
+type Table struct {
+	ID   string                            `json:"id"`
+	Key  string                            `json:"key"`
+	Rows map[string]map[string]interface{} `json:"rows"`
+
+	networks []network
+}
+
+type network struct {
+	net *net.IPNet
+	key string
+}
+
+// Validate the table and index its networks
+func (t *Table) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("table id is required")
+	}
+
+	if t.Key == "" {
+		return fmt.Errorf("table key column is required")
+	}
+
+	var networks []network
+	for key := range t.Rows {
+		if _, ipnet, err := net.ParseCIDR(key); err == nil {
+			networks = append(networks, network{net: ipnet, key: key})
+		}
+	}
+
+	// most specific network first
+	sort.Slice(networks, func(i, j int) bool {
+		ones, _ := networks[i].net.Mask.Size()
+		otherOnes, _ := networks[j].net.Mask.Size()
+		if ones != otherOnes {
+			return ones > otherOnes
+		}
+		return networks[i].key < networks[j].key
+	})
+
+	t.networks = networks
+	return nil
+}
+
+// Lookup returns the row matching the value. The table must be validated first
+func (t *Table) Lookup(value, match string) map[string]interface{} {
+	if match != MatchCIDR {
+		return t.Rows[value]
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil
+	}
+
+	for _, n := range t.networks {
+		if n.net.Contains(ip) {
+			return t.Rows[n.key]
+		}
+	}
+	return nil
+}
+
+// ParseCSV parses a table from csv with a header row. The key column is the first column if key is empty
+func ParseCSV(id, key string, r io.Reader) (*Table, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %v", err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("csv header row is required")
+	}
+
+	header := records[0]
+	if key == "" {
+		key = header[0]
+	}
+
+	keyIndex := -1
+	for i, column := range header {
+		if column == key {
+			keyIndex = i
+		}
+	}
+
+	if keyIndex < 0 {
+		return nil, fmt.Errorf("key column %s not found in the csv header", key)
+	}
+
+	table := &Table{ID: id, Key: key, Rows: make(map[string]map[string]interface{})}
+	for _, record := range records[1:] {
+		row := make(map[string]interface{})
+		for i, column := range header {
+			if i != keyIndex {
+				row[column] = record[i]
+			}
+		}
+		table.Rows[record[keyIndex]] = row
+	}
+
+	return table, table.Validate()
+}
+
+// ParseJSON parses a table from a json array of objects having the key column, or from a json object of objects
+// keyed by the value of the key column
+func ParseJSON(id, key string, r io.Reader) (*Table, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{ID: id, Key: key, Rows: make(map[string]map[string]interface{})}
+
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		if err := json.Unmarshal(body, &table.Rows); err != nil {
+			return nil, fmt.Errorf("invalid json: %v", err)
+		}
+
+		if table.Key == "" {
+			table.Key = "key"
+		}
+
+		return table, table.Validate()
+	}
+
+	var list []map[string]interface{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid json: %v", err)
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key column is required for a json array")
+	}
+
+	for i, row := range list {
+		value, ok := row[key]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("row %d has no key column %s", i, key)
+		}
+		delete(row, key)
+		table.Rows[fmt.Sprintf("%v", value)] = row
+	}
+
+	return table, table.Validate()
+}
+
+// Step adds the fields of the table row matching the value at the key path of the event, e.g. data.host, to
+// the event's extensions or data
+type Step struct {
+	Table     string   `json:"table"`
+	Key       string   `json:"key"`
+	Match     string   `json:"match,omitempty"`     // exact or cidr, exact if empty
+	Fields    []string `json:"fields,omitempty"`    // columns to add, all if empty
+	Target    string   `json:"target,omitempty"`    // extensions or data, extensions if empty
+	Prefix    string   `json:"prefix,omitempty"`    // prepended to the names of the added fields
+	Overwrite bool     `json:"overwrite,omitempty"` // replace the fields already set on the event
+}
+
+// Validate the step
+func (s *Step) Validate() error {
+	if s.Table == "" {
+		return fmt.Errorf("step table is required")
+	}
+
+	if s.Key == "" {
+		return fmt.Errorf("step key is required")
+	}
+
+	switch s.Match {
+	case "", MatchExact, MatchCIDR:
+	default:
+		return fmt.Errorf("invalid step match %s, must be %s or %s", s.Match, MatchExact, MatchCIDR)
+	}
+
+	switch s.Target {
+	case "", TargetExtensions, TargetData:
+	default:
+		return fmt.Errorf("invalid step target %s, must be %s or %s", s.Target, TargetExtensions, TargetData)
+	}
+
+	return nil
+}
+
+// Pipeline is the ordered list of steps enriching the events before they are matched. A step sees the fields
+// added by the steps before it
+type Pipeline struct {
+	Steps []Step `json:"steps"`
+}
+
+// Validate the steps of the pipeline
+func (p *Pipeline) Validate() error {
+	for i := range p.Steps {
+		if err := p.Steps[i].Validate(); err != nil {
+			return fmt.Errorf("step %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Enrich the event with the steps of the pipeline. Steps whose table is missing are skipped
+func (p *Pipeline) Enrich(event *events.Event, tables map[string]*Table) {
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		table := tables[step.Table]
+		if table == nil {
+			continue
+		}
+
+		value := incidents.GroupKey(step.Key, event)
+		if value == "" {
+			continue
+		}
+
+		row := table.Lookup(value, step.Match)
+		if row == nil {
+			continue
+		}
+
+		target := targetMap(event, step.Target)
+		if target == nil {
+			continue
+		}
+
+		fields := step.Fields
+		if len(fields) == 0 {
+			for field := range row {
+				fields = append(fields, field)
+			}
+		}
+
+		for _, field := range fields {
+			v, ok := row[field]
+			if !ok {
+				continue
+			}
+			if _, exists := target[step.Prefix+field]; exists && !step.Overwrite {
+				continue
+			}
+			target[step.Prefix+field] = v
+		}
+	}
+}
+
+// targetMap returns the event's extensions or data as a map, creating it if unset. nil is returned for data
+// which isn't a json object
+func targetMap(event *events.Event, target string) map[string]interface{} {
+	if target == TargetData {
+		switch data := event.Data.(type) {
+		case map[string]interface{}:
+			return data
+		case nil:
+			m := make(map[string]interface{})
+			event.Data = m
+			return m
+		}
+		return nil
+	}
+
+	return event.ExtensionsMap()
+}
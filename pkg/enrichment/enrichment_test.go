@@ -0,0 +1,102 @@
+package enrichment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	table, err := ParseCSV("hosts", "", strings.NewReader("host,team,owner\nweb-1,checkout,alice\ndb-1,storage,bob\n"))
+	require.NoError(t, err)
+	require.Equal(t, "host", table.Key)
+	require.Equal(t, map[string]map[string]interface{}{
+		"web-1": {"team": "checkout", "owner": "alice"},
+		"db-1":  {"team": "storage", "owner": "bob"},
+	}, table.Rows)
+
+	table, err = ParseCSV("hosts", "team", strings.NewReader("host,team\nweb-1,checkout\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"host": "web-1"}, table.Lookup("checkout", MatchExact))
+
+	_, err = ParseCSV("hosts", "missing", strings.NewReader("host,team\nweb-1,checkout\n"))
+	require.Error(t, err)
+
+	_, err = ParseCSV("hosts", "", strings.NewReader("host,team\nweb-1\n"))
+	require.Error(t, err)
+
+	_, err = ParseCSV("", "", strings.NewReader("host,team\n"))
+	require.Error(t, err)
+}
+
+func TestParseJSON(t *testing.T) {
+	table, err := ParseJSON("services", "service", strings.NewReader(`[{"service": "cart", "tier": 1}, {"service": "search", "tier": 2}]`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"tier": float64(2)}, table.Lookup("search", MatchExact))
+
+	table, err = ParseJSON("services", "", strings.NewReader(`{"cart": {"tier": 1}}`))
+	require.NoError(t, err)
+	require.Equal(t, "key", table.Key)
+	require.Equal(t, map[string]interface{}{"tier": float64(1)}, table.Lookup("cart", MatchExact))
+
+	_, err = ParseJSON("services", "", strings.NewReader(`[{"service": "cart"}]`))
+	require.Error(t, err)
+
+	_, err = ParseJSON("services", "service", strings.NewReader(`[{"tier": 1}]`))
+	require.Error(t, err)
+
+	_, err = ParseJSON("services", "service", strings.NewReader(`"cart"`))
+	require.Error(t, err)
+}
+
+func TestTableLookupCIDR(t *testing.T) {
+	table, err := ParseCSV("dcs", "network", strings.NewReader("network,dc\n10.0.0.0/8,eu\n10.1.0.0/16,eu-west\nweb-1,none\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{"dc": "eu-west"}, table.Lookup("10.1.2.3", MatchCIDR))
+	require.Equal(t, map[string]interface{}{"dc": "eu"}, table.Lookup("10.2.2.3", MatchCIDR))
+	require.Nil(t, table.Lookup("192.168.1.1", MatchCIDR))
+	require.Nil(t, table.Lookup("web-1", MatchCIDR))
+	require.Nil(t, table.Lookup("10.1.2.3", MatchExact))
+}
+
+func TestPipelineEnrich(t *testing.T) {
+	hosts, err := ParseCSV("hosts", "host", strings.NewReader("host,team,ip\nweb-1,checkout,10.1.2.3\n"))
+	require.NoError(t, err)
+	dcs, err := ParseCSV("dcs", "network", strings.NewReader("network,dc\n10.1.0.0/16,eu-west\n"))
+	require.NoError(t, err)
+	tables := map[string]*Table{"hosts": hosts, "dcs": dcs}
+
+	pipeline := &Pipeline{Steps: []Step{
+		{Table: "hosts", Key: "data.host", Fields: []string{"team", "ip"}, Prefix: "host_"},
+		{Table: "dcs", Key: "extensions.host_ip", Match: MatchCIDR, Target: TargetData},
+		{Table: "missing", Key: "data.host"},
+	}}
+	require.NoError(t, pipeline.Validate())
+
+	event := &events.Event{
+		EventID:    "1",
+		Data:       map[string]interface{}{"host": "web-1", "dc": "unknown"},
+		Extensions: map[string]string{"host_team": "infra"},
+	}
+	pipeline.Enrich(event, tables)
+
+	// fields set by the producer are kept
+	require.Equal(t, map[string]interface{}{"host_team": "infra", "host_ip": "10.1.2.3"}, event.Extensions)
+	require.Equal(t, map[string]interface{}{"host": "web-1", "dc": "unknown"}, event.Data)
+
+	pipeline.Steps[1].Overwrite = true
+	pipeline.Enrich(event, tables)
+	require.Equal(t, "eu-west", event.Data.(map[string]interface{})["dc"])
+
+	// events without the key are left as is
+	event = &events.Event{EventID: "2", Data: "text"}
+	pipeline.Enrich(event, tables)
+	require.Nil(t, event.Extensions)
+
+	require.Error(t, (&Pipeline{Steps: []Step{{Table: "hosts"}}}).Validate())
+	require.Error(t, (&Pipeline{Steps: []Step{{Table: "hosts", Key: "data.host", Match: "prefix"}}}).Validate())
+	require.Error(t, (&Pipeline{Steps: []Step{{Table: "hosts", Key: "data.host", Target: "source"}}}).Validate())
+}
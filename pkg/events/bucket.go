@@ -14,11 +14,17 @@ import (
 
 // NewBucket creates a new Bucket
 func NewBucket(rule rules.Rule) *Bucket {
+	return NewBucketAt(rule, time.Now())
+}
+
+// NewBucketAt creates a new Bucket created at now. Replicas use the time stamped by the leader so that every
+// replica computes the same flush deadline.
+func NewBucketAt(rule rules.Rule, now time.Time) *Bucket {
 	return &Bucket{
 		flushWait:    rule.Dwell,
-		dwellResetAt: time.Now(),
-		UpdatedAt:    time.Now(),
-		CreatedAt:    time.Now(),
+		dwellResetAt: now,
+		UpdatedAt:    now,
+		CreatedAt:    windowStart(&rule, now),
 		Rule:         rule,
 	}
 }
@@ -27,20 +33,67 @@ func NewBucket(rule rules.Rule) *Bucket {
 
 // Bucket contains the rule for a collection of events and the events
 type Bucket struct {
-	Rule         rules.Rule `json:"rule"`
-	Events       []*Event   `json:"events"`
-	FlushLock    bool       `json:"flush_lock"`
-	UpdatedAt    time.Time  `json:"updated_at"`
-	CreatedAt    time.Time  `json:"created_at"`
+	Rule         rules.Rule  `json:"rule"`
+	Events       []*Event    `json:"events"`
+	FlushLock    bool        `json:"flush_lock"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	CreatedAt    time.Time   `json:"created_at"`
+	LateEvents   int         `json:"late_events"` // events which arrived after their event time window
+	Extension    uint64      `json:"extension"`   // milliseconds the flush was manually pushed out by
+	EventTimes   []time.Time `json:"-"`           // time at which each event was added, used to assign events to windows
 	dwellResetAt time.Time
 	flushWait    uint64
 }
 
+// BucketSnapshot is a copy of a bucket along with its flush timing state, used to persist in-flight buckets
+type BucketSnapshot struct {
+	Bucket       *Bucket   `json:"bucket"`
+	DwellResetAt time.Time `json:"dwell_reset_at"`
+	FlushWait    uint64    `json:"flush_wait"`
+}
+
+// Snapshot returns a copy of the bucket which is safe to persist while the bucket is updated
+func (rb *Bucket) Snapshot() *BucketSnapshot {
+	clone := *rb
+	clone.Events = append([]*Event(nil), rb.Events...)
+	clone.EventTimes = append([]time.Time(nil), rb.EventTimes...)
+	return &BucketSnapshot{
+		Bucket:       &clone,
+		DwellResetAt: rb.dwellResetAt,
+		FlushWait:    rb.flushWait,
+	}
+}
+
+// Restore returns the bucket with its flush timing state, so that it flushes on the original schedule
+func (s *BucketSnapshot) Restore() *Bucket {
+	rb := s.Bucket
+	rb.dwellResetAt = s.DwellResetAt
+	rb.flushWait = s.FlushWait
+	return rb
+}
+
 // AddEvent to the bucket
 func (rb *Bucket) AddEvent(event *Event) {
+	rb.AddEventAt(event, time.Now())
+}
+
+// AddEventAt adds the event to the bucket at now
+func (rb *Bucket) AddEventAt(event *Event, now time.Time) {
 	glog.Infof("add event %v  ==> %+v\n", event.EventID, event)
 	rb.Events = append(rb.Events, event)
-	rb.updateDwell()
+	rb.EventTimes = append(rb.EventTimes, now)
+
+	switch rb.Rule.GetWindowType() {
+	case rules.WindowTypeDwell:
+		rb.updateDwell(now)
+	case rules.WindowTypeSession:
+		// out of order events do not move the session back
+		if now.After(rb.UpdatedAt) {
+			rb.UpdatedAt = now
+		}
+	default:
+		rb.UpdatedAt = now
+	}
 }
 
 // Post posts rulebucket to the configured hook endpoint
@@ -89,32 +142,47 @@ func (rb *Bucket) getMaxDwell() time.Duration {
 	return time.Millisecond * time.Duration(rb.Rule.MaxDwell)
 }
 
+// FlushAt returns the time at which the bucket can be flushed. event time windows wait for the allowed lateness
+// after the window closes.
+func (rb *Bucket) FlushAt() time.Time {
+	flushAt := rb.windowEnd().Add(time.Millisecond * time.Duration(rb.Extension))
+	if rb.Rule.IsEventTime() {
+		flushAt = flushAt.Add(time.Millisecond * time.Duration(rb.Rule.AllowedLateness))
+	}
+	return flushAt
+}
+
 // CanFlush returns if the bucket can be evicted from the db
 func (rb *Bucket) CanFlush() bool {
-	return time.Since(rb.CreatedAt) >= time.Millisecond*time.Duration(rb.flushWait)
+	return rb.CanFlushAt(time.Now())
+}
+
+// CanFlushAt returns if the bucket can be evicted from the db at now
+func (rb *Bucket) CanFlushAt(now time.Time) bool {
+	return !now.Before(rb.FlushAt())
 }
 
 // CanFlushIn returns time left for flush
 func (rb *Bucket) CanFlushIn() time.Duration {
-	return time.Millisecond*time.Duration(rb.flushWait) - time.Since(rb.CreatedAt)
+	return rb.FlushAt().Sub(time.Now())
 }
 
 // UpdateDwell updates flush waiting duration
-func (rb *Bucket) updateDwell() {
+func (rb *Bucket) updateDwell(now time.Time) {
 	glog.Infof("updateDwell ")
-	timeSinceDwellReset := time.Since(rb.dwellResetAt)
+	timeSinceDwellReset := now.Sub(rb.dwellResetAt)
 
 	glog.Infof("updateDwell %v %v %v %v", timeSinceDwellReset, rb.getDwellDuration(), rb.getMaxDwell(), rb.getDwellDeadlineDuration())
 	if (timeSinceDwellReset + rb.getDwellDuration()) >= rb.getMaxDwell() {
-		rb.UpdatedAt = time.Now()
+		rb.UpdatedAt = now
 		return
 	}
 
 	if timeSinceDwellReset >= rb.getDwellDeadlineDuration() {
 		glog.Info("updateDwell flushwait + dwell")
-		rb.dwellResetAt = time.Now()
+		rb.dwellResetAt = now
 		rb.flushWait = rb.flushWait + rb.Rule.Dwell
 	}
 
-	rb.UpdatedAt = time.Now()
+	rb.UpdatedAt = now
 }
@@ -3,6 +3,8 @@ package events
 // Code generated by github.com/tinylib/msgp DO NOT EDIT.
 
 import (
+	"time"
+
 	"github.com/tinylib/msgp/msgp"
 )
 
@@ -70,6 +72,33 @@ func (z *Bucket) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "LateEvents":
+			z.LateEvents, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "Extension":
+			z.Extension, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "EventTimes":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.EventTimes) >= int(zb0003) {
+				z.EventTimes = (z.EventTimes)[:zb0003]
+			} else {
+				z.EventTimes = make([]time.Time, zb0003)
+			}
+			for za0002 := range z.EventTimes {
+				z.EventTimes[za0002], err = dc.ReadTime()
+				if err != nil {
+					return
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -82,9 +111,9 @@ func (z *Bucket) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Bucket) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 5
+	// map header, size 8
 	// write "Rule"
-	err = en.Append(0x85, 0xa4, 0x52, 0x75, 0x6c, 0x65)
+	err = en.Append(0x88, 0xa4, 0x52, 0x75, 0x6c, 0x65)
 	if err != nil {
 		return
 	}
@@ -141,15 +170,48 @@ func (z *Bucket) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "LateEvents"
+	err = en.Append(0xaa, 0x4c, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.LateEvents)
+	if err != nil {
+		return
+	}
+	// write "Extension"
+	err = en.Append(0xa9, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Extension)
+	if err != nil {
+		return
+	}
+	// write "EventTimes"
+	err = en.Append(0xaa, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.EventTimes)))
+	if err != nil {
+		return
+	}
+	for za0002 := range z.EventTimes {
+		err = en.WriteTime(z.EventTimes[za0002])
+		if err != nil {
+			return
+		}
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Bucket) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 5
+	// map header, size 8
 	// string "Rule"
-	o = append(o, 0x85, 0xa4, 0x52, 0x75, 0x6c, 0x65)
+	o = append(o, 0x88, 0xa4, 0x52, 0x75, 0x6c, 0x65)
 	o, err = z.Rule.MarshalMsg(o)
 	if err != nil {
 		return
@@ -176,6 +238,18 @@ func (z *Bucket) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "CreatedAt"
 	o = append(o, 0xa9, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
 	o = msgp.AppendTime(o, z.CreatedAt)
+	// string "LateEvents"
+	o = append(o, 0xaa, 0x4c, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73)
+	o = msgp.AppendInt(o, z.LateEvents)
+	// string "Extension"
+	o = append(o, 0xa9, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e)
+	o = msgp.AppendUint64(o, z.Extension)
+	// string "EventTimes"
+	o = append(o, 0xaa, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.EventTimes)))
+	for za0002 := range z.EventTimes {
+		o = msgp.AppendTime(o, z.EventTimes[za0002])
+	}
 	return
 }
 
@@ -243,6 +317,33 @@ func (z *Bucket) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "LateEvents":
+			z.LateEvents, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Extension":
+			z.Extension, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "EventTimes":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.EventTimes) >= int(zb0003) {
+				z.EventTimes = (z.EventTimes)[:zb0003]
+			} else {
+				z.EventTimes = make([]time.Time, zb0003)
+			}
+			for za0002 := range z.EventTimes {
+				z.EventTimes[za0002], bts, err = msgp.ReadTimeBytes(bts)
+				if err != nil {
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -264,6 +365,186 @@ func (z *Bucket) Msgsize() (s int) {
 			s += z.Events[za0001].Msgsize()
 		}
 	}
-	s += 10 + msgp.BoolSize + 10 + msgp.TimeSize + 10 + msgp.TimeSize
+	s += 10 + msgp.BoolSize + 10 + msgp.TimeSize + 10 + msgp.TimeSize + 11 + msgp.IntSize + 10 + msgp.Uint64Size + 11 + msgp.ArrayHeaderSize + (len(z.EventTimes) * (msgp.TimeSize))
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *BucketSnapshot) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "Bucket":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Bucket = nil
+			} else {
+				if z.Bucket == nil {
+					z.Bucket = new(Bucket)
+				}
+				err = z.Bucket.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "DwellResetAt":
+			z.DwellResetAt, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
+		case "FlushWait":
+			z.FlushWait, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *BucketSnapshot) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "Bucket"
+	err = en.Append(0x83, 0xa6, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74)
+	if err != nil {
+		return
+	}
+	if z.Bucket == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Bucket.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "DwellResetAt"
+	err = en.Append(0xac, 0x44, 0x77, 0x65, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x65, 0x74, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.DwellResetAt)
+	if err != nil {
+		return
+	}
+	// write "FlushWait"
+	err = en.Append(0xa9, 0x46, 0x6c, 0x75, 0x73, 0x68, 0x57, 0x61, 0x69, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.FlushWait)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *BucketSnapshot) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 3
+	// string "Bucket"
+	o = append(o, 0x83, 0xa6, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74)
+	if z.Bucket == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Bucket.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "DwellResetAt"
+	o = append(o, 0xac, 0x44, 0x77, 0x65, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x65, 0x74, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.DwellResetAt)
+	// string "FlushWait"
+	o = append(o, 0xa9, 0x46, 0x6c, 0x75, 0x73, 0x68, 0x57, 0x61, 0x69, 0x74)
+	o = msgp.AppendUint64(o, z.FlushWait)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *BucketSnapshot) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "Bucket":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Bucket = nil
+			} else {
+				if z.Bucket == nil {
+					z.Bucket = new(Bucket)
+				}
+				bts, err = z.Bucket.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "DwellResetAt":
+			z.DwellResetAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
+		case "FlushWait":
+			z.FlushWait, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *BucketSnapshot) Msgsize() (s int) {
+	s = 1 + 7
+	if z.Bucket == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Bucket.Msgsize()
+	}
+	s += 13 + msgp.TimeSize + 10 + msgp.Uint64Size
 	return
 }
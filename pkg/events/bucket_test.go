@@ -0,0 +1,31 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/myntra/cortex/pkg/rules"
+)
+
+func TestBucketDwellAt(t *testing.T) {
+	rule := rules.Rule{ID: "dwell", Dwell: 1000, DwellDeadline: 800, MaxDwell: 3000}
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rb := NewBucketAt(rule, start)
+	rb.AddEventAt(&Event{EventID: "1"}, start)
+	require.Equal(t, start.Add(time.Second), rb.FlushAt())
+
+	// past the dwell deadline, the flush is pushed back by dwell
+	rb.AddEventAt(&Event{EventID: "2"}, start.Add(900*time.Millisecond))
+	require.Equal(t, start.Add(2*time.Second), rb.FlushAt())
+	require.Equal(t, start.Add(900*time.Millisecond), rb.UpdatedAt)
+
+	// within the dwell deadline of the last reset, the flush time is unchanged
+	rb.AddEventAt(&Event{EventID: "3"}, start.Add(1000*time.Millisecond))
+	require.Equal(t, start.Add(2*time.Second), rb.FlushAt())
+
+	require.False(t, rb.CanFlushAt(start.Add(1999*time.Millisecond)))
+	require.True(t, rb.CanFlushAt(start.Add(2*time.Second)))
+}
@@ -90,3 +90,27 @@ func (e *Event) Hash() []byte {
 
 	return e.hash
 }
+
+// ExtensionsMap returns the event's extensions as a map, setting it on the event if they are unset or of another
+// type. The entries of map[string]string extensions are copied and other values are kept under the "extensions"
+// key, so the schemas, the enrichment and the normalization add their fields to extensions of the same shape
+func (e *Event) ExtensionsMap() map[string]interface{} {
+	switch extensions := e.Extensions.(type) {
+	case map[string]interface{}:
+		return extensions
+	case map[string]string:
+		m := make(map[string]interface{}, len(extensions))
+		for k, v := range extensions {
+			m[k] = v
+		}
+		e.Extensions = m
+		return m
+	}
+
+	m := make(map[string]interface{})
+	if e.Extensions != nil {
+		m["extensions"] = e.Extensions
+	}
+	e.Extensions = m
+	return m
+}
@@ -63,3 +63,17 @@ func TestEventHashMatch(t *testing.T) {
 	require.False(t, bytes.Equal(hash2, hash3))
 
 }
+
+func TestEventExtensionsMap(t *testing.T) {
+	event := &Event{}
+	event.ExtensionsMap()["team"] = "checkout"
+	require.Equal(t, map[string]interface{}{"team": "checkout"}, event.Extensions)
+
+	event = &Event{Extensions: map[string]string{"team": "checkout"}}
+	event.ExtensionsMap()["tier"] = 1
+	require.Equal(t, map[string]interface{}{"team": "checkout", "tier": 1}, event.Extensions)
+
+	event = &Event{Extensions: "checkout"}
+	event.ExtensionsMap()["tier"] = 1
+	require.Equal(t, map[string]interface{}{"extensions": "checkout", "tier": 1}, event.Extensions)
+}
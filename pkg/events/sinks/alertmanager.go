@@ -0,0 +1,135 @@
+package sinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/myntra/cortex/pkg/events"
+)
+
+// DefaultAlertmanagerEventType is the event type template used when none is configured
+const DefaultAlertmanagerEventType = "alertmanager.{{.Labels.alertname}}.{{.Status}}"
+
+// Alertmanager alert states
+const (
+	AlertmanagerFiring   = "firing"
+	AlertmanagerResolved = "resolved"
+)
+
+// AlertmanagerWebhook structure for the prometheus alertmanager webhook payload
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert structure for a single alert in the alertmanager webhook
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt" structs:",omitnested"`
+	EndsAt       time.Time         `json:"endsAt" structs:",omitnested"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+	Receiver     string            `json:"receiver"`
+	ExternalURL  string            `json:"externalURL"`
+}
+
+// NewAlertmanagerEventType parses the event type template for alertmanager alerts. The template is executed
+// against an AlertmanagerAlert, so labels are available as {{.Labels.<name>}} and the alert state as {{.Status}}
+func NewAlertmanagerEventType(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultAlertmanagerEventType
+	}
+
+	return NewEventType("alertmanager", text)
+}
+
+// EventsFromAlertmanager converts the alerts sent from alertmanager into cloud events, one per alert
+func EventsFromAlertmanager(webhook AlertmanagerWebhook, eventType *template.Template) ([]*events.Event, error) {
+	result := make([]*events.Event, 0, len(webhook.Alerts))
+	for _, alert := range webhook.Alerts {
+		if alert.Status == "" {
+			alert.Status = webhook.Status
+		}
+		if alert.Status != AlertmanagerFiring && alert.Status != AlertmanagerResolved {
+			return nil, fmt.Errorf("alert %s has unknown status %q", alert.Fingerprint, alert.Status)
+		}
+		alert.Receiver = webhook.Receiver
+		alert.ExternalURL = webhook.ExternalURL
+
+		et, err := buildEventType(eventType, alert)
+		if err != nil {
+			return nil, fmt.Errorf("alert %s: %v", alert.Fingerprint, err)
+		}
+
+		// a resolved alert happened when it ended, a firing one when it started
+		eventTime := alert.StartsAt
+		if alert.Status == AlertmanagerResolved {
+			eventTime = alert.EndsAt
+		}
+		if eventTime.IsZero() {
+			eventTime = time.Now()
+		}
+
+		result = append(result, &events.Event{
+			Source:             "alertmanager",
+			Data:               structs.New(alert).Map(),
+			ContentType:        "application/json",
+			EventTypeVersion:   "1.0",
+			CloudEventsVersion: "0.1",
+			SchemaURL:          "",
+			EventID:            generateUUID().String(),
+			EventTime:          eventTime,
+			EventType:          et,
+		})
+	}
+
+	return result, nil
+}
+
+// AlertmanagerSink decodes alertmanager webhooks into one event per alert
+type AlertmanagerSink struct {
+	eventType *template.Template
+	err       error
+}
+
+// NewAlertmanagerSink returns an alertmanager sink building event types with the template
+func NewAlertmanagerSink(eventType string) *AlertmanagerSink {
+	tmpl, err := NewAlertmanagerEventType(eventType)
+	return &AlertmanagerSink{eventType: tmpl, err: err}
+}
+
+// Name of the sink
+func (s *AlertmanagerSink) Name() string {
+	return "alertmanager"
+}
+
+// Validate returns the event type template error, if any
+func (s *AlertmanagerSink) Validate() error {
+	return s.err
+}
+
+// Decode the webhook payload
+func (s *AlertmanagerSink) Decode(payload []byte) ([]*events.Event, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	webhook := AlertmanagerWebhook{}
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, err
+	}
+
+	return EventsFromAlertmanager(webhook, s.eventType)
+}
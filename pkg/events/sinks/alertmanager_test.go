@@ -0,0 +1,77 @@
+package sinks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var alertmanagerWebhook = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"HighLatency\"}",
+	"status": "firing",
+	"receiver": "cortex",
+	"groupLabels": {"alertname": "HighLatency"},
+	"commonLabels": {"alertname": "HighLatency", "severity": "critical"},
+	"commonAnnotations": {"summary": "latency is high"},
+	"externalURL": "http://alertmanager:9093",
+	"alerts": [
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighLatency", "severity": "critical", "instance": "web-1"},
+			"annotations": {"summary": "latency is high"},
+			"startsAt": "2018-08-03T09:52:26.739266876+05:30",
+			"endsAt": "0001-01-01T00:00:00Z",
+			"generatorURL": "http://prometheus:9090/graph",
+			"fingerprint": "a1"
+		},
+		{
+			"status": "resolved",
+			"labels": {"alertname": "HighLatency", "severity": "critical", "instance": "web-2"},
+			"annotations": {"summary": "latency is high"},
+			"startsAt": "2018-08-03T09:40:26.739266876+05:30",
+			"endsAt": "2018-08-03T09:50:26.739266876+05:30",
+			"generatorURL": "http://prometheus:9090/graph",
+			"fingerprint": "a2"
+		}
+	]
+}`
+
+func TestEventsFromAlertmanager(t *testing.T) {
+	webhook := AlertmanagerWebhook{}
+	require.NoError(t, json.Unmarshal([]byte(alertmanagerWebhook), &webhook))
+
+	eventType, err := NewAlertmanagerEventType("")
+	require.NoError(t, err)
+
+	evs, err := EventsFromAlertmanager(webhook, eventType)
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+
+	firing, resolved := evs[0], evs[1]
+	require.Equal(t, "alertmanager.HighLatency.firing", firing.EventType)
+	require.Equal(t, "alertmanager.HighLatency.resolved", resolved.EventType)
+	require.True(t, firing.EventTime.Equal(webhook.Alerts[0].StartsAt))
+	require.True(t, resolved.EventTime.Equal(webhook.Alerts[1].EndsAt))
+
+	data := firing.Data.(map[string]interface{})
+	require.Equal(t, webhook.Alerts[0].Labels, data["Labels"])
+	require.Equal(t, webhook.Alerts[0].Annotations, data["Annotations"])
+	require.Equal(t, "cortex", data["Receiver"])
+	require.IsType(t, time.Time{}, data["StartsAt"])
+
+	eventType, err = NewAlertmanagerEventType("{{.Labels.severity}}.{{.Labels.instance}}.{{.Labels.missing}}")
+	require.NoError(t, err)
+	evs, err = EventsFromAlertmanager(webhook, eventType)
+	require.NoError(t, err)
+	require.Equal(t, "critical.web-1.", evs[0].EventType)
+
+	_, err = NewAlertmanagerEventType("{{.Labels.alertname")
+	require.Error(t, err)
+
+	webhook.Alerts[0].Status = "pending"
+	_, err = EventsFromAlertmanager(webhook, eventType)
+	require.Error(t, err)
+}
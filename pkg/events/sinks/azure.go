@@ -1,9 +1,10 @@
 package sinks
 
 import (
+	"encoding/json"
 	"github.com/myntra/cortex/pkg/events"
+	"text/template"
 	"time"
-	"fmt"
 	"github.com/fatih/structs"
 )
 
@@ -49,8 +50,24 @@ type AzureActivityProperty struct {
 	Cause                string `json:"cause"`
 }
 
-// EventFromAzure converts alerts sent from azure into cloud events
+// DefaultAzureEventType is the event type template used when none is configured. The resource id is escaped to
+// a single segment
+const DefaultAzureEventType = "azure.{{.Data.Context.Activity.ResourceID}}"
+
+var defaultAzureEventType = mustEventType("azure", DefaultAzureEventType)
+
+// EventFromAzure converts alerts sent from azure into cloud events with the default event type
 func EventFromAzure(alert AzureAlert) *events.Event {
+	event, _ := eventFromAzure(alert, defaultAzureEventType)
+	return event
+}
+
+func eventFromAzure(alert AzureAlert, eventType *template.Template) (*events.Event, error) {
+	et, err := buildEventType(eventType, alert)
+	if err != nil {
+		return nil, err
+	}
+
 	event := events.Event{
 		Source:             "azure",
 		Data:               structs.New(alert).Map(),
@@ -60,7 +77,55 @@ func EventFromAzure(alert AzureAlert) *events.Event {
 		SchemaURL:          "",
 		EventID:            generateUUID().String(),
 		EventTime:          time.Now(),
-		EventType:          fmt.Sprintf("azure.%s", alert.Data.Context.Activity.ResourceID),
+		EventType:          et,
+	}
+	return &event, nil
+}
+
+// AzureSink decodes azure alerts
+type AzureSink struct {
+	eventType *template.Template
+	err       error
+}
+
+// NewAzureSink returns an azure sink building event types with the template, the default one if empty
+func NewAzureSink(eventType string) *AzureSink {
+	if eventType == "" {
+		eventType = DefaultAzureEventType
+	}
+	tmpl, err := NewEventType("azure", eventType)
+	return &AzureSink{eventType: tmpl, err: err}
+}
+
+// Name of the sink
+func (s *AzureSink) Name() string {
+	return "azure"
+}
+
+// RespondsWithEvent is true, the azure endpoint responds with the converted event
+func (s *AzureSink) RespondsWithEvent() bool {
+	return true
+}
+
+// Validate returns the event type template error, if any
+func (s *AzureSink) Validate() error {
+	return s.err
+}
+
+// Decode the alert payload
+func (s *AzureSink) Decode(payload []byte) ([]*events.Event, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	alert := AzureAlert{}
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		return nil, err
+	}
+
+	event, err := eventFromAzure(alert, s.eventType)
+	if err != nil {
+		return nil, err
 	}
-	return &event
+	return []*events.Event{event}, nil
 }
@@ -42,8 +42,8 @@ var azureAlert = AzureAlert{
 
 func TestEventFromAzure(t *testing.T) {
 	event := EventFromAzure(azureAlert)
-	if event.EventType != fmt.Sprintf("azure.%s", azureAlert.Data.Context.Activity.ResourceID) {
-		t.Errorf("Event type not matching. expected : %s, got: %s", fmt.Sprintf("azure.%s", azureAlert.Data.Context.Activity.ResourceID), event.EventType)
+	if event.EventType != fmt.Sprintf("azure.%s", Segment(azureAlert.Data.Context.Activity.ResourceID)) {
+		t.Errorf("Event type not matching. expected : %s, got: %s", fmt.Sprintf("azure.%s", Segment(azureAlert.Data.Context.Activity.ResourceID)), event.EventType)
 	}
 	if !reflect.DeepEqual(event.Data, structs.New(azureAlert).Map()) {
 		t.Errorf("Event data not matching. expected : %v, got: %v", azureAlert, event.Data)
@@ -0,0 +1,90 @@
+package sinks
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/structs"
+)
+
+var segmentRegexp = regexp.MustCompile(`[^a-zA-Z0-9_:@-]+`)
+
+// Segment escapes a value to a single event type segment: runs of characters other than letters, digits, _, -, :
+// and @ are replaced by an underscore, e.g. "Connection refused: 10.0.0.1" is Connection_refused:_10_0_0_1. A value
+// which isn't a string, or is empty or - once escaped, is "unknown". The built-in sinks, the json sinks and the
+// inputs escape their event type segments with it
+func Segment(v interface{}) string {
+	s, _ := v.(string)
+	s = strings.Trim(segmentRegexp.ReplaceAllString(s, "_"), "_")
+	if s == "" || s == "-" {
+		return "unknown"
+	}
+	return s
+}
+
+var eventTypeFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// NewEventType parses the event type template of a built-in sink. The template is executed against the fields of
+// the sink's alert, e.g. {{.HostDisplayName}}, whose string values are escaped with Segment so a value can't
+// add segments to the event type. The lower and upper functions change the case of a value, e.g.
+// icinga.{{lower .ServiceState}}
+func NewEventType(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(eventTypeFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s event type template: %v", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// mustEventType parses a default event type template
+func mustEventType(name, text string) *template.Template {
+	tmpl, err := NewEventType(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// buildEventType executes the template against the escaped fields of the alert
+func buildEventType(tmpl *template.Template, alert interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, escapeSegments(structs.New(alert).Map())); err != nil {
+		return "", fmt.Errorf("error building event type: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+// escapeSegments returns a copy of the value with its strings escaped
+func escapeSegments(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return Segment(value)
+	case map[string]string:
+		m := make(map[string]string, len(value))
+		for k, s := range value {
+			m[k] = Segment(s)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, x := range value {
+			m[k] = escapeSegments(x)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(value))
+		for i, x := range value {
+			l[i] = escapeSegments(x)
+		}
+		return l
+	}
+	return v
+}
@@ -0,0 +1,40 @@
+package sinks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegment(t *testing.T) {
+	require.Equal(t, "Connection_refused:_10_0_0_1", Segment("Connection refused: 10.0.0.1"))
+	require.Equal(t, "subscriptions_abc_resourceGroups_rg-1", Segment("/subscriptions/abc/resourceGroups/rg-1/"))
+	require.Equal(t, "web-1@eu", Segment("web-1@eu"))
+	require.Equal(t, "web-1_example_com", Segment("web-1.example.com"))
+	require.Equal(t, "disk_full", Segment(" disk full "))
+	require.Equal(t, "unknown", Segment(" . "))
+	require.Equal(t, "unknown", Segment("-"))
+	require.Equal(t, "unknown", Segment(nil))
+}
+
+func TestBuiltinSinksEventType(t *testing.T) {
+	alert := icingaAlert
+	alert.HostDisplayName = "web-1.eu"
+	evs, err := NewIcingaSink("icinga.{{lower .HostDisplayName}}.{{lower .ServiceState}}.{{.ServiceOutput}}").Decode(mustMarshal(t, alert))
+	require.NoError(t, err)
+	require.Equal(t, "icinga.web-1_eu.critical.connect_to_address_1_2_3_4_and_port_5000:_Connection_refused", evs[0].EventType)
+	// the raw fields are kept in the data
+	require.Equal(t, "web-1.eu", evs[0].Data.(map[string]interface{})["HostDisplayName"])
+
+	evs, err = NewSite247Sink("{{upper .MonitorType}}.{{.MonitorName}}").Decode(mustMarshal(t, site247Alert))
+	require.NoError(t, err)
+	require.Equal(t, "URL.brand_test", evs[0].EventType)
+
+	evs, err = NewAzureSink("").Decode(mustMarshal(t, azureAlert))
+	require.NoError(t, err)
+	require.Equal(t, "azure.subscriptions_subscription_Id_resourceGroups_resource_group_providers_Microsoft_Compute_virtualMachines_resource_name", evs[0].EventType)
+
+	require.Error(t, NewIcingaSink("{{.HostDisplayName").Validate())
+	_, err = NewAzureSink("{{.Data.Missing.Field}}").Decode(mustMarshal(t, azureAlert))
+	require.Error(t, err)
+}
@@ -0,0 +1,297 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/myntra/cortex/pkg/events"
+)
+
+//go:generate msgp
+//msgp:ignore compiledSink expression
+
+// GenericSink is a json sink defined as data instead of code. It is served at /event/sink/<path>.
+//
+// The event fields are expressions evaluated against each alert in the payload. An expression starting
+// with $ is a json path (e.g. $.labels.host), one containing {{ is a text/template
+// (e.g. acme.{{.service}}.{{.status}}) and anything else is used as is.
+type GenericSink struct {
+	ID              string `json:"id"`
+	Path            string `json:"path"`
+	EventType       string `json:"event_type"`
+	Source          string `json:"source,omitempty"`            // defaults to the sink id
+	EventID         string `json:"event_id,omitempty"`          // defaults to a generated uuid
+	EventTime       string `json:"event_time,omitempty"`        // defaults to the time the payload was received
+	EventTimeFormat string `json:"event_time_format,omitempty"` // go time layout, defaults to RFC3339. numbers are unix seconds
+	Split           string `json:"split,omitempty"`             // json path to an array of alerts, each one becoming an event
+
+	// Normalize maps the alerts to the normalized extension, before the mapping of the event source
+	Normalize *NormalizeMapping `json:"normalize,omitempty"`
+
+	compiled *compiledSink // set by Validate
+}
+
+var sinkPathRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Validate the sink definition and compile its expressions. Decode reuses the compiled expressions, so the sink must
+// be validated before it is shared between requests.
+func (s *GenericSink) Validate() error {
+	c, err := s.compile()
+	if err != nil {
+		return err
+	}
+	s.compiled = c
+	return nil
+}
+
+// Name returns the path the sink is served at
+func (s *GenericSink) Name() string {
+	return s.Path
+}
+
+// Decode converts a json payload into events, one per alert
+func (s *GenericSink) Decode(payload []byte) ([]*events.Event, error) {
+	var err error
+	c := s.compiled
+	if c == nil {
+		if c, err = s.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, fmt.Errorf("invalid json payload: %v", err)
+	}
+
+	alerts := []interface{}{doc}
+	if c.split != nil {
+		var ok bool
+		alerts, ok = c.split.lookup(doc).([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("split path %s is not an array", s.Split)
+		}
+	}
+
+	now := time.Now()
+	result := make([]*events.Event, 0, len(alerts))
+	for i, alert := range alerts {
+		event := &events.Event{
+			Source:             s.ID,
+			Data:               alert,
+			ContentType:        "application/json",
+			EventTypeVersion:   "1.0",
+			CloudEventsVersion: "0.1",
+			SchemaURL:          "",
+			EventID:            generateUUID().String(),
+			EventTime:          now,
+		}
+
+		if event.EventType, err = c.eventType.evalEventType(alert); err != nil {
+			return nil, fmt.Errorf("alert %d: event type: %v", i, err)
+		}
+		if event.EventType == "" {
+			return nil, fmt.Errorf("alert %d: empty event type", i)
+		}
+
+		if c.source != nil {
+			if event.Source, err = c.source.eval(alert); err != nil {
+				return nil, fmt.Errorf("alert %d: source: %v", i, err)
+			}
+		}
+
+		if c.eventID != nil {
+			id, err := c.eventID.eval(alert)
+			if err != nil {
+				return nil, fmt.Errorf("alert %d: event id: %v", i, err)
+			}
+			if id != "" {
+				event.EventID = id
+			}
+		}
+
+		if c.eventTime != nil {
+			if event.EventTime, err = c.eventTime.evalTime(alert, s.EventTimeFormat); err != nil {
+				return nil, fmt.Errorf("alert %d: event time: %v", i, err)
+			}
+		}
+
+		if c.normalize != nil {
+			c.normalize.normalize(event)
+		}
+
+		result = append(result, event)
+	}
+
+	return result, nil
+}
+
+type compiledSink struct {
+	eventType *expression
+	source    *expression
+	eventID   *expression
+	eventTime *expression
+	split     jsonPath
+	normalize *compiledMapping
+}
+
+func (s *GenericSink) compile() (*compiledSink, error) {
+	if s.ID == "" {
+		return nil, fmt.Errorf("sink id is empty")
+	}
+
+	if !sinkPathRegexp.MatchString(s.Path) {
+		return nil, fmt.Errorf("sink path %q is invalid. only letters, digits, _ and - are allowed", s.Path)
+	}
+
+	if Get(s.Path) != nil {
+		return nil, fmt.Errorf("sink path %q is reserved for a registered sink", s.Path)
+	}
+
+	if s.EventType == "" {
+		return nil, fmt.Errorf("sink event_type is empty")
+	}
+
+	var c compiledSink
+	var err error
+	if c.eventType, err = compileExpression(s.EventType); err != nil {
+		return nil, err
+	}
+	if c.source, err = compileExpression(s.Source); err != nil {
+		return nil, err
+	}
+	if c.eventID, err = compileExpression(s.EventID); err != nil {
+		return nil, err
+	}
+	if c.eventTime, err = compileExpression(s.EventTime); err != nil {
+		return nil, err
+	}
+	if s.Split != "" {
+		if c.split, err = parseJSONPath(s.Split); err != nil {
+			return nil, err
+		}
+	}
+	if s.Normalize != nil {
+		if c.normalize, err = s.Normalize.compile(); err != nil {
+			return nil, fmt.Errorf("normalize: %v", err)
+		}
+	}
+
+	return &c, nil
+}
+
+// expression is a compiled json path, template or literal
+type expression struct {
+	path      jsonPath
+	tmpl      *template.Template
+	literal   string
+	isLiteral bool
+}
+
+// compileExpression returns nil for an empty expression
+func compileExpression(expr string) (*expression, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	if isJSONPath(expr) {
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		return &expression{path: path}, nil
+	}
+
+	if strings.Contains(expr, "{{") {
+		tmpl, err := template.New("sink").Option("missingkey=zero").Parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %q: %v", expr, err)
+		}
+		return &expression{tmpl: tmpl}, nil
+	}
+
+	return &expression{literal: expr, isLiteral: true}, nil
+}
+
+func (e *expression) value(doc interface{}) (interface{}, error) {
+	switch {
+	case e.isLiteral:
+		return e.literal, nil
+	case e.tmpl != nil:
+		var buf bytes.Buffer
+		if err := e.tmpl.Execute(&buf, doc); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	default:
+		return e.path.lookup(doc), nil
+	}
+}
+
+func (e *expression) eval(doc interface{}) (string, error) {
+	v, err := e.value(doc)
+	if err != nil {
+		return "", err
+	}
+
+	switch value := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	default:
+		return "", fmt.Errorf("value %v is not a string or number", v)
+	}
+}
+
+// evalEventType evaluates an event type expression. the string values of the alert are escaped with Segment, as
+// the event types of the built-in sinks are, so a value with dots or spaces can't add segments to the event type.
+// literals are used as is
+func (e *expression) evalEventType(doc interface{}) (string, error) {
+	switch {
+	case e.isLiteral:
+		return e.literal, nil
+	case e.tmpl != nil:
+		var buf bytes.Buffer
+		if err := e.tmpl.Execute(&buf, escapeSegments(doc)); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	v, err := e.eval(doc)
+	if err != nil || v == "" {
+		return v, err
+	}
+	return Segment(v), nil
+}
+
+func (e *expression) evalTime(doc interface{}, layout string) (time.Time, error) {
+	v, err := e.value(doc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch value := v.(type) {
+	case float64:
+		sec := int64(value)
+		return time.Unix(sec, int64((value-float64(sec))*1e9)), nil
+	case string:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return time.Parse(layout, value)
+	default:
+		return time.Time{}, fmt.Errorf("value %v is not a time", v)
+	}
+}
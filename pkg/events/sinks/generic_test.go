@@ -0,0 +1,86 @@
+package sinks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericSinkEvents(t *testing.T) {
+	sink := GenericSink{
+		ID:        "nagios",
+		Path:      "nagios",
+		EventType: "nagios.{{.host}}.{{.state}}",
+		Source:    "$.checker",
+		EventID:   "$['id']",
+		EventTime: "$.time",
+		Split:     "$.alerts",
+	}
+	require.NoError(t, sink.Validate())
+
+	payload := []byte(`{"alerts": [
+		{"host": "web-1", "state": "down", "checker": "nagios-1", "id": 42, "time": 1533280000},
+		{"host": "web-2", "state": "up", "time": 1533280000.5}
+	]}`)
+
+	evs, err := sink.Decode(payload)
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+
+	require.Equal(t, "nagios.web-1.down", evs[0].EventType)
+	require.Equal(t, "nagios-1", evs[0].Source)
+	require.Equal(t, "42", evs[0].EventID)
+	require.True(t, evs[0].EventTime.Equal(time.Unix(1533280000, 0)))
+	require.Equal(t, "web-1", evs[0].Data.(map[string]interface{})["host"])
+
+	// missing fields fall back to the defaults
+	require.Equal(t, "nagios.web-2.up", evs[1].EventType)
+	require.Equal(t, "", evs[1].Source)
+	require.NotEmpty(t, evs[1].EventID)
+	require.True(t, evs[1].EventTime.Equal(time.Unix(1533280000, 5e8)))
+
+	sink.Split = ""
+	sink.Source = ""
+	sink.EventTime = "$.alerts[0].at"
+	sink.EventTimeFormat = "2006-01-02 15:04:05"
+	sink.EventType = "$.alerts[1].host"
+	require.NoError(t, sink.Validate()) // the definition changed, compile it again
+	evs, err = sink.Decode([]byte(`{"alerts": [{"at": "2018-08-03 07:00:00"}, {"host": "web-2"}]}`))
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	require.Equal(t, "web-2", evs[0].EventType)
+	require.Equal(t, "nagios", evs[0].Source)
+	require.True(t, evs[0].EventTime.Equal(time.Date(2018, 8, 3, 7, 0, 0, 0, time.UTC)))
+
+	_, err = sink.Decode([]byte(`{"alerts": []}`))
+	require.Error(t, err, "empty event type")
+
+	// values with dots or spaces don't add segments to the event type
+	evs, err = sink.Decode([]byte(`{"alerts": [{"at": "2018-08-03 07:00:00"}, {"host": "web-2.example.com"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, "web-2_example_com", evs[0].EventType)
+
+	sink.EventType = "nagios.{{.host}}.{{index .checks 0}}"
+	sink.EventTime = ""
+	require.NoError(t, sink.Validate())
+	evs, err = sink.Decode([]byte(`{"host": "web-2.example.com", "checks": ["disk full"]}`))
+	require.NoError(t, err)
+	require.Equal(t, "nagios.web-2_example_com.disk_full", evs[0].EventType)
+}
+
+func TestGenericSinkValidate(t *testing.T) {
+	invalid := []GenericSink{
+		{Path: "nagios", EventType: "nagios"},
+		{ID: "nagios", Path: "a/b", EventType: "nagios"},
+		{ID: "nagios", Path: "icinga", EventType: "nagios"},
+		{ID: "nagios", Path: "nagios"},
+		{ID: "nagios", Path: "nagios", EventType: "{{.host"},
+		{ID: "nagios", Path: "nagios", EventType: "$.a[x]"},
+		{ID: "nagios", Path: "nagios", EventType: "nagios", Split: "alerts"},
+	}
+
+	for _, sink := range invalid {
+		require.Error(t, sink.Validate(), "%+v", sink)
+	}
+}
@@ -1,7 +1,8 @@
 package sinks
 
 import (
-	"fmt"
+	"encoding/json"
+	"text/template"
 	"time"
 
 	"github.com/fatih/structs"
@@ -23,8 +24,24 @@ type IcingaAlert struct {
 	ServiceDisplayName     string `json:"service_display_name"`
 }
 
-// EventFromIcinga converts alerts sent from icinga into cloud events
+// DefaultIcingaEventType is the event type template used when none is configured. The service output is free
+// text, it is only kept in the data. Before the templates, the event type ended with the unescaped service output
+const DefaultIcingaEventType = "{{.ServiceDisplayName}}.{{.HostDisplayName}}.{{.ServiceState}}"
+
+var defaultIcingaEventType = mustEventType("icinga", DefaultIcingaEventType)
+
+// EventFromIcinga converts alerts sent from icinga into cloud events with the default event type
 func EventFromIcinga(alert IcingaAlert) *events.Event {
+	event, _ := eventFromIcinga(alert, defaultIcingaEventType)
+	return event
+}
+
+func eventFromIcinga(alert IcingaAlert, eventType *template.Template) (*events.Event, error) {
+	et, err := buildEventType(eventType, alert)
+	if err != nil {
+		return nil, err
+	}
+
 	event := events.Event{
 		Source:             "icinga",
 		Data:               structs.New(alert).Map(),
@@ -34,7 +51,55 @@ func EventFromIcinga(alert IcingaAlert) *events.Event {
 		SchemaURL:          "",
 		EventID:            generateUUID().String(),
 		EventTime:          time.Now(),
-		EventType:          fmt.Sprintf("%s.%s.%s", alert.ServiceDisplayName, alert.HostDisplayName, alert.ServiceOutput),
+		EventType:          et,
+	}
+	return &event, nil
+}
+
+// IcingaSink decodes icinga alerts
+type IcingaSink struct {
+	eventType *template.Template
+	err       error
+}
+
+// NewIcingaSink returns an icinga sink building event types with the template, the default one if empty
+func NewIcingaSink(eventType string) *IcingaSink {
+	if eventType == "" {
+		eventType = DefaultIcingaEventType
+	}
+	tmpl, err := NewEventType("icinga", eventType)
+	return &IcingaSink{eventType: tmpl, err: err}
+}
+
+// Name of the sink
+func (s *IcingaSink) Name() string {
+	return "icinga"
+}
+
+// RespondsWithEvent is true, the icinga endpoint responds with the converted event
+func (s *IcingaSink) RespondsWithEvent() bool {
+	return true
+}
+
+// Validate returns the event type template error, if any
+func (s *IcingaSink) Validate() error {
+	return s.err
+}
+
+// Decode the alert payload
+func (s *IcingaSink) Decode(payload []byte) ([]*events.Event, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	alert := IcingaAlert{}
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		return nil, err
+	}
+
+	event, err := eventFromIcinga(alert, s.eventType)
+	if err != nil {
+		return nil, err
 	}
-	return &event
+	return []*events.Event{event}, nil
 }
@@ -0,0 +1,88 @@
+package sinks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed subset of JSONPath: $.a.b[0]['c d']
+type jsonPath []interface{}
+
+func isJSONPath(expr string) bool {
+	return strings.HasPrefix(expr, "$")
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	if !isJSONPath(expr) {
+		return nil, fmt.Errorf("json path %q must start with $", expr)
+	}
+
+	var path jsonPath
+	rest := expr[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("json path %q has an empty key", expr)
+			}
+			path = append(path, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("json path %q has an unclosed [", expr)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				path = append(path, selector[1:len(selector)-1])
+				continue
+			}
+			index, err := strconv.Atoi(selector)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("json path %q has an invalid index %q", expr, selector)
+			}
+			path = append(path, index)
+		default:
+			return nil, fmt.Errorf("json path %q is invalid at %q", expr, rest)
+		}
+	}
+
+	return path, nil
+}
+
+// lookup returns the value at the path in a decoded json document, or nil if it doesn't exist
+func (p jsonPath) lookup(doc interface{}) interface{} {
+	value := doc
+	for _, step := range p {
+		switch s := step.(type) {
+		case string:
+			switch m := value.(type) {
+			case map[string]interface{}:
+				value = m[s]
+			case map[string]string:
+				// labels of the built-in sinks' data
+				v, ok := m[s]
+				if !ok {
+					return nil
+				}
+				value = v
+			default:
+				return nil
+			}
+		case int:
+			a, ok := value.([]interface{})
+			if !ok || s >= len(a) {
+				return nil
+			}
+			value = a[s]
+		}
+	}
+	return value
+}
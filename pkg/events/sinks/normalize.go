@@ -0,0 +1,290 @@
+package sinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/myntra/cortex/pkg/events"
+)
+
+// NormalizedExtension is the extension holding the normalized fields of an event
+const NormalizedExtension = "normalized"
+
+// Normalized severities
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+	SeverityOK       = "ok"
+)
+
+// Normalized statuses
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// FallbackMapping is the key of the mapping used for the sources without one
+const FallbackMapping = "*"
+
+//go:generate msgp
+//msgp:ignore Normalizer compiledMapping
+
+// NormalizeMapping maps the data of a source's events to the normalized fields: severity, status, resource,
+// service and environment. Each field is an expression evaluated against the event data like the generic sink
+// fields: a json path (e.g. $.labels.instance), a template or a literal.
+//
+// Severity and status values are lowercased and translated with Severities and Statuses, then with the common
+// synonyms, e.g. crit or error is critical and recovery is resolved. Values not translating to a normalized value
+// are dropped. The status defaults to resolved for the ok severity and to firing for the others.
+type NormalizeMapping struct {
+	Severity    string            `json:"severity,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Resource    string            `json:"resource,omitempty"`
+	Service     string            `json:"service,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Severities  map[string]string `json:"severities,omitempty"` // lowercased source value to severity
+	Statuses    map[string]string `json:"statuses,omitempty"`   // lowercased source value to status
+}
+
+var severitySynonyms = map[string]string{
+	"critical": SeverityCritical, "crit": SeverityCritical, "fatal": SeverityCritical, "emergency": SeverityCritical,
+	"emerg": SeverityCritical, "alert": SeverityCritical, "error": SeverityCritical, "err": SeverityCritical,
+	"high": SeverityCritical, "major": SeverityCritical, "down": SeverityCritical,
+	"warning": SeverityWarning, "warn": SeverityWarning, "medium": SeverityWarning, "minor": SeverityWarning,
+	"info": SeverityInfo, "informational": SeverityInfo, "information": SeverityInfo, "notice": SeverityInfo,
+	"low": SeverityInfo, "debug": SeverityInfo, "verbose": SeverityInfo,
+	"ok": SeverityOK, "up": SeverityOK, "normal": SeverityOK, "clear": SeverityOK, "cleared": SeverityOK,
+	"resolved": SeverityOK, "recovery": SeverityOK, "healthy": SeverityOK,
+}
+
+var statusSynonyms = map[string]string{
+	"firing": StatusFiring, "active": StatusFiring, "activated": StatusFiring, "open": StatusFiring,
+	"problem": StatusFiring, "triggered": StatusFiring, "alerting": StatusFiring,
+	"resolved": StatusResolved, "closed": StatusResolved, "recovery": StatusResolved, "recovered": StatusResolved,
+	"cleared": StatusResolved, "ok": StatusResolved,
+}
+
+// DefaultMappings are the mappings of the built-in sinks, keyed by event source, and the fallback mapping of the
+// other sources, e.g. the events posted to /event. They can be replaced per source with a mappings file, see
+// LoadNormalizeMappings
+var DefaultMappings = map[string]*NormalizeMapping{
+	// icinga reports the service state, e.g. CRITICAL, and the notification type, e.g. PROBLEM or RECOVERY.
+	// acknowledgements and other notifications get the status of their state
+	"icinga": {
+		Severity:   "$.ServiceState",
+		Status:     "$.NotificationType",
+		Resource:   "$.HostDisplayName",
+		Service:    "$.ServiceDisplayName",
+		Severities: map[string]string{"unknown": SeverityWarning},
+	},
+	// site24x7 reports the monitor status, e.g. DOWN, TROUBLE or UP
+	"site247": {
+		Severity:   "$.Status",
+		Resource:   "$.MonitorName",
+		Service:    "$.MonitorGroupName",
+		Severities: map[string]string{"trouble": SeverityWarning, "critical": SeverityCritical, "maintenance": SeverityInfo},
+	},
+	// azure reports the activity log level, e.g. Error or Informational, and the alert status, Activated or
+	// Resolved
+	"azure": {
+		Severity: "$.Data.Context.Activity.Level",
+		Status:   "$.Data.Status",
+		Resource: "$.Data.Context.Activity.ResourceID",
+		Service:  "$.Data.Context.Activity.ResourceGroupName",
+	},
+	// alertmanager reports the alert status, firing or resolved, and the severity is a label by convention
+	"alertmanager": {
+		Severity:    "$.Labels.severity",
+		Status:      "$.Status",
+		Resource:    "$.Labels.instance",
+		Service:     "$.Labels.job",
+		Environment: "{{or .Labels.environment .Labels.env}}",
+	},
+	// other events carry the fields in their data, e.g. {"severity": "error", "resource": "web-1"}
+	FallbackMapping: {
+		Severity:    "$.severity",
+		Status:      "$.status",
+		Resource:    "$.resource",
+		Service:     "$.service",
+		Environment: "$.environment",
+	},
+}
+
+type compiledMapping struct {
+	severity    *expression
+	status      *expression
+	resource    *expression
+	service     *expression
+	environment *expression
+	severities  map[string]string
+	statuses    map[string]string
+}
+
+// Validate the mapping
+func (m *NormalizeMapping) Validate() error {
+	_, err := m.compile()
+	return err
+}
+
+func (m *NormalizeMapping) compile() (*compiledMapping, error) {
+	for k, v := range m.Severities {
+		if v != SeverityCritical && v != SeverityWarning && v != SeverityInfo && v != SeverityOK {
+			return nil, fmt.Errorf("severity %s of %s must be %s, %s, %s or %s", v, k, SeverityCritical, SeverityWarning, SeverityInfo, SeverityOK)
+		}
+	}
+
+	for k, v := range m.Statuses {
+		if v != StatusFiring && v != StatusResolved {
+			return nil, fmt.Errorf("status %s of %s must be %s or %s", v, k, StatusFiring, StatusResolved)
+		}
+	}
+
+	c := &compiledMapping{severities: m.Severities, statuses: m.Statuses}
+	var err error
+	if c.severity, err = compileExpression(m.Severity); err != nil {
+		return nil, fmt.Errorf("severity: %v", err)
+	}
+	if c.status, err = compileExpression(m.Status); err != nil {
+		return nil, fmt.Errorf("status: %v", err)
+	}
+	if c.resource, err = compileExpression(m.Resource); err != nil {
+		return nil, fmt.Errorf("resource: %v", err)
+	}
+	if c.service, err = compileExpression(m.Service); err != nil {
+		return nil, fmt.Errorf("service: %v", err)
+	}
+	if c.environment, err = compileExpression(m.Environment); err != nil {
+		return nil, fmt.Errorf("environment: %v", err)
+	}
+
+	return c, nil
+}
+
+// fields returns the normalized fields of the event. values failing to evaluate are skipped
+func (c *compiledMapping) fields(event *events.Event) map[string]interface{} {
+	fields := make(map[string]interface{})
+	set := func(name string, expr *expression, translations, synonyms map[string]string) {
+		if expr == nil {
+			return
+		}
+
+		// templates print the missing keys of a map as <no value>
+		value, err := expr.eval(event.Data)
+		if err != nil || value == "" || value == "<no value>" {
+			return
+		}
+
+		if synonyms != nil {
+			value = strings.ToLower(value)
+			if translated, ok := translations[value]; ok {
+				value = translated
+			} else if value = synonyms[value]; value == "" {
+				return
+			}
+		}
+
+		fields[name] = value
+	}
+
+	set("severity", c.severity, c.severities, severitySynonyms)
+	set("status", c.status, c.statuses, statusSynonyms)
+	set("resource", c.resource, nil, nil)
+	set("service", c.service, nil, nil)
+	set("environment", c.environment, nil, nil)
+
+	if _, ok := fields["status"]; !ok {
+		switch fields["severity"] {
+		case nil:
+		case SeverityOK:
+			fields["status"] = StatusResolved
+		default:
+			fields["status"] = StatusFiring
+		}
+	}
+
+	return fields
+}
+
+// normalize adds the fields missing from the event's normalized extension
+func (c *compiledMapping) normalize(event *events.Event) {
+	fields := c.fields(event)
+	if len(fields) == 0 {
+		return
+	}
+
+	extensions := event.ExtensionsMap()
+
+	block, ok := extensions[NormalizedExtension].(map[string]interface{})
+	if !ok {
+		if extensions[NormalizedExtension] != nil {
+			// set by the producer in another shape
+			return
+		}
+		block = make(map[string]interface{})
+		extensions[NormalizedExtension] = block
+	}
+
+	for k, v := range fields {
+		if current, ok := block[k]; !ok || current == "" {
+			block[k] = v
+		}
+	}
+}
+
+// Normalizer fills the normalized extension of the events with the mapping of their source
+type Normalizer struct {
+	mappings map[string]*compiledMapping
+}
+
+// NewNormalizer returns a normalizer with the default mappings replaced by the mappings
+func NewNormalizer(mappings map[string]*NormalizeMapping) (*Normalizer, error) {
+	n := &Normalizer{mappings: make(map[string]*compiledMapping)}
+	for _, m := range []map[string]*NormalizeMapping{DefaultMappings, mappings} {
+		for source, mapping := range m {
+			if mapping == nil {
+				// normalization disabled for the source
+				n.mappings[source] = nil
+				continue
+			}
+			c, err := mapping.compile()
+			if err != nil {
+				return nil, fmt.Errorf("invalid normalize mapping of %s: %v", source, err)
+			}
+			n.mappings[source] = c
+		}
+	}
+	return n, nil
+}
+
+// Normalize adds the fields missing from the normalized extension of the event. Fields set by the producer are
+// kept
+func (n *Normalizer) Normalize(event *events.Event) {
+	mapping, ok := n.mappings[event.Source]
+	if !ok {
+		mapping = n.mappings[FallbackMapping]
+	}
+
+	if mapping != nil {
+		mapping.normalize(event)
+	}
+}
+
+// LoadNormalizeMappings reads a json mappings file, an object of mappings keyed by event source, e.g.
+// {"icinga": {"severity": "$.HostState", "environment": "prod"}}. The key * replaces the fallback mapping and a null
+// mapping disables the normalization of a source
+func LoadNormalizeMappings(path string) (map[string]*NormalizeMapping, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make(map[string]*NormalizeMapping)
+	if err := json.Unmarshal(b, &mappings); err != nil {
+		return nil, fmt.Errorf("invalid normalize mappings file %s: %v", path, err)
+	}
+
+	return mappings, nil
+}
@@ -0,0 +1,175 @@
+package sinks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+func normalized(t *testing.T, event *events.Event) map[string]interface{} {
+	extensions, ok := event.Extensions.(map[string]interface{})
+	require.True(t, ok, "extensions %v", event.Extensions)
+	block, _ := extensions[NormalizedExtension].(map[string]interface{})
+	return block
+}
+
+func TestNormalizeBuiltinSinks(t *testing.T) {
+	n, err := NewNormalizer(nil)
+	require.NoError(t, err)
+
+	event := EventFromIcinga(icingaAlert)
+	n.Normalize(event)
+	require.Equal(t, map[string]interface{}{
+		"severity": SeverityCritical,
+		"status":   StatusFiring,
+		"resource": "hostname",
+		"service":  "servicename-26378",
+	}, normalized(t, event))
+
+	recovery := icingaAlert
+	recovery.ServiceState = "OK"
+	recovery.NotificationType = "RECOVERY"
+	event = EventFromIcinga(recovery)
+	n.Normalize(event)
+	require.Equal(t, SeverityOK, normalized(t, event)["severity"])
+	require.Equal(t, StatusResolved, normalized(t, event)["status"])
+
+	event = EventFromSite247(site247Alert)
+	n.Normalize(event)
+	require.Equal(t, map[string]interface{}{
+		"severity": SeverityCritical,
+		"status":   StatusFiring,
+		"resource": "brand_test",
+		"service":  "search",
+	}, normalized(t, event))
+
+	event = EventFromAzure(azureAlert)
+	n.Normalize(event)
+	require.Equal(t, map[string]interface{}{
+		"severity": SeverityInfo,
+		"status":   StatusFiring,
+		"resource": azureAlert.Data.Context.Activity.ResourceID,
+		"service":  "<resource group>",
+	}, normalized(t, event))
+
+	var webhook AlertmanagerWebhook
+	require.NoError(t, json.Unmarshal([]byte(alertmanagerWebhook), &webhook))
+	webhook.Alerts[1].Labels["env"] = "prod"
+	evs, err := NewAlertmanagerSink("").Decode(mustMarshal(t, webhook))
+	require.NoError(t, err)
+	for _, event := range evs {
+		n.Normalize(event)
+	}
+	require.Equal(t, map[string]interface{}{
+		"severity": SeverityCritical,
+		"status":   StatusFiring,
+		"resource": "web-1",
+	}, normalized(t, evs[0]))
+	require.Equal(t, map[string]interface{}{
+		"severity":    SeverityCritical,
+		"status":      StatusResolved,
+		"resource":    "web-2",
+		"environment": "prod",
+	}, normalized(t, evs[1]))
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
+
+func TestNormalizeEvent(t *testing.T) {
+	n, err := NewNormalizer(nil)
+	require.NoError(t, err)
+
+	// events posted to /event use the fallback mapping
+	event := &events.Event{
+		Source:     "billing",
+		Data:       map[string]interface{}{"severity": "WARN", "resource": "db-1", "environment": "staging"},
+		Extensions: map[string]interface{}{NormalizedExtension: map[string]interface{}{"service": "payments", "resource": "db-2"}},
+	}
+	n.Normalize(event)
+
+	// fields set by the producer are kept
+	require.Equal(t, map[string]interface{}{
+		"severity":    SeverityWarning,
+		"status":      StatusFiring,
+		"resource":    "db-2",
+		"service":     "payments",
+		"environment": "staging",
+	}, normalized(t, event))
+
+	// values without a normalized value are dropped
+	event = &events.Event{Source: "billing", Data: map[string]interface{}{"severity": "purple", "status": "pending"}}
+	n.Normalize(event)
+	require.Nil(t, event.Extensions)
+
+	event = &events.Event{Source: "billing", Data: "text", Extensions: map[string]string{"team": "infra"}}
+	n.Normalize(event)
+	require.Equal(t, map[string]string{"team": "infra"}, event.Extensions)
+
+	// the mapping of a generic sink comes before the mapping of the source
+	sink := &GenericSink{ID: "nagios", Path: "nagios", EventType: "nagios.{{.host}}", Normalize: &NormalizeMapping{
+		Severity:   "$.state",
+		Severities: map[string]string{"2": SeverityCritical},
+	}}
+	evs, err := sink.Decode([]byte(`{"host": "web-1", "state": 2, "severity": "info", "resource": "web-1"}`))
+	require.NoError(t, err)
+	n.Normalize(evs[0])
+	require.Equal(t, map[string]interface{}{
+		"severity": SeverityCritical,
+		"status":   StatusFiring,
+		"resource": "web-1",
+	}, normalized(t, evs[0]))
+
+	sink.Normalize.Severities["2"] = "fatal"
+	require.Error(t, sink.Validate())
+}
+
+func TestNormalizeMappings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "normalize")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mappings.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+		"icinga": {"severity": "$.ServiceState", "environment": "prod", "severities": {"critical": "warning"}},
+		"site247": null,
+		"*": {"severity": "$.level", "statuses": {"done": "resolved"}, "status": "$.state"}
+	}`), 0644))
+
+	mappings, err := LoadNormalizeMappings(path)
+	require.NoError(t, err)
+
+	n, err := NewNormalizer(mappings)
+	require.NoError(t, err)
+
+	event := EventFromIcinga(icingaAlert)
+	n.Normalize(event)
+	require.Equal(t, map[string]interface{}{
+		"severity":    SeverityWarning,
+		"status":      StatusFiring,
+		"environment": "prod",
+	}, normalized(t, event))
+
+	event = EventFromSite247(site247Alert)
+	n.Normalize(event)
+	require.Nil(t, event.Extensions)
+
+	event = &events.Event{Source: "billing", Data: map[string]interface{}{"level": "error", "state": "done"}}
+	n.Normalize(event)
+	require.Equal(t, map[string]interface{}{"severity": SeverityCritical, "status": StatusResolved}, normalized(t, event))
+
+	_, err = NewNormalizer(map[string]*NormalizeMapping{"icinga": {Severities: map[string]string{"down": "fatal"}}})
+	require.Error(t, err)
+	_, err = NewNormalizer(map[string]*NormalizeMapping{"icinga": {Statuses: map[string]string{"down": "open"}}})
+	require.Error(t, err)
+	_, err = NewNormalizer(map[string]*NormalizeMapping{"icinga": {Resource: "$.a["}})
+	require.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package sinks
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/myntra/cortex/pkg/events"
+)
+
+// Sink converts the alerts of a monitoring tool into events. A sink is served at /event/sink/<name>
+type Sink interface {
+	// Name is the unique name of the sink, used as its http path
+	Name() string
+	// Validate checks the sink is usable before it is registered
+	Validate() error
+	// Decode converts the request payload into events. An invalid payload returns an error
+	Decode(payload []byte) ([]*events.Event, error)
+}
+
+// EventResponder is implemented by the sinks converting a payload into a single event. They respond with the
+// converted event instead of the match results, as the site247, icinga and azure endpoints always did.
+type EventResponder interface {
+	RespondsWithEvent() bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Sink)
+)
+
+// Register makes a sink available to the service. Built-in sinks register themselves in init, embedders
+// importing cortex as a library can register their own sinks before the service is created.
+// Register panics if the sink is invalid or a sink with the same name is already registered
+func Register(sink Sink) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if sink == nil {
+		panic("sinks: Register sink is nil")
+	}
+
+	if !sinkPathRegexp.MatchString(sink.Name()) {
+		panic(fmt.Sprintf("sinks: Register invalid sink name %q", sink.Name()))
+	}
+
+	if _, ok := registry[sink.Name()]; ok {
+		panic("sinks: Register called twice for sink " + sink.Name())
+	}
+
+	if err := sink.Validate(); err != nil {
+		panic(fmt.Sprintf("sinks: Register invalid sink %s: %v", sink.Name(), err))
+	}
+
+	registry[sink.Name()] = sink
+}
+
+// Get returns the registered sink with the name or nil
+func Get(name string) Sink {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name]
+}
+
+// Registered returns the registered sinks sorted by name
+func Registered() []Sink {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]Sink, 0, len(registry))
+	for _, sink := range registry {
+		list = append(list, sink)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+
+	return list
+}
+
+// Registry holds the sinks of a service: the registered sinks, some of them replaced by the service, e.g. the
+// built-in sinks with their configured event types. The inputs decode with the sinks of their service
+type Registry struct {
+	m map[string]Sink
+}
+
+// NewRegistry returns the registered sinks, replacing the ones with the name of a replacement. NewRegistry returns
+// an error if a replacement is invalid or no sink with its name is registered
+func NewRegistry(replacements ...Sink) (*Registry, error) {
+	registryMu.RLock()
+	m := make(map[string]Sink, len(registry))
+	for name, sink := range registry {
+		m[name] = sink
+	}
+	registryMu.RUnlock()
+
+	for _, sink := range replacements {
+		if _, ok := m[sink.Name()]; !ok {
+			return nil, fmt.Errorf("sink %s is not registered", sink.Name())
+		}
+
+		if err := sink.Validate(); err != nil {
+			return nil, err
+		}
+
+		m[sink.Name()] = sink
+	}
+
+	return &Registry{m: m}, nil
+}
+
+// Get returns the sink with the name or nil
+func (r *Registry) Get(name string) Sink {
+	return r.m[name]
+}
+
+// Sinks returns the sinks sorted by name
+func (r *Registry) Sinks() []Sink {
+	list := make([]Sink, 0, len(r.m))
+	for _, sink := range r.m {
+		list = append(list, sink)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+
+	return list
+}
+
+func init() {
+	Register(NewSite247Sink(DefaultSite247EventType))
+	Register(NewIcingaSink(DefaultIcingaEventType))
+	Register(NewAzureSink(DefaultAzureEventType))
+	Register(NewAlertmanagerSink(DefaultAlertmanagerEventType))
+}
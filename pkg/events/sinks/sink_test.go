@@ -0,0 +1,73 @@
+package sinks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+type testSink struct{ name string }
+
+func (s testSink) Name() string    { return s.name }
+func (s testSink) Validate() error { return nil }
+func (s testSink) Decode(payload []byte) ([]*events.Event, error) {
+	return []*events.Event{{EventType: s.name + "." + string(payload)}}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	names := []string{}
+	for _, sink := range Registered() {
+		names = append(names, sink.Name())
+	}
+	require.Equal(t, []string{"alertmanager", "azure", "icinga", "site247"}, names)
+
+	Register(testSink{name: "custom"})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "custom")
+		registryMu.Unlock()
+	}()
+
+	evs, err := Get("custom").Decode([]byte("down"))
+	require.NoError(t, err)
+	require.Equal(t, "custom.down", evs[0].EventType)
+
+	require.Panics(t, func() { Register(testSink{name: "custom"}) })
+	require.Panics(t, func() { Register(testSink{name: "a/b"}) })
+	require.Panics(t, func() { Register(NewAlertmanagerSink("{{")) })
+
+	// generic sinks can't shadow a registered sink
+	require.Error(t, (&GenericSink{ID: "custom", Path: "custom", EventType: "x"}).Validate())
+
+	// the sinks of a registry replace the registered sinks of the same name, which are left as they are
+	alertmanager := NewAlertmanagerSink("am.{{.Status}}")
+	registry, err := NewRegistry(alertmanager)
+	require.NoError(t, err)
+	require.Equal(t, alertmanager, registry.Get("alertmanager"))
+	require.NotEqual(t, alertmanager, Get("alertmanager"))
+	require.Equal(t, Get("custom"), registry.Get("custom"))
+	require.Len(t, registry.Sinks(), 5)
+	require.Equal(t, "alertmanager", registry.Sinks()[0].Name())
+
+	_, err = NewRegistry(testSink{name: "unknown"})
+	require.Error(t, err)
+	_, err = NewRegistry(NewAlertmanagerSink("{{"))
+	require.Error(t, err)
+}
+
+func TestBuiltinSinksDecode(t *testing.T) {
+	payload, err := json.Marshal(icingaAlert)
+	require.NoError(t, err)
+
+	evs, err := Get("icinga").Decode(payload)
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	require.Equal(t, EventFromIcinga(icingaAlert).EventType, evs[0].EventType)
+
+	for _, sink := range Registered() {
+		_, err := sink.Decode([]byte("{"))
+		require.Error(t, err, sink.Name())
+	}
+}
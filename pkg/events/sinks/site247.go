@@ -1,7 +1,8 @@
 package sinks
 
 import (
-	"fmt"
+	"encoding/json"
+	"text/template"
 	"time"
 
 	"github.com/fatih/structs"
@@ -28,8 +29,23 @@ type Site247Alert struct {
 	Tags                 []map[string]interface{} `json:"JSON_TAGS,omitempty"`
 }
 
-// EventFromSite247 converts alerts sent from site24x7 into cloud events
+// DefaultSite247EventType is the event type template used when none is configured
+const DefaultSite247EventType = "site247.{{.MonitorGroupName}}.{{.MonitorName}}.{{.Status}}"
+
+var defaultSite247EventType = mustEventType("site247", DefaultSite247EventType)
+
+// EventFromSite247 converts alerts sent from site24x7 into cloud events with the default event type
 func EventFromSite247(alert Site247Alert) *events.Event {
+	event, _ := eventFromSite247(alert, defaultSite247EventType)
+	return event
+}
+
+func eventFromSite247(alert Site247Alert, eventType *template.Template) (*events.Event, error) {
+	et, err := buildEventType(eventType, alert)
+	if err != nil {
+		return nil, err
+	}
+
 	event := events.Event{
 		Source:             "site247",
 		Data:               structs.New(alert).Map(),
@@ -39,12 +55,60 @@ func EventFromSite247(alert Site247Alert) *events.Event {
 		SchemaURL:          "",
 		EventID:            generateUUID().String(),
 		EventTime:          time.Now(),
-		EventType:          fmt.Sprintf("site247.%s.%s.%s", alert.MonitorGroupName, alert.MonitorName, alert.Status),
+		EventType:          et,
 	}
-	return &event
+	return &event, nil
 }
 
 func generateUUID() uuid.UUID {
 	uid := uuid.NewV4()
 	return uid
 }
+
+// Site247Sink decodes site247 alerts
+type Site247Sink struct {
+	eventType *template.Template
+	err       error
+}
+
+// NewSite247Sink returns a site247 sink building event types with the template, the default one if empty
+func NewSite247Sink(eventType string) *Site247Sink {
+	if eventType == "" {
+		eventType = DefaultSite247EventType
+	}
+	tmpl, err := NewEventType("site247", eventType)
+	return &Site247Sink{eventType: tmpl, err: err}
+}
+
+// Name of the sink
+func (s *Site247Sink) Name() string {
+	return "site247"
+}
+
+// RespondsWithEvent is true, the site247 endpoint responds with the converted event
+func (s *Site247Sink) RespondsWithEvent() bool {
+	return true
+}
+
+// Validate returns the event type template error, if any
+func (s *Site247Sink) Validate() error {
+	return s.err
+}
+
+// Decode the alert payload
+func (s *Site247Sink) Decode(payload []byte) ([]*events.Event, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	alert := Site247Alert{}
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		return nil, err
+	}
+
+	event, err := eventFromSite247(alert, s.eventType)
+	if err != nil {
+		return nil, err
+	}
+	return []*events.Event{event}, nil
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"time"
+
+	"github.com/myntra/cortex/pkg/rules"
+)
+
+// windowStart returns the start of the rule's first window which contains t. tumbling and hopping windows are aligned
+// to multiples of the window size and hop respectively.
+func windowStart(rule *rules.Rule, t time.Time) time.Time {
+	switch rule.GetWindowType() {
+	case rules.WindowTypeTumbling:
+		return t.Truncate(time.Millisecond * time.Duration(rule.WindowSize))
+	case rules.WindowTypeHopping:
+		size := time.Millisecond * time.Duration(rule.WindowSize)
+		hop := time.Millisecond * time.Duration(rule.WindowHop)
+		// the earliest window ending after t
+		return t.Add(-size).Truncate(hop).Add(hop)
+	default:
+		return t
+	}
+}
+
+// windowEnd returns the time at which the bucket's current window closes
+func (rb *Bucket) windowEnd() time.Time {
+	switch rb.Rule.GetWindowType() {
+	case rules.WindowTypeTumbling, rules.WindowTypeHopping:
+		return rb.CreatedAt.Add(time.Millisecond * time.Duration(rb.Rule.WindowSize))
+	case rules.WindowTypeSession:
+		return rb.UpdatedAt.Add(time.Millisecond * time.Duration(rb.Rule.SessionGap))
+	default:
+		return rb.CreatedAt.Add(time.Millisecond * time.Duration(rb.flushWait))
+	}
+}
+
+// eventTime returns the time at which the i'th event was added. events added without a time belong to the current
+// window.
+func (rb *Bucket) eventTime(i int) time.Time {
+	if i < len(rb.EventTimes) {
+		return rb.EventTimes[i]
+	}
+	return rb.CreatedAt
+}
+
+// Window returns the bucket to execute for the current window. tumbling and hopping buckets may hold events of the
+// following windows, so a copy with only the current window's events is returned. the bucket itself is returned for
+// the other window types.
+func (rb *Bucket) Window() *Bucket {
+	switch rb.Rule.GetWindowType() {
+	case rules.WindowTypeTumbling, rules.WindowTypeHopping:
+	default:
+		return rb
+	}
+
+	end := rb.windowEnd()
+	window := *rb
+	window.Events = nil
+	window.EventTimes = nil
+	for i, event := range rb.Events {
+		if t := rb.eventTime(i); t.Before(end) {
+			window.Events = append(window.Events, event)
+			window.EventTimes = append(window.EventTimes, t)
+		}
+	}
+
+	return &window
+}
+
+// Advance moves a tumbling or hopping bucket to its next window, keeping the events which belong to it. returns false
+// if the bucket has no events left or its window type does not advance, in which case the bucket should be removed.
+func (rb *Bucket) Advance() bool {
+	var hop uint64
+	switch rb.Rule.GetWindowType() {
+	case rules.WindowTypeTumbling:
+		hop = rb.Rule.WindowSize
+	case rules.WindowTypeHopping:
+		hop = rb.Rule.WindowHop
+	default:
+		return false
+	}
+
+	start := rb.CreatedAt.Add(time.Millisecond * time.Duration(hop))
+	var events []*Event
+	var eventTimes []time.Time
+	for i, event := range rb.Events {
+		if t := rb.eventTime(i); !t.Before(start) {
+			events = append(events, event)
+			eventTimes = append(eventTimes, t)
+		}
+	}
+
+	if len(events) == 0 {
+		return false
+	}
+
+	// skip the empty windows between the current window and the earliest remaining event
+	earliest := windowStart(&rb.Rule, eventTimes[0])
+	for _, t := range eventTimes[1:] {
+		if first := windowStart(&rb.Rule, t); first.Before(earliest) {
+			earliest = first
+		}
+	}
+	if earliest.After(start) {
+		start = earliest
+	}
+
+	rb.CreatedAt = start
+	rb.Events = events
+	rb.EventTimes = eventTimes
+	rb.FlushLock = false
+	rb.LateEvents = 0
+	rb.Extension = 0
+	return true
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/myntra/cortex/pkg/rules"
+)
+
+func eventIDs(rb *Bucket) []string {
+	var ids []string
+	for _, event := range rb.Events {
+		ids = append(ids, event.EventID)
+	}
+	return ids
+}
+
+func TestDwellWindowIsDefault(t *testing.T) {
+	rule := rules.Rule{ID: "dwell", Dwell: 1000, DwellDeadline: 800, MaxDwell: 3000}
+	require.NoError(t, rule.Validate())
+	require.Equal(t, rules.WindowTypeDwell, rule.GetWindowType())
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 500, time.UTC)
+	rb := NewBucketAt(rule, start)
+	rb.AddEventAt(&Event{EventID: "1"}, start)
+	require.Equal(t, start, rb.CreatedAt)
+	require.Equal(t, start.Add(time.Second), rb.FlushAt())
+	require.True(t, rb == rb.Window())
+	require.False(t, rb.Advance())
+}
+
+func TestTumblingWindow(t *testing.T) {
+	rule := rules.Rule{ID: "tumbling", WindowType: rules.WindowTypeTumbling, WindowSize: 1000}
+	require.NoError(t, rule.Validate())
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	rb := NewBucketAt(rule, start.Add(300*time.Millisecond))
+	rb.AddEventAt(&Event{EventID: "1"}, start.Add(300*time.Millisecond))
+	rb.AddEventAt(&Event{EventID: "2"}, start.Add(900*time.Millisecond))
+	// received before the flush, belongs to the next window
+	rb.AddEventAt(&Event{EventID: "3"}, start.Add(1100*time.Millisecond))
+
+	// aligned to the window size and not expanded by events
+	require.Equal(t, start, rb.CreatedAt)
+	require.Equal(t, start.Add(time.Second), rb.FlushAt())
+
+	window := rb.Window()
+	require.Equal(t, []string{"1", "2"}, eventIDs(window))
+
+	require.True(t, rb.Advance())
+	require.Equal(t, start.Add(time.Second), rb.CreatedAt)
+	require.Equal(t, []string{"3"}, eventIDs(rb))
+	require.Equal(t, []string{"1", "2"}, eventIDs(window))
+
+	require.False(t, rb.Advance())
+}
+
+func TestTumblingWindowSkipsEmptyWindows(t *testing.T) {
+	rule := rules.Rule{ID: "tumbling", WindowType: rules.WindowTypeTumbling, WindowSize: 1000}
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rb := NewBucketAt(rule, start)
+	rb.AddEventAt(&Event{EventID: "1"}, start)
+	rb.AddEventAt(&Event{EventID: "2"}, start.Add(3500*time.Millisecond))
+
+	require.True(t, rb.Advance())
+	require.Equal(t, start.Add(3*time.Second), rb.CreatedAt)
+	require.Equal(t, []string{"2"}, eventIDs(rb))
+}
+
+func TestSessionWindow(t *testing.T) {
+	rule := rules.Rule{ID: "session", WindowType: rules.WindowTypeSession, SessionGap: 500}
+	require.NoError(t, rule.Validate())
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	rb := NewBucketAt(rule, start)
+	rb.AddEventAt(&Event{EventID: "1"}, start)
+	require.Equal(t, start.Add(500*time.Millisecond), rb.FlushAt())
+
+	// activity keeps the session open
+	rb.AddEventAt(&Event{EventID: "2"}, start.Add(400*time.Millisecond))
+	rb.AddEventAt(&Event{EventID: "3"}, start.Add(800*time.Millisecond))
+	require.Equal(t, start.Add(1300*time.Millisecond), rb.FlushAt())
+	require.False(t, rb.CanFlushAt(start.Add(1200*time.Millisecond)))
+	require.True(t, rb.CanFlushAt(start.Add(1300*time.Millisecond)))
+
+	// an out of order event does not shorten the session
+	rb.AddEventAt(&Event{EventID: "4"}, start.Add(100*time.Millisecond))
+	require.Equal(t, start.Add(1300*time.Millisecond), rb.FlushAt())
+
+	require.Equal(t, []string{"1", "2", "3", "4"}, eventIDs(rb.Window()))
+	require.False(t, rb.Advance())
+}
+
+func TestHoppingWindow(t *testing.T) {
+	rule := rules.Rule{ID: "hopping", WindowType: rules.WindowTypeHopping, WindowSize: 1000, WindowHop: 500}
+	require.NoError(t, rule.Validate())
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the earliest window containing 1.2s is [0.5s, 1.5s)
+	rb := NewBucketAt(rule, start.Add(1200*time.Millisecond))
+	rb.AddEventAt(&Event{EventID: "1"}, start.Add(1200*time.Millisecond))
+	rb.AddEventAt(&Event{EventID: "2"}, start.Add(1400*time.Millisecond))
+	rb.AddEventAt(&Event{EventID: "3"}, start.Add(1700*time.Millisecond))
+
+	require.Equal(t, start.Add(500*time.Millisecond), rb.CreatedAt)
+	require.Equal(t, start.Add(1500*time.Millisecond), rb.FlushAt())
+	require.Equal(t, []string{"1", "2"}, eventIDs(rb.Window()))
+
+	// windows overlap, the events of [1s, 2s) are executed again
+	require.True(t, rb.Advance())
+	require.Equal(t, start.Add(time.Second), rb.CreatedAt)
+	require.Equal(t, []string{"1", "2", "3"}, eventIDs(rb.Window()))
+
+	require.True(t, rb.Advance())
+	require.Equal(t, start.Add(1500*time.Millisecond), rb.CreatedAt)
+	require.Equal(t, []string{"3"}, eventIDs(rb.Window()))
+
+	require.False(t, rb.Advance())
+}
+
+func TestWindowTypeValidation(t *testing.T) {
+	invalid := []rules.Rule{
+		{WindowType: "unknown"},
+		{WindowType: rules.WindowTypeTumbling},
+		{WindowType: rules.WindowTypeSession},
+		{WindowType: rules.WindowTypeHopping, WindowSize: 1000},
+		{WindowType: rules.WindowTypeHopping, WindowSize: 1000, WindowHop: 2000},
+	}
+
+	for _, rule := range invalid {
+		require.Error(t, rule.Validate(), rule.WindowType)
+	}
+}
@@ -6,6 +6,15 @@ import (
 	"github.com/myntra/cortex/pkg/events"
 )
 
+const (
+	// ScriptStatusNone is set when the rule has no script or the script did not set a result
+	ScriptStatusNone = "none"
+	// ScriptStatusOK is set when the script set a result
+	ScriptStatusOK = "ok"
+	// ScriptStatusError is set when the script failed. the error message is stored as the script result
+	ScriptStatusError = "error"
+)
+
 //go:generate msgp
 
 // Record stores a rules execution state and result
@@ -13,6 +22,8 @@ type Record struct {
 	ID             string        `json:"id"`
 	Bucket         events.Bucket `json:"bucket"`
 	ScriptResult   interface{}   `json:"script_result"`
+	ScriptStatus   string        `json:"script_status"`
 	HookStatusCode int           `json:"hook_status_code"`
+	LateEvents     int           `json:"late_events"` // events which arrived after the bucket's event time window
 	CreatedAt      time.Time     `json:"created_at"`
 }
@@ -37,11 +37,21 @@ func (z *Record) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "ScriptStatus":
+			z.ScriptStatus, err = dc.ReadString()
+			if err != nil {
+				return
+			}
 		case "HookStatusCode":
 			z.HookStatusCode, err = dc.ReadInt()
 			if err != nil {
 				return
 			}
+		case "LateEvents":
+			z.LateEvents, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
 		case "CreatedAt":
 			z.CreatedAt, err = dc.ReadTime()
 			if err != nil {
@@ -59,9 +69,9 @@ func (z *Record) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Record) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 5
+	// map header, size 7
 	// write "ID"
-	err = en.Append(0x85, 0xa2, 0x49, 0x44)
+	err = en.Append(0x87, 0xa2, 0x49, 0x44)
 	if err != nil {
 		return
 	}
@@ -87,6 +97,15 @@ func (z *Record) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "ScriptStatus"
+	err = en.Append(0xac, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ScriptStatus)
+	if err != nil {
+		return
+	}
 	// write "HookStatusCode"
 	err = en.Append(0xae, 0x48, 0x6f, 0x6f, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65)
 	if err != nil {
@@ -96,6 +115,15 @@ func (z *Record) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "LateEvents"
+	err = en.Append(0xaa, 0x4c, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.LateEvents)
+	if err != nil {
+		return
+	}
 	// write "CreatedAt"
 	err = en.Append(0xa9, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
 	if err != nil {
@@ -111,9 +139,9 @@ func (z *Record) EncodeMsg(en *msgp.Writer) (err error) {
 // MarshalMsg implements msgp.Marshaler
 func (z *Record) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 5
+	// map header, size 7
 	// string "ID"
-	o = append(o, 0x85, 0xa2, 0x49, 0x44)
+	o = append(o, 0x87, 0xa2, 0x49, 0x44)
 	o = msgp.AppendString(o, z.ID)
 	// string "Bucket"
 	o = append(o, 0xa6, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74)
@@ -127,9 +155,15 @@ func (z *Record) MarshalMsg(b []byte) (o []byte, err error) {
 	if err != nil {
 		return
 	}
+	// string "ScriptStatus"
+	o = append(o, 0xac, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73)
+	o = msgp.AppendString(o, z.ScriptStatus)
 	// string "HookStatusCode"
 	o = append(o, 0xae, 0x48, 0x6f, 0x6f, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65)
 	o = msgp.AppendInt(o, z.HookStatusCode)
+	// string "LateEvents"
+	o = append(o, 0xaa, 0x4c, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73)
+	o = msgp.AppendInt(o, z.LateEvents)
 	// string "CreatedAt"
 	o = append(o, 0xa9, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
 	o = msgp.AppendTime(o, z.CreatedAt)
@@ -167,11 +201,21 @@ func (z *Record) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "ScriptStatus":
+			z.ScriptStatus, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
 		case "HookStatusCode":
 			z.HookStatusCode, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
 				return
 			}
+		case "LateEvents":
+			z.LateEvents, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
 		case "CreatedAt":
 			z.CreatedAt, bts, err = msgp.ReadTimeBytes(bts)
 			if err != nil {
@@ -190,6 +234,6 @@ func (z *Record) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *Record) Msgsize() (s int) {
-	s = 1 + 3 + msgp.StringPrefixSize + len(z.ID) + 7 + z.Bucket.Msgsize() + 13 + msgp.GuessSize(z.ScriptResult) + 15 + msgp.IntSize + 10 + msgp.TimeSize
+	s = 1 + 3 + msgp.StringPrefixSize + len(z.ID) + 7 + z.Bucket.Msgsize() + 13 + msgp.GuessSize(z.ScriptResult) + 13 + msgp.StringPrefixSize + len(z.ScriptStatus) + 15 + msgp.IntSize + 11 + msgp.IntSize + 10 + msgp.TimeSize
 	return
 }
@@ -0,0 +1,97 @@
+package executions
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// OrderAsc returns the oldest records first
+	OrderAsc = "asc"
+	// OrderDesc returns the newest records first
+	OrderDesc = "desc"
+
+	// DefaultQueryLimit is the page size when the query limit is not set
+	DefaultQueryLimit = 100
+	// MaxQueryLimit is the maximum page size
+	MaxQueryLimit = 1000
+)
+
+// Query filters, sorts and paginates the execution history of a rule
+type Query struct {
+	RuleID         string    `json:"rule_id"`
+	From           time.Time `json:"from"`             // inclusive, zero is unbounded
+	To             time.Time `json:"to"`               // inclusive, zero is unbounded
+	HookStatusCode int       `json:"hook_status_code"` // 0 matches all
+	ScriptStatus   string    `json:"script_status"`    // none, ok or error. empty matches all
+	Order          string    `json:"order"`            // asc or desc by created_at. defaults to desc
+	Limit          int       `json:"limit"`            // page size. defaults to DefaultQueryLimit
+	Cursor         string    `json:"cursor"`           // NextCursor of the previous page
+}
+
+// Page is a page of records returned for a query
+type Page struct {
+	Records    []*Record `json:"records"`
+	NextCursor string    `json:"next_cursor,omitempty"` // empty if there are no more records
+}
+
+// Validate validates the query and sets the defaults
+func (q *Query) Validate() error {
+	if q.RuleID == "" {
+		return fmt.Errorf("rule id is empty")
+	}
+
+	switch q.Order {
+	case "":
+		q.Order = OrderDesc
+	case OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("invalid order %v, expected %v or %v", q.Order, OrderAsc, OrderDesc)
+	}
+
+	switch q.ScriptStatus {
+	case "", ScriptStatusNone, ScriptStatusOK, ScriptStatusError:
+	default:
+		return fmt.Errorf("invalid script status %v, expected %v, %v or %v", q.ScriptStatus,
+			ScriptStatusNone, ScriptStatusOK, ScriptStatusError)
+	}
+
+	if q.Limit < 0 {
+		return fmt.Errorf("invalid limit %v, must not be negative", q.Limit)
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultQueryLimit
+	}
+
+	if q.Limit > MaxQueryLimit {
+		q.Limit = MaxQueryLimit
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return fmt.Errorf("invalid time range, to %v is before from %v", q.To, q.From)
+	}
+
+	return nil
+}
+
+// Matches returns true if the record matches the query filters
+func (q *Query) Matches(r *Record) bool {
+	if !q.From.IsZero() && r.CreatedAt.Before(q.From) {
+		return false
+	}
+
+	if !q.To.IsZero() && r.CreatedAt.After(q.To) {
+		return false
+	}
+
+	if q.HookStatusCode != 0 && r.HookStatusCode != q.HookStatusCode {
+		return false
+	}
+
+	if q.ScriptStatus != "" && r.ScriptStatus != q.ScriptStatus {
+		return false
+	}
+
+	return true
+}
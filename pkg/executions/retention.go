@@ -0,0 +1,71 @@
+package executions
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+//go:generate msgp
+
+// Retention limits the execution history. A zero value disables the limit.
+type Retention struct {
+	MaxRecords        int    `json:"max_records"`          // maximum number of records across all rules
+	MaxRecordsPerRule int    `json:"max_records_per_rule"` // maximum number of records per rule
+	MaxAge            uint64 `json:"max_age"`              // maximum age of a record in milliseconds
+}
+
+// Validate retention data
+func (r *Retention) Validate() error {
+	if r.MaxRecords < 0 {
+		return fmt.Errorf("invalid max_records %v, must not be negative", r.MaxRecords)
+	}
+
+	if r.MaxRecordsPerRule < 0 {
+		return fmt.Errorf("invalid max_records_per_rule %v, must not be negative", r.MaxRecordsPerRule)
+	}
+
+	return nil
+}
+
+// Expired returns the ids of the records which exceed the retention, oldest first. A record is expired if it is
+// older than MaxAge, if it is not among the newest MaxRecordsPerRule of its rule or if it is not among the newest
+// MaxRecords overall.
+func (r *Retention) Expired(records []*Record, now time.Time) []string {
+	sorted := make([]*Record, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	var expired []*Record
+	var retained []*Record
+	perRule := make(map[string]int)
+	for _, record := range sorted {
+		if r.MaxAge > 0 && now.Sub(record.CreatedAt) > time.Millisecond*time.Duration(r.MaxAge) {
+			expired = append(expired, record)
+			continue
+		}
+
+		ruleID := record.Bucket.Rule.ID
+		if r.MaxRecordsPerRule > 0 && perRule[ruleID] >= r.MaxRecordsPerRule {
+			expired = append(expired, record)
+			continue
+		}
+
+		if r.MaxRecords > 0 && len(retained) >= r.MaxRecords {
+			expired = append(expired, record)
+			continue
+		}
+
+		perRule[ruleID]++
+		retained = append(retained, record)
+	}
+
+	ids := make([]string, 0, len(expired))
+	for i := len(expired) - 1; i >= 0; i-- {
+		ids = append(ids, expired[i].ID)
+	}
+
+	return ids
+}
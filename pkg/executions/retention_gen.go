@@ -0,0 +1,145 @@
+package executions
+
+// Code generated by github.com/tinylib/msgp DO NOT EDIT.
+
+import (
+	"github.com/tinylib/msgp/msgp"
+)
+
+// DecodeMsg implements msgp.Decodable
+func (z *Retention) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "MaxRecords":
+			z.MaxRecords, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "MaxRecordsPerRule":
+			z.MaxRecordsPerRule, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "MaxAge":
+			z.MaxAge, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z Retention) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "MaxRecords"
+	err = en.Append(0x83, 0xaa, 0x4d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.MaxRecords)
+	if err != nil {
+		return
+	}
+	// write "MaxRecordsPerRule"
+	err = en.Append(0xb1, 0x4d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x50, 0x65, 0x72, 0x52, 0x75, 0x6c, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.MaxRecordsPerRule)
+	if err != nil {
+		return
+	}
+	// write "MaxAge"
+	err = en.Append(0xa6, 0x4d, 0x61, 0x78, 0x41, 0x67, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.MaxAge)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z Retention) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 3
+	// string "MaxRecords"
+	o = append(o, 0x83, 0xaa, 0x4d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73)
+	o = msgp.AppendInt(o, z.MaxRecords)
+	// string "MaxRecordsPerRule"
+	o = append(o, 0xb1, 0x4d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x50, 0x65, 0x72, 0x52, 0x75, 0x6c, 0x65)
+	o = msgp.AppendInt(o, z.MaxRecordsPerRule)
+	// string "MaxAge"
+	o = append(o, 0xa6, 0x4d, 0x61, 0x78, 0x41, 0x67, 0x65)
+	o = msgp.AppendUint64(o, z.MaxAge)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *Retention) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "MaxRecords":
+			z.MaxRecords, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "MaxRecordsPerRule":
+			z.MaxRecordsPerRule, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "MaxAge":
+			z.MaxAge, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Retention) Msgsize() (s int) {
+	s = 1 + 11 + msgp.IntSize + 18 + msgp.IntSize + 7 + msgp.Uint64Size
+	return
+}
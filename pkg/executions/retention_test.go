@@ -0,0 +1,54 @@
+package executions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/rules"
+)
+
+func newTestRecord(id, ruleID string, age time.Duration, now time.Time) *Record {
+	return &Record{
+		ID:        id,
+		Bucket:    events.Bucket{Rule: rules.Rule{ID: ruleID}},
+		CreatedAt: now.Add(-age),
+	}
+}
+
+func TestRetentionExpired(t *testing.T) {
+	now := time.Now()
+	records := []*Record{
+		newTestRecord("a1", "a", time.Minute*1, now),
+		newTestRecord("a2", "a", time.Minute*2, now),
+		newTestRecord("a3", "a", time.Minute*3, now),
+		newTestRecord("b1", "b", time.Minute*4, now),
+		newTestRecord("b2", "b", time.Hour*2, now),
+	}
+
+	var retentionTests = []struct {
+		name      string
+		retention Retention
+		expected  []string
+	}{
+		{"unlimited", Retention{}, []string{}},
+		{"max age", Retention{MaxAge: uint64(time.Hour / time.Millisecond)}, []string{"b2"}},
+		{"max records per rule", Retention{MaxRecordsPerRule: 1}, []string{"b2", "a3", "a2"}},
+		{"max records", Retention{MaxRecords: 2}, []string{"b2", "b1", "a3"}},
+		{"combined", Retention{MaxRecords: 2, MaxRecordsPerRule: 2, MaxAge: uint64(time.Hour / time.Millisecond)}, []string{"b2", "b1", "a3"}},
+	}
+
+	for _, tc := range retentionTests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.retention.Expired(records, now))
+		})
+	}
+}
+
+func TestRetentionValidate(t *testing.T) {
+	require.NoError(t, (&Retention{MaxRecords: 1}).Validate())
+	require.Error(t, (&Retention{MaxRecords: -1}).Validate())
+	require.Error(t, (&Retention{MaxRecordsPerRule: -1}).Validate())
+}
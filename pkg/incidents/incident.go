@@ -0,0 +1,112 @@
+package incidents
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myntra/cortex/pkg/events"
+)
+
+const (
+	// StatusOpen is the status of an incident which is yet to be acknowledged or resolved
+	StatusOpen = "open"
+	// StatusAcknowledged is the status of an incident which has been acknowledged but not resolved
+	StatusAcknowledged = "acknowledged"
+	// StatusResolved is the status of a resolved incident
+	StatusResolved = "resolved"
+)
+
+const (
+	// ActionOpen is sent when an incident is opened on the first execution
+	ActionOpen = "open"
+	// ActionUpdate is sent when a later execution updates an open incident
+	ActionUpdate = "update"
+	// ActionResolve is sent when an incident is resolved by an event or the api
+	ActionResolve = "resolve"
+)
+
+//go:generate msgp
+
+// Incident tracks the executions of a rule for a group key until it is resolved
+type Incident struct {
+	ID             string    `json:"id"`
+	RuleID         string    `json:"rule_id"`
+	GroupKey       string    `json:"group_key"`
+	Status         string    `json:"status"`
+	RecordIDs      []string  `json:"record_ids"`  // execution records which opened or updated the incident
+	ResolvedBy     string    `json:"resolved_by"` // event id of the resolving event, or "api"
+	OpenedAt       time.Time `json:"opened_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+	ResolvedAt     time.Time `json:"resolved_at"`
+}
+
+// IsActive returns true if the incident is not resolved
+func (i *Incident) IsActive() bool {
+	return i.Status != StatusResolved
+}
+
+// Clone returns a copy of the incident
+func (i *Incident) Clone() *Incident {
+	clone := *i
+	clone.RecordIDs = append([]string(nil), i.RecordIDs...)
+	return &clone
+}
+
+// Notification is posted to the rule's incident hook endpoint when an incident changes state
+type Notification struct {
+	Action   string    `json:"action"` // open, update or resolve
+	Incident *Incident `json:"incident"`
+}
+
+// GroupKey evaluates a dotted path(eg: source, data.host, extensions.team) against the event's json representation.
+// An empty path returns an empty group key, i.e one incident per rule.
+func GroupKey(path string, event *events.Event) string {
+	if path == "" || event == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return ""
+	}
+
+	var current interface{}
+	if err := json.Unmarshal(b, &current); err != nil {
+		return ""
+	}
+
+	for _, field := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if current, ok = m[field]; !ok {
+			return ""
+		}
+	}
+
+	if current == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", current)
+}
+
+// GroupKeys returns the distinct group keys of the events, in the order of their first event.
+func GroupKeys(path string, evs []*events.Event) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, event := range evs {
+		key := GroupKey(path, event)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,493 @@
+package incidents
+
+// Code generated by github.com/tinylib/msgp DO NOT EDIT.
+
+import (
+	"github.com/tinylib/msgp/msgp"
+)
+
+// DecodeMsg implements msgp.Decodable
+func (z *Incident) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ID":
+			z.ID, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "RuleID":
+			z.RuleID, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "GroupKey":
+			z.GroupKey, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Status":
+			z.Status, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "RecordIDs":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.RecordIDs) >= int(zb0002) {
+				z.RecordIDs = (z.RecordIDs)[:zb0002]
+			} else {
+				z.RecordIDs = make([]string, zb0002)
+			}
+			for za0001 := range z.RecordIDs {
+				z.RecordIDs[za0001], err = dc.ReadString()
+				if err != nil {
+					return
+				}
+			}
+		case "ResolvedBy":
+			z.ResolvedBy, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "OpenedAt":
+			z.OpenedAt, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
+		case "UpdatedAt":
+			z.UpdatedAt, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
+		case "AcknowledgedAt":
+			z.AcknowledgedAt, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
+		case "ResolvedAt":
+			z.ResolvedAt, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *Incident) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 10
+	// write "ID"
+	err = en.Append(0x8a, 0xa2, 0x49, 0x44)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ID)
+	if err != nil {
+		return
+	}
+	// write "RuleID"
+	err = en.Append(0xa6, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.RuleID)
+	if err != nil {
+		return
+	}
+	// write "GroupKey"
+	err = en.Append(0xa8, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.GroupKey)
+	if err != nil {
+		return
+	}
+	// write "Status"
+	err = en.Append(0xa6, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Status)
+	if err != nil {
+		return
+	}
+	// write "RecordIDs"
+	err = en.Append(0xa9, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.RecordIDs)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.RecordIDs {
+		err = en.WriteString(z.RecordIDs[za0001])
+		if err != nil {
+			return
+		}
+	}
+	// write "ResolvedBy"
+	err = en.Append(0xaa, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x42, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ResolvedBy)
+	if err != nil {
+		return
+	}
+	// write "OpenedAt"
+	err = en.Append(0xa8, 0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.OpenedAt)
+	if err != nil {
+		return
+	}
+	// write "UpdatedAt"
+	err = en.Append(0xa9, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.UpdatedAt)
+	if err != nil {
+		return
+	}
+	// write "AcknowledgedAt"
+	err = en.Append(0xae, 0x41, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.AcknowledgedAt)
+	if err != nil {
+		return
+	}
+	// write "ResolvedAt"
+	err = en.Append(0xaa, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x41, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.ResolvedAt)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *Incident) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 10
+	// string "ID"
+	o = append(o, 0x8a, 0xa2, 0x49, 0x44)
+	o = msgp.AppendString(o, z.ID)
+	// string "RuleID"
+	o = append(o, 0xa6, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x44)
+	o = msgp.AppendString(o, z.RuleID)
+	// string "GroupKey"
+	o = append(o, 0xa8, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.GroupKey)
+	// string "Status"
+	o = append(o, 0xa6, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73)
+	o = msgp.AppendString(o, z.Status)
+	// string "RecordIDs"
+	o = append(o, 0xa9, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.RecordIDs)))
+	for za0001 := range z.RecordIDs {
+		o = msgp.AppendString(o, z.RecordIDs[za0001])
+	}
+	// string "ResolvedBy"
+	o = append(o, 0xaa, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x42, 0x79)
+	o = msgp.AppendString(o, z.ResolvedBy)
+	// string "OpenedAt"
+	o = append(o, 0xa8, 0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.OpenedAt)
+	// string "UpdatedAt"
+	o = append(o, 0xa9, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.UpdatedAt)
+	// string "AcknowledgedAt"
+	o = append(o, 0xae, 0x41, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.AcknowledgedAt)
+	// string "ResolvedAt"
+	o = append(o, 0xaa, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x41, 0x74)
+	o = msgp.AppendTime(o, z.ResolvedAt)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *Incident) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "ID":
+			z.ID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "RuleID":
+			z.RuleID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "GroupKey":
+			z.GroupKey, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Status":
+			z.Status, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "RecordIDs":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.RecordIDs) >= int(zb0002) {
+				z.RecordIDs = (z.RecordIDs)[:zb0002]
+			} else {
+				z.RecordIDs = make([]string, zb0002)
+			}
+			for za0001 := range z.RecordIDs {
+				z.RecordIDs[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "ResolvedBy":
+			z.ResolvedBy, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "OpenedAt":
+			z.OpenedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
+		case "UpdatedAt":
+			z.UpdatedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
+		case "AcknowledgedAt":
+			z.AcknowledgedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
+		case "ResolvedAt":
+			z.ResolvedAt, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Incident) Msgsize() (s int) {
+	s = 1 + 3 + msgp.StringPrefixSize + len(z.ID) + 7 + msgp.StringPrefixSize + len(z.RuleID) + 9 + msgp.StringPrefixSize + len(z.GroupKey) + 7 + msgp.StringPrefixSize + len(z.Status) + 10 + msgp.ArrayHeaderSize
+	for za0001 := range z.RecordIDs {
+		s += msgp.StringPrefixSize + len(z.RecordIDs[za0001])
+	}
+	s += 11 + msgp.StringPrefixSize + len(z.ResolvedBy) + 9 + msgp.TimeSize + 10 + msgp.TimeSize + 15 + msgp.TimeSize + 11 + msgp.TimeSize
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *Notification) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "Action":
+			z.Action, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Incident":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Incident = nil
+			} else {
+				if z.Incident == nil {
+					z.Incident = new(Incident)
+				}
+				err = z.Incident.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *Notification) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 2
+	// write "Action"
+	err = en.Append(0x82, 0xa6, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Action)
+	if err != nil {
+		return
+	}
+	// write "Incident"
+	err = en.Append(0xa8, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74)
+	if err != nil {
+		return
+	}
+	if z.Incident == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Incident.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *Notification) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 2
+	// string "Action"
+	o = append(o, 0x82, 0xa6, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e)
+	o = msgp.AppendString(o, z.Action)
+	// string "Incident"
+	o = append(o, 0xa8, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74)
+	if z.Incident == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Incident.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *Notification) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "Action":
+			z.Action, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Incident":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Incident = nil
+			} else {
+				if z.Incident == nil {
+					z.Incident = new(Incident)
+				}
+				bts, err = z.Incident.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Notification) Msgsize() (s int) {
+	s = 1 + 7 + msgp.StringPrefixSize + len(z.Action) + 9
+	if z.Incident == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Incident.Msgsize()
+	}
+	return
+}
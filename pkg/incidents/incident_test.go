@@ -0,0 +1,65 @@
+package incidents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+var testevent = &events.Event{
+	EventType:          "acme.prod.icinga.check_disk",
+	EventTypeVersion:   "1.0",
+	CloudEventsVersion: "0.1",
+	Source:             "/sink",
+	EventID:            "42",
+	EventTime:          time.Now(),
+	ContentType:        "application/json",
+	Data:               map[string]interface{}{"host": "node1", "check": map[string]interface{}{"id": 7}},
+	Extensions:         map[string]string{"team": "search"},
+}
+
+var groupKeyTests = []struct {
+	path     string
+	expected string
+}{
+	{"", ""},
+	{"source", "/sink"},
+	{"eventType", "acme.prod.icinga.check_disk"},
+	{"data.host", "node1"},
+	{"data.check.id", "7"},
+	{"extensions.team", "search"},
+	{"data.missing", ""},
+	{"data.host.name", ""},
+}
+
+func TestGroupKey(t *testing.T) {
+	for _, tc := range groupKeyTests {
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.expected, GroupKey(tc.path, testevent))
+		})
+	}
+}
+
+func TestGroupKeys(t *testing.T) {
+	other := *testevent
+	other.Data = map[string]interface{}{"host": "node2"}
+	missing := *testevent
+	missing.Data = nil
+
+	evs := []*events.Event{testevent, &other, testevent, &missing}
+	require.Equal(t, []string{"node1", "node2", ""}, GroupKeys("data.host", evs))
+	require.Equal(t, []string{""}, GroupKeys("", evs))
+	require.Nil(t, GroupKeys("data.host", nil))
+}
+
+func TestIncidentClone(t *testing.T) {
+	incident := &Incident{ID: "1", Status: StatusOpen, RecordIDs: []string{"a"}}
+	clone := incident.Clone()
+	clone.RecordIDs = append(clone.RecordIDs, "b")
+	clone.RecordIDs[0] = "c"
+
+	require.Equal(t, []string{"a"}, incident.RecordIDs)
+	require.True(t, clone.IsActive())
+}
@@ -0,0 +1,107 @@
+package inputs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/schemas"
+	"github.com/myntra/cortex/pkg/store"
+)
+
+// Node is the part of store.Node used by the push inputs
+type Node interface {
+	LeaderAddr() string
+	Stash(event *events.Event) error
+	IngestBatch(batch []*events.Event) ([]*store.IngestResult, error)
+}
+
+// ForwardError is an error posting events to the leader. Status is 0 if the leader couldn't be reached
+type ForwardError struct {
+	Leader string
+	Path   string
+	Status int
+	Err    error
+}
+
+func (e *ForwardError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("error forwarding to leader %s: %v", e.Leader, e.Err)
+	}
+	return fmt.Sprintf("leader %s rejected %s: %v", e.Leader, e.Path, e.Err)
+}
+
+// IsRetryable returns true if the events can be stashed again later: the cluster has no leader, a schema can't be
+// compiled, the leader couldn't be reached or failed with a server error. Other errors, e.g. a leader rejecting an
+// invalid event, are final
+func IsRetryable(err error) bool {
+	if store.IsUnavailable(err) || schemas.IsSchemaError(err) {
+		return true
+	}
+
+	if fe, ok := err.(*ForwardError); ok {
+		return fe.Status == 0 || fe.Status >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// Forwarder stashes the events of push inputs, which listen on every node of the cluster. Events received
+// by a follower are posted to the leader's /event or /events/batch endpoint
+type Forwarder struct {
+	node   Node
+	client *http.Client
+}
+
+// NewForwarder returns a forwarder for the node
+func NewForwarder(node Node) *Forwarder {
+	return &Forwarder{
+		node:   node,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Stash the event on the leader
+func (f *Forwarder) Stash(event *events.Event) error {
+	leaderAddr := f.node.LeaderAddr()
+	if leaderAddr == "" {
+		return f.node.Stash(event)
+	}
+
+	return f.post(leaderAddr, "/event", event)
+}
+
+// Ingest the batch on the leader, like the events decoded by the http sinks
+func (f *Forwarder) Ingest(batch []*events.Event) error {
+	leaderAddr := f.node.LeaderAddr()
+	if leaderAddr == "" {
+		_, err := f.node.IngestBatch(batch)
+		return err
+	}
+
+	return f.post(leaderAddr, "/events/batch", batch)
+}
+
+func (f *Forwarder) post(leaderAddr, path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.client.Post("http://"+leaderAddr+path, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return &ForwardError{Leader: leaderAddr, Path: path, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return &ForwardError{Leader: leaderAddr, Path: path, Status: resp.StatusCode, Err: fmt.Errorf("%s %s", resp.Status, body)}
+	}
+
+	return nil
+}
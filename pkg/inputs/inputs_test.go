@@ -0,0 +1,100 @@
+package inputs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/store"
+	"github.com/stretchr/testify/require"
+)
+
+type testNode struct {
+	leaderAddr string
+	stashed    []*events.Event
+}
+
+func (n *testNode) LeaderAddr() string {
+	return n.leaderAddr
+}
+
+func (n *testNode) Stash(event *events.Event) error {
+	n.stashed = append(n.stashed, event)
+	return nil
+}
+
+func (n *testNode) IngestBatch(batch []*events.Event) ([]*store.IngestResult, error) {
+	n.stashed = append(n.stashed, batch...)
+	return nil, nil
+}
+
+func TestForwarder(t *testing.T) {
+	var forwarded []*events.Event
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/event", r.URL.Path)
+		event := &events.Event{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(event))
+		forwarded = append(forwarded, event)
+		switch event.EventID {
+		case "bad":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer leader.Close()
+
+	node := &testNode{}
+	forwarder := NewForwarder(node)
+
+	// the leader stashes locally
+	require.NoError(t, forwarder.Stash(&events.Event{EventID: "1"}))
+	require.Len(t, node.stashed, 1)
+
+	// a follower forwards to the leader
+	node.leaderAddr = strings.TrimPrefix(leader.URL, "http://")
+	require.NoError(t, forwarder.Stash(&events.Event{EventID: "2"}))
+	err := forwarder.Stash(&events.Event{EventID: "bad"})
+	require.Error(t, err)
+	require.True(t, IsRetryable(err))
+	require.Len(t, node.stashed, 1)
+	require.Len(t, forwarded, 2)
+	require.Equal(t, "2", forwarded[0].EventID)
+
+	// the leader rejecting the event is final
+	err = forwarder.Stash(&events.Event{EventID: "invalid"})
+	require.Error(t, err)
+	require.False(t, IsRetryable(err))
+
+	// an unreachable leader is retried
+	leader.Close()
+	err = forwarder.Stash(&events.Event{EventID: "3"})
+	require.Error(t, err)
+	require.True(t, IsRetryable(err))
+}
+
+func TestForwarderIngest(t *testing.T) {
+	var forwarded []*events.Event
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/events/batch", r.URL.Path)
+		var batch []*events.Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		forwarded = append(forwarded, batch...)
+	}))
+	defer leader.Close()
+
+	node := &testNode{}
+	forwarder := NewForwarder(node)
+
+	require.NoError(t, forwarder.Ingest([]*events.Event{{EventID: "1"}, {EventID: "2"}}))
+	require.Len(t, node.stashed, 2)
+
+	node.leaderAddr = strings.TrimPrefix(leader.URL, "http://")
+	require.NoError(t, forwarder.Ingest([]*events.Event{{EventID: "3"}, {EventID: "4"}}))
+	require.Len(t, node.stashed, 2)
+	require.Len(t, forwarded, 2)
+	require.Equal(t, "4", forwarded[1].EventID)
+}
@@ -0,0 +1,327 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	leaderCheckInterval = time.Second
+	retryInterval       = time.Second
+	defaultResync       = 10 * time.Minute
+)
+
+// Node is the part of store.Node used by the watcher
+type Node interface {
+	IsLeader() bool
+	Stash(event *events.Event) error
+	GetCheckpoint(inputID string) time.Time
+	SetCheckpoint(inputID string, readUntil time.Time) error
+}
+
+// Watcher stashes the events of a kubernetes cluster with the event type k8s.<cluster>.<namespace>.<kind>.<reason>
+// and the involved object in the data. It only watches while the node is the raft leader.
+//
+// The last seen time of the stashed events is replicated as the checkpoint of the cluster, and the events last
+// seen before the checkpoint are skipped when listed, so a new leader resumes where the previous one stopped.
+// The listed events arrive in any order, so the checkpoint only moves once all of them are stashed.
+// Events last seen in the second of the checkpoint are stashed again, with the same event id. Without a
+// checkpoint the watch starts from the time the first leader started it.
+type Watcher struct {
+	node       Node
+	client     kubernetes.Interface
+	cluster    string
+	namespace  string
+	types      map[string]bool
+	resync     time.Duration
+	retryDelay time.Duration
+
+	mu        sync.Mutex
+	readUntil time.Time   // last seen time of the stashed events, replicated by saveCheckpoint
+	listed    func() bool // true once the listed events of the watch are handled
+	aborted   bool        // an event of the watch was not stashed, the checkpoint can't move past it
+
+	leaderCheckInterval time.Duration
+}
+
+// Enabled returns true if the cluster to watch is configured
+func Enabled(cfg *config.Config) bool {
+	return cfg.K8sCluster != ""
+}
+
+// New returns a watcher of the cluster of the kubeconfig, or of the cluster cortex runs in if it is not set
+func New(cfg *config.Config, node Node) (*Watcher, error) {
+	if cfg.K8sCluster == "" {
+		return nil, fmt.Errorf("k8s_cluster is not set")
+	}
+
+	restCfg, err := clientcmd.BuildConfigFromFlags("", cfg.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %v", err)
+	}
+
+	client, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the kubernetes client: %v", err)
+	}
+
+	return newWatcher(cfg, node, client), nil
+}
+
+func newWatcher(cfg *config.Config, node Node, client kubernetes.Interface) *Watcher {
+	w := &Watcher{
+		node:                node,
+		client:              client,
+		cluster:             cfg.K8sCluster,
+		namespace:           cfg.K8sNamespace,
+		resync:              time.Duration(cfg.K8sResync) * time.Second,
+		retryDelay:          retryInterval,
+		leaderCheckInterval: leaderCheckInterval,
+	}
+
+	if w.resync <= 0 {
+		w.resync = defaultResync
+	}
+
+	for _, t := range strings.Split(cfg.K8sEventTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			if w.types == nil {
+				w.types = make(map[string]bool)
+			}
+			w.types[t] = true
+		}
+	}
+
+	return w
+}
+
+// Run watches while the node is the leader, stopping on leadership loss, until the context is done
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.leaderCheckInterval)
+	defer ticker.Stop()
+
+	var cancel context.CancelFunc
+	for {
+		leader := w.node.IsLeader()
+		switch {
+		case leader && cancel == nil:
+			glog.Infof("k8s: leader, watching the events of cluster %s", w.cluster)
+			cancel = w.start(ctx)
+		case !leader && cancel != nil:
+			glog.Info("k8s: leadership lost, stopping the watch")
+			cancel()
+			cancel = nil
+		case leader:
+			w.saveCheckpoint()
+		}
+
+		select {
+		case <-ctx.Done():
+			if cancel != nil {
+				cancel()
+				w.saveCheckpoint()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkpointID is the input id of the cluster's checkpoint
+func (w *Watcher) checkpointID() string {
+	return "k8s." + w.cluster
+}
+
+// saveCheckpoint replicates the last seen time of the stashed events if it moved forward, and every event seen
+// before it is stashed
+func (w *Watcher) saveCheckpoint() {
+	w.mu.Lock()
+	readUntil, listed, aborted := w.readUntil, w.listed, w.aborted
+	w.mu.Unlock()
+
+	if listed == nil || !listed() || aborted {
+		return
+	}
+
+	if !readUntil.After(w.node.GetCheckpoint(w.checkpointID())) {
+		return
+	}
+
+	if err := w.node.SetCheckpoint(w.checkpointID(), readUntil); err != nil {
+		glog.Errorf("k8s: error saving the checkpoint %v: %v", readUntil, err)
+	}
+}
+
+// read moves the last seen time of the stashed events forward
+func (w *Watcher) read(e *corev1.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if seen := lastSeen(e); seen.After(w.readUntil) {
+		w.readUntil = seen
+	}
+}
+
+// abort stops the checkpoint of the watch at the event which was not stashed
+func (w *Watcher) abort(e *corev1.Event) {
+	glog.Errorf("k8s: stash of event %s aborted, the checkpoint stops before it", e.UID)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.aborted = true
+}
+
+// start an informer of the events. The informer lists the events and watches them from the listed resource
+// version, listing again if the watch can't be resumed
+func (w *Watcher) start(ctx context.Context) context.CancelFunc {
+	ctx, cancel := context.WithCancel(ctx)
+
+	since := w.node.GetCheckpoint(w.checkpointID())
+	if since.IsZero() {
+		since = time.Now()
+		if err := w.node.SetCheckpoint(w.checkpointID(), since); err != nil {
+			glog.Errorf("k8s: error saving the checkpoint %v: %v", since, err)
+		}
+	}
+	// the timestamps of the events have a second precision
+	since = since.Truncate(time.Second)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(w.client, w.resync, informers.WithNamespace(w.namespace))
+	informer := factory.Core().V1().Events().Informer()
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if e, ok := obj.(*corev1.Event); ok && !lastSeen(e).Before(since) {
+				w.stash(ctx, e)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			old, ok := oldObj.(*corev1.Event)
+			e, newOk := newObj.(*corev1.Event)
+			// resyncs deliver unchanged events
+			if ok && newOk && old.ResourceVersion != e.ResourceVersion {
+				w.stash(ctx, e)
+			}
+		},
+	})
+	if err != nil {
+		glog.Errorf("k8s: error adding the event handler %v", err)
+	}
+	informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		glog.Errorf("k8s: watch error %v", err)
+	})
+
+	w.mu.Lock()
+	w.listed, w.aborted = nil, false
+	if registration != nil {
+		w.listed = registration.HasSynced
+	}
+	w.mu.Unlock()
+
+	factory.Start(ctx.Done())
+	return cancel
+}
+
+// stash retries the event while the cluster has no leader and the node is still the leader
+func (w *Watcher) stash(ctx context.Context, e *corev1.Event) {
+	if w.types != nil && !w.types[e.Type] {
+		return
+	}
+
+	event := w.event(e)
+	for {
+		err := w.node.Stash(event)
+		if err == nil {
+			w.read(e)
+			return
+		}
+
+		if !store.IsUnavailable(err) {
+			glog.Errorf("k8s: error stashing event %s: %v", event.EventID, err)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			w.abort(e)
+			return
+		case <-time.After(w.retryDelay):
+		}
+
+		if !w.node.IsLeader() {
+			w.abort(e)
+			return
+		}
+	}
+}
+
+// event converts a kubernetes event
+func (w *Watcher) event(e *corev1.Event) *events.Event {
+	object := e.InvolvedObject
+	count := e.Count
+	if e.Series != nil {
+		count = e.Series.Count
+	}
+
+	return &events.Event{
+		EventType: strings.Join([]string{
+			"k8s",
+			sinks.Segment(w.cluster),
+			sinks.Segment(object.Namespace),
+			sinks.Segment(object.Kind),
+			sinks.Segment(e.Reason),
+		}, "."),
+		EventTypeVersion:   "1.0",
+		CloudEventsVersion: "0.1",
+		Source:             "k8s",
+		EventID:            fmt.Sprintf("%s:%s:%d", w.cluster, e.UID, count),
+		EventTime:          lastSeen(e),
+		ContentType:        "application/json",
+		Data: map[string]interface{}{
+			"cluster":   w.cluster,
+			"namespace": e.Namespace,
+			"name":      e.Name,
+			"type":      e.Type,
+			"reason":    e.Reason,
+			"message":   e.Message,
+			"count":     count,
+			"component": e.Source.Component,
+			"host":      e.Source.Host,
+			"involved_object": map[string]interface{}{
+				"kind":             object.Kind,
+				"namespace":        object.Namespace,
+				"name":             object.Name,
+				"uid":              string(object.UID),
+				"api_version":      object.APIVersion,
+				"resource_version": object.ResourceVersion,
+				"field_path":       object.FieldPath,
+			},
+		},
+	}
+}
+
+// lastSeen returns the last time the event occurred
+func lastSeen(e *corev1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	}
+	return e.CreationTimestamp.Time
+}
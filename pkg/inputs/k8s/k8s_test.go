@@ -0,0 +1,257 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/hashicorp/raft"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeNode struct {
+	mu          sync.Mutex
+	leader      bool
+	failures    int                      // stashes failing with raft.ErrNotLeader
+	blocked     map[string]chan struct{} // [eventID] stashes waiting for the channel to close
+	stashed     []*events.Event
+	checkpoints map[string]time.Time
+}
+
+func (n *fakeNode) GetCheckpoint(inputID string) time.Time {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.checkpoints[inputID]
+}
+
+func (n *fakeNode) SetCheckpoint(inputID string, readUntil time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.checkpoints == nil {
+		n.checkpoints = make(map[string]time.Time)
+	}
+	if readUntil.After(n.checkpoints[inputID]) {
+		n.checkpoints[inputID] = readUntil
+	}
+	return nil
+}
+
+func (n *fakeNode) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.leader
+}
+
+func (n *fakeNode) setLeader(leader bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.leader = leader
+}
+
+func (n *fakeNode) Stash(event *events.Event) error {
+	n.mu.Lock()
+	blocked := n.blocked[event.EventID]
+	n.mu.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures > 0 {
+		n.failures--
+		return raft.ErrNotLeader
+	}
+	n.stashed = append(n.stashed, event)
+	return nil
+}
+
+func (n *fakeNode) events() []*events.Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*events.Event(nil), n.stashed...)
+}
+
+func (n *fakeNode) waitEvents(t *testing.T, count int) []*events.Event {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, backoff.Retry(func() error {
+		if got := len(n.events()); got != count {
+			return fmt.Errorf("%d events stashed, expected %d", got, count)
+		}
+		return nil
+	}, b))
+	return n.events()
+}
+
+func k8sEvent(name, eventType, reason string, count int32, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "shop",
+			UID:       types.UID("uid-" + name),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:       "Pod",
+			Namespace:  "shop",
+			Name:       "checkout-1",
+			UID:        "pod-uid",
+			APIVersion: "v1",
+			FieldPath:  "spec.containers{checkout}",
+		},
+		Type:          eventType,
+		Reason:        reason,
+		Message:       "Back-off restarting failed container",
+		Count:         count,
+		Source:        corev1.EventSource{Component: "kubelet", Host: "node-1"},
+		LastTimestamp: metav1.NewTime(last),
+	}
+}
+
+func testWatcher(node Node, client *fake.Clientset) *Watcher {
+	w := newWatcher(&config.Config{K8sCluster: "prod.eu", K8sEventTypes: "Warning"}, node, client)
+	w.leaderCheckInterval = 10 * time.Millisecond
+	w.retryDelay = 10 * time.Millisecond
+	return w
+}
+
+func TestWatcherEvent(t *testing.T) {
+	// events last seen before the watcher started are skipped
+	old := k8sEvent("old", "Warning", "BackOff", 1, time.Now().Add(-time.Hour))
+	client := fake.NewSimpleClientset(old)
+	node := &fakeNode{leader: true, failures: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go testWatcher(node, client).Run(ctx)
+
+	e := k8sEvent("backoff", "Warning", "BackOff", 1, time.Now())
+	_, err := client.CoreV1().Events("shop").Create(ctx, e, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// filtered by type
+	_, err = client.CoreV1().Events("shop").Create(ctx, k8sEvent("pulled", "Normal", "Pulled", 1, time.Now()), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	stashed := node.waitEvents(t, 1)
+	event := stashed[0]
+	require.Equal(t, "k8s.prod_eu.shop.Pod.BackOff", event.EventType)
+	require.Equal(t, "prod.eu:uid-backoff:1", event.EventID)
+	require.Equal(t, "k8s", event.Source)
+	require.Equal(t, e.LastTimestamp.Unix(), event.EventTime.Unix())
+
+	data := event.Data.(map[string]interface{})
+	require.Equal(t, "Back-off restarting failed container", data["message"])
+	require.Equal(t, "node-1", data["host"])
+	require.Equal(t, map[string]interface{}{
+		"kind":             "Pod",
+		"namespace":        "shop",
+		"name":             "checkout-1",
+		"uid":              "pod-uid",
+		"api_version":      "v1",
+		"resource_version": "",
+		"field_path":       "spec.containers{checkout}",
+	}, data["involved_object"])
+
+	// a repeated event is stashed with its new count
+	e.Count = 2
+	e.ResourceVersion = "2"
+	_, err = client.CoreV1().Events("shop").Update(ctx, e, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	stashed = node.waitEvents(t, 2)
+	require.Equal(t, "prod.eu:uid-backoff:2", stashed[1].EventID)
+}
+
+func TestWatcherLeadership(t *testing.T) {
+	// the checkpoint replicated by the previous leader
+	scheduled := time.Now().Add(-30 * time.Second).Truncate(time.Second)
+	client := fake.NewSimpleClientset(k8sEvent("old", "Warning", "BackOff", 1, scheduled.Add(-time.Minute)))
+	node := &fakeNode{checkpoints: map[string]time.Time{"k8s.prod.eu": scheduled.Add(-10 * time.Second)}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go testWatcher(node, client).Run(ctx)
+
+	_, err := client.CoreV1().Events("shop").Create(ctx, k8sEvent("scheduling", "Warning", "FailedScheduling", 1, scheduled), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, node.events())
+
+	// the new leader lists the events seen since the checkpoint
+	node.setLeader(true)
+	require.Equal(t, "k8s.prod_eu.shop.Pod.FailedScheduling", node.waitEvents(t, 1)[0].EventType)
+
+	// and replicates the last seen time of the stashed events
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, backoff.Retry(func() error {
+		if checkpoint := node.GetCheckpoint("k8s.prod.eu"); !checkpoint.Equal(scheduled) {
+			return fmt.Errorf("checkpoint %v, expected %v", checkpoint, scheduled)
+		}
+		return nil
+	}, b))
+
+	node.setLeader(false)
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = client.CoreV1().Events("shop").Create(ctx, k8sEvent("oom", "Warning", "OOMKilled", 1, scheduled.Add(10*time.Second)), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, node.events(), 1)
+
+	// events seen before the checkpoint are not listed again, the ones seen in its second are
+	node.setLeader(true)
+	stashed := node.waitEvents(t, 3)
+	types := []string{stashed[1].EventType, stashed[2].EventType}
+	require.ElementsMatch(t, []string{"k8s.prod_eu.shop.Pod.FailedScheduling", "k8s.prod_eu.shop.Pod.OOMKilled"}, types)
+	require.Equal(t, "prod.eu:uid-scheduling:1", stashed[0].EventID)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, node.events(), 3)
+}
+
+func TestWatcherListedCheckpoint(t *testing.T) {
+	checkpoint := time.Now().Add(-time.Minute).Truncate(time.Second)
+	client := fake.NewSimpleClientset(
+		k8sEvent("a-later", "Warning", "BackOff", 1, checkpoint.Add(20*time.Second)),
+		k8sEvent("b-earlier", "Warning", "BackOff", 1, checkpoint.Add(10*time.Second)),
+	)
+	release := make(chan struct{})
+	node := &fakeNode{
+		leader:      true,
+		checkpoints: map[string]time.Time{"k8s.prod.eu": checkpoint},
+		blocked:     map[string]chan struct{}{"prod.eu:uid-b-earlier:1": release},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go testWatcher(node, client).Run(ctx)
+
+	// the listed events arrive in any order: the checkpoint doesn't move past the earlier event while it is stashed
+	time.Sleep(200 * time.Millisecond)
+	require.True(t, checkpoint.Equal(node.GetCheckpoint("k8s.prod.eu")))
+
+	close(release)
+	node.waitEvents(t, 2)
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, backoff.Retry(func() error {
+		if got := node.GetCheckpoint("k8s.prod.eu"); !got.Equal(checkpoint.Add(20 * time.Second)) {
+			return fmt.Errorf("checkpoint %v, expected %v", got, checkpoint.Add(20*time.Second))
+		}
+		return nil
+	}, b))
+}
@@ -0,0 +1,267 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/store"
+)
+
+const (
+	leaderCheckInterval = time.Second
+	retryInterval       = 5 * time.Second
+)
+
+// Node is the part of store.Node used by the consumer
+type Node interface {
+	IsLeader() bool
+	Stash(event *events.Event) error
+	GetSink(id string) *sinks.GenericSink
+}
+
+// consumerGroup is implemented by sarama.ConsumerGroup
+type consumerGroup interface {
+	Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error
+	Errors() <-chan error
+	Close() error
+}
+
+// Consumer reads events from kafka topics as part of a consumer group and stashes them. It only consumes
+// while the node is the raft leader, and marks a message's offset for commit once its events are stashed
+type Consumer struct {
+	node     Node
+	sinks    *sinks.Registry
+	topics   []string
+	sink     string
+	newGroup func() (consumerGroup, error)
+
+	leaderCheckInterval time.Duration
+	retryInterval       time.Duration
+}
+
+// New returns a consumer for the kafka config, decoding with the registry's sinks or the node's json sinks
+func New(cfg *config.Config, node Node, registry *sinks.Registry) (*Consumer, error) {
+	if cfg.KafkaBrokers == "" {
+		return nil, fmt.Errorf("kafka_brokers is not set")
+	}
+
+	version, err := sarama.ParseKafkaVersion(cfg.KafkaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kafka_version: %v", err)
+	}
+
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Version = version
+	saramaCfg.ClientID = "cortex"
+	saramaCfg.Consumer.Return.Errors = true
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	brokers := splitList(cfg.KafkaBrokers)
+	c := newConsumer(node, registry, splitList(cfg.KafkaTopics), cfg.KafkaSink, func() (consumerGroup, error) {
+		return sarama.NewConsumerGroup(brokers, cfg.KafkaGroup, saramaCfg)
+	})
+
+	return c, nil
+}
+
+func newConsumer(node Node, registry *sinks.Registry, topics []string, sink string, newGroup func() (consumerGroup, error)) *Consumer {
+	return &Consumer{
+		node:                node,
+		sinks:               registry,
+		topics:              topics,
+		sink:                sink,
+		newGroup:            newGroup,
+		leaderCheckInterval: leaderCheckInterval,
+		retryInterval:       retryInterval,
+	}
+}
+
+// Run consumes while the node is the leader, pausing on leadership loss, until the context is done
+func (c *Consumer) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.leaderCheckInterval)
+	defer ticker.Stop()
+
+	var running *consumption
+	for {
+		leader := c.node.IsLeader()
+		switch {
+		case leader && running == nil:
+			glog.Infof("kafka: leader, consuming topics %v", c.topics)
+			running = c.start(ctx)
+		case !leader && running != nil:
+			glog.Info("kafka: leadership lost, pausing the consumer")
+			running.stop()
+			running = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if running != nil {
+				running.stop()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// consumption is a running consume loop
+type consumption struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (c *Consumer) start(ctx context.Context) *consumption {
+	ctx, cancel := context.WithCancel(ctx)
+	running := &consumption{cancel: cancel, done: make(chan struct{})}
+	go c.consume(ctx, running.done)
+	return running
+}
+
+// stop the consume loop and wait for it to leave the consumer group
+func (r *consumption) stop() {
+	r.cancel()
+	<-r.done
+}
+
+// consume joins the consumer group and consumes the topics until the context is done
+func (c *Consumer) consume(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for ctx.Err() == nil {
+		group, err := c.newGroup()
+		if err != nil {
+			glog.Errorf("kafka: error creating the consumer group %v", err)
+			c.wait(ctx)
+			continue
+		}
+
+		go func() {
+			for err := range group.Errors() {
+				glog.Errorf("kafka: consumer group error %v", err)
+			}
+		}()
+
+		// Consume returns at the end of every session, e.g. on a rebalance or a stash error
+		for ctx.Err() == nil {
+			handler := &groupHandler{c: c}
+			err := group.Consume(ctx, c.topics, handler)
+			if err == nil {
+				handler.mu.Lock()
+				err = handler.err
+				handler.mu.Unlock()
+			}
+			if err != nil {
+				glog.Errorf("kafka: consumer group session ended %v", err)
+				c.wait(ctx)
+			}
+		}
+
+		if err := group.Close(); err != nil {
+			glog.Errorf("kafka: error closing the consumer group %v", err)
+		}
+	}
+}
+
+func (c *Consumer) wait(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(c.retryInterval):
+	}
+}
+
+// decode converts a message into events with the configured sink, or as a cloudevents json event
+func (c *Consumer) decode(payload []byte) ([]*events.Event, error) {
+	if c.sink == "" {
+		event := &events.Event{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			return nil, err
+		}
+		return []*events.Event{event}, nil
+	}
+
+	if sink := c.sinks.Get(c.sink); sink != nil {
+		return sink.Decode(payload)
+	}
+
+	if sink := c.node.GetSink(c.sink); sink != nil {
+		return sink.Decode(payload)
+	}
+
+	return nil, fmt.Errorf("sink %s not found", c.sink)
+}
+
+// stash returns an error only if the message should be consumed again
+func (c *Consumer) stash(msg *sarama.ConsumerMessage) error {
+	batch, err := c.decode(msg.Value)
+	if err != nil {
+		glog.Errorf("kafka: skipping undecodable message %s/%d/%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+		return nil
+	}
+
+	for _, event := range batch {
+		if err := c.node.Stash(event); err != nil {
+			if store.IsUnavailable(err) {
+				return err
+			}
+			glog.Errorf("kafka: error stashing event %s from message %s/%d/%d: %v", event.EventID, msg.Topic, msg.Partition, msg.Offset, err)
+		}
+	}
+
+	return nil
+}
+
+type groupHandler struct {
+	c   *Consumer
+	mu  sync.Mutex
+	err error // stash error which ended the session
+}
+
+func (h *groupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *groupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim marks each message once it is stashed. The marked offsets are committed by sarama, so a message
+// whose stash failed is consumed again by the next session
+func (h *groupHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-sess.Context().Done():
+			return nil
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if err := h.c.stash(msg); err != nil {
+				h.mu.Lock()
+				h.err = err
+				h.mu.Unlock()
+				return err
+			}
+			sess.MarkMessage(msg, "")
+		}
+	}
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,242 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/cenkalti/backoff"
+	"github.com/hashicorp/raft"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNode struct {
+	mu       sync.Mutex
+	leader   bool
+	failures int // stashes failing with raft.ErrNotLeader
+	stashed  []*events.Event
+	sinks    map[string]*sinks.GenericSink
+}
+
+func (n *fakeNode) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.leader
+}
+
+func (n *fakeNode) setLeader(leader bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.leader = leader
+}
+
+func (n *fakeNode) Stash(event *events.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failures > 0 {
+		n.failures--
+		return raft.ErrNotLeader
+	}
+	n.stashed = append(n.stashed, event)
+	return nil
+}
+
+func (n *fakeNode) GetSink(id string) *sinks.GenericSink {
+	return n.sinks[id]
+}
+
+func (n *fakeNode) eventIDs() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var ids []string
+	for _, event := range n.stashed {
+		ids = append(ids, event.EventID)
+	}
+	return ids
+}
+
+// fakeBroker is a single partition topic with the committed offset of the consumer group
+type fakeBroker struct {
+	mu        sync.Mutex
+	messages  [][]byte
+	committed int64
+	groups    int // open consumer groups
+}
+
+func (b *fakeBroker) newGroup() (consumerGroup, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.groups++
+	return &fakeGroup{broker: b, errors: make(chan error)}, nil
+}
+
+func (b *fakeBroker) openGroups() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.groups
+}
+
+func (b *fakeBroker) committedOffset() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.committed
+}
+
+type fakeGroup struct {
+	broker *fakeBroker
+	errors chan error
+}
+
+// Consume runs a session delivering the messages after the committed offset
+func (g *fakeGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sess := &fakeSession{ctx: ctx, broker: g.broker}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage)}
+
+	g.broker.mu.Lock()
+	offset := g.broker.committed
+	messages := g.broker.messages[offset:]
+	g.broker.mu.Unlock()
+
+	go func() {
+		for i, value := range messages {
+			msg := &sarama.ConsumerMessage{Topic: topics[0], Offset: offset + int64(i), Value: value}
+			select {
+			case claim.messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if err := handler.Setup(sess); err != nil {
+		return err
+	}
+	handler.ConsumeClaim(sess, claim)
+	cancel()
+
+	return handler.Cleanup(sess)
+}
+
+func (g *fakeGroup) Errors() <-chan error {
+	return g.errors
+}
+
+func (g *fakeGroup) Close() error {
+	close(g.errors)
+	g.broker.mu.Lock()
+	defer g.broker.mu.Unlock()
+	g.broker.groups--
+	return nil
+}
+
+// fakeSession commits the marked offsets right away
+type fakeSession struct {
+	ctx    context.Context
+	broker *fakeBroker
+}
+
+func (s *fakeSession) Claims() map[string][]int32               { return nil }
+func (s *fakeSession) MemberID() string                         { return "fake" }
+func (s *fakeSession) GenerationID() int32                      { return 1 }
+func (s *fakeSession) MarkOffset(string, int32, int64, string)  {}
+func (s *fakeSession) ResetOffset(string, int32, int64, string) {}
+func (s *fakeSession) Context() context.Context                 { return s.ctx }
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.committed = msg.Offset + 1
+}
+
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return "events" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func newTestConsumer(node *fakeNode, broker *fakeBroker, sink string) *Consumer {
+	registry, _ := sinks.NewRegistry()
+	c := newConsumer(node, registry, []string{"events"}, sink, broker.newGroup)
+	c.leaderCheckInterval = 10 * time.Millisecond
+	c.retryInterval = 10 * time.Millisecond
+	return c
+}
+
+func retry(t *testing.T, f func() error) {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, backoff.Retry(f, b))
+}
+
+func TestConsumerCommitsAfterStash(t *testing.T) {
+	node := &fakeNode{leader: true, failures: 1}
+	broker := &fakeBroker{messages: [][]byte{
+		[]byte(`{"eventType": "acme.prod.cpu", "eventID": "1"}`),
+		[]byte(`not json`),
+		[]byte(`{"eventType": "acme.prod.disk", "eventID": "2"}`),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		newTestConsumer(node, broker, "").Run(ctx)
+		close(done)
+	}()
+
+	// the first stash fails, so the first message is consumed again. the undecodable message is skipped
+	retry(t, func() error {
+		if broker.committedOffset() != 3 {
+			return fmt.Errorf("committed offset %d", broker.committedOffset())
+		}
+		return nil
+	})
+	require.Equal(t, []string{"1", "2"}, node.eventIDs())
+
+	cancel()
+	<-done
+	require.Equal(t, 0, broker.openGroups())
+}
+
+func TestConsumerLeaderOnly(t *testing.T) {
+	node := &fakeNode{sinks: map[string]*sinks.GenericSink{
+		"mapping": {ID: "mapping", Path: "mapping", EventType: "kafka.{{.host}}", EventID: "$.id"},
+	}}
+	broker := &fakeBroker{messages: [][]byte{[]byte(`{"host": "web-1", "id": "1"}`)}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go newTestConsumer(node, broker, "mapping").Run(ctx)
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 0, broker.openGroups())
+	require.Empty(t, node.eventIDs())
+
+	node.setLeader(true)
+	retry(t, func() error {
+		if len(node.eventIDs()) != 1 {
+			return fmt.Errorf("stashed %v", node.eventIDs())
+		}
+		return nil
+	})
+	require.Equal(t, "kafka.web-1", node.stashed[0].EventType)
+
+	// pause on leadership loss
+	node.setLeader(false)
+	retry(t, func() error {
+		if broker.openGroups() != 0 {
+			return fmt.Errorf("%d open groups", broker.openGroups())
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package snmp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MIB maps numeric oids to their names
+type MIB map[string]string
+
+// standard oids of SNMPv2-MIB and IF-MIB, which traps commonly carry
+var standardMIB = MIB{
+	"1.3.6.1.2.1.1.3":        "sysUpTime",
+	"1.3.6.1.6.3.1.1.4.1":    "snmpTrapOID",
+	"1.3.6.1.6.3.1.1.4.3":    "snmpTrapEnterprise",
+	"1.3.6.1.6.3.18.1.3":     "snmpTrapAddress",
+	"1.3.6.1.6.3.18.1.4":     "snmpTrapCommunity",
+	"1.3.6.1.6.3.1.1.5.1":    "coldStart",
+	"1.3.6.1.6.3.1.1.5.2":    "warmStart",
+	"1.3.6.1.6.3.1.1.5.3":    "linkDown",
+	"1.3.6.1.6.3.1.1.5.4":    "linkUp",
+	"1.3.6.1.6.3.1.1.5.5":    "authenticationFailure",
+	"1.3.6.1.2.1.2.2.1.1":    "ifIndex",
+	"1.3.6.1.2.1.2.2.1.2":    "ifDescr",
+	"1.3.6.1.2.1.2.2.1.7":    "ifAdminStatus",
+	"1.3.6.1.2.1.2.2.1.8":    "ifOperStatus",
+	"1.3.6.1.2.1.31.1.1.1.1": "ifName",
+}
+
+// LoadMIB reads a mib map file. Each line holds a name and its numeric oid, which is the output of
+// `snmptranslate -Tz -m ALL`, e.g. "linkDown" "1.3.6.1.6.3.1.1.5.3". Empty lines and lines starting
+// with # are skipped
+func LoadMIB(path string) (MIB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseMIB(f)
+}
+
+// ParseMIB parses a mib map, see LoadMIB
+func ParseMIB(r io.Reader) (MIB, error) {
+	mib := MIB{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("mib line %d: expected a name and an oid", n)
+		}
+
+		name := strings.Trim(fields[0], `"`)
+		oid := trimOID(strings.Trim(fields[1], `"`))
+		if name == "" || !isNumericOID(oid) {
+			return nil, fmt.Errorf("mib line %d: invalid oid %q for %q", n, fields[1], name)
+		}
+
+		mib[oid] = name
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return mib, nil
+}
+
+// Translate returns the name of the oid's longest known prefix followed by the rest of the oid,
+// e.g. ifIndex.2 for 1.3.6.1.2.1.2.2.1.1.2. Unknown oids are returned as is
+func (m MIB) Translate(oid string) string {
+	oid = trimOID(oid)
+	for prefix := oid; prefix != ""; {
+		if name, ok := m[prefix]; ok {
+			return name + oid[len(prefix):]
+		}
+
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+
+	return oid
+}
+
+// trimOID removes the leading dot of the oids decoded by gosnmp
+func trimOID(oid string) string {
+	return strings.TrimPrefix(oid, ".")
+}
+
+func isNumericOID(oid string) bool {
+	if oid == "" {
+		return false
+	}
+
+	for _, part := range strings.Split(oid, ".") {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+
+	return true
+}
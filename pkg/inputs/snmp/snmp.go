@@ -0,0 +1,277 @@
+package snmp
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/satori/go.uuid"
+	"github.com/soniah/gosnmp"
+)
+
+// oids of the varbinds describing the trap itself
+const (
+	sysUpTimeOID       = "1.3.6.1.2.1.1.3.0"
+	snmpTrapOID        = "1.3.6.1.6.3.1.1.4.1.0"
+	snmpTrapAddressOID = "1.3.6.1.6.3.18.1.3.0"
+)
+
+// Ingester ingests the events decoded from the traps
+type Ingester interface {
+	Ingest(batch []*events.Event) error
+}
+
+// Receiver listens for SNMPv2c and SNMPv3 traps and ingests them as events of type snmp.<host>.<trap name>
+type Receiver struct {
+	addr      string
+	listener  *gosnmp.TrapListener
+	community string
+	user      string
+	msgFlags  gosnmp.SnmpV3MsgFlags
+	mib       MIB
+	ingester  Ingester
+	started   bool
+}
+
+// New returns a receiver for the snmp config. v2c traps are accepted with the configured community and
+// v3 traps from the configured user. It starts receiving once started with Start
+func New(cfg *config.Config, ingester Ingester) (*Receiver, error) {
+	if cfg.SNMPCommunity == "" && cfg.SNMPUser == "" {
+		return nil, fmt.Errorf("snmp_community or snmp_user must be set with snmp_trap")
+	}
+
+	mib := MIB{}
+	for oid, name := range standardMIB {
+		mib[oid] = name
+	}
+
+	if cfg.SNMPMIB != "" {
+		loaded, err := LoadMIB(cfg.SNMPMIB)
+		if err != nil {
+			return nil, fmt.Errorf("error loading snmp_mib: %v", err)
+		}
+		for oid, name := range loaded {
+			mib[oid] = name
+		}
+	}
+
+	params := &gosnmp.GoSNMP{Version: gosnmp.Version2c}
+	if cfg.SNMPUser != "" {
+		usm, msgFlags, err := securityParameters(cfg)
+		if err != nil {
+			return nil, err
+		}
+		params.Version = gosnmp.Version3
+		params.SecurityModel = gosnmp.UserSecurityModel
+		params.MsgFlags = msgFlags
+		params.SecurityParameters = usm
+	}
+
+	r := &Receiver{
+		addr:      cfg.SNMPTrapAddr,
+		listener:  gosnmp.NewTrapListener(),
+		community: cfg.SNMPCommunity,
+		user:      cfg.SNMPUser,
+		msgFlags:  params.MsgFlags,
+		mib:       mib,
+		ingester:  ingester,
+	}
+	r.listener.Params = params
+	r.listener.OnNewTrap = r.handle
+
+	return r, nil
+}
+
+// securityParameters returns the usm user and the security level of the traps it sends
+func securityParameters(cfg *config.Config) (*gosnmp.UsmSecurityParameters, gosnmp.SnmpV3MsgFlags, error) {
+	usm := &gosnmp.UsmSecurityParameters{
+		UserName:                 cfg.SNMPUser,
+		AuthenticationProtocol:   gosnmp.NoAuth,
+		AuthenticationPassphrase: cfg.SNMPAuthPassphrase,
+		PrivacyProtocol:          gosnmp.NoPriv,
+		PrivacyPassphrase:        cfg.SNMPPrivPassphrase,
+	}
+
+	switch cfg.SNMPAuthProtocol {
+	case "":
+	case "md5":
+		usm.AuthenticationProtocol = gosnmp.MD5
+	case "sha":
+		usm.AuthenticationProtocol = gosnmp.SHA
+	default:
+		return nil, 0, fmt.Errorf("invalid snmp_auth_protocol %s. expected md5 or sha", cfg.SNMPAuthProtocol)
+	}
+
+	switch cfg.SNMPPrivProtocol {
+	case "":
+	case "des":
+		usm.PrivacyProtocol = gosnmp.DES
+	case "aes":
+		usm.PrivacyProtocol = gosnmp.AES
+	default:
+		return nil, 0, fmt.Errorf("invalid snmp_priv_protocol %s. expected des or aes", cfg.SNMPPrivProtocol)
+	}
+
+	switch {
+	case usm.PrivacyProtocol != gosnmp.NoPriv:
+		if usm.AuthenticationProtocol == gosnmp.NoAuth {
+			return nil, 0, fmt.Errorf("snmp_priv_protocol requires snmp_auth_protocol")
+		}
+		if usm.AuthenticationPassphrase == "" || usm.PrivacyPassphrase == "" {
+			return nil, 0, fmt.Errorf("snmp_auth_passphrase and snmp_priv_passphrase must be set")
+		}
+		return usm, gosnmp.AuthPriv, nil
+	case usm.AuthenticationProtocol != gosnmp.NoAuth:
+		if usm.AuthenticationPassphrase == "" {
+			return nil, 0, fmt.Errorf("snmp_auth_passphrase must be set")
+		}
+		return usm, gosnmp.AuthNoPriv, nil
+	default:
+		return usm, gosnmp.NoAuthNoPriv, nil
+	}
+}
+
+// Start listening for traps. It returns once the listener is bound
+func (r *Receiver) Start() error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- r.listener.Listen(r.addr)
+	}()
+
+	select {
+	case <-r.listener.Listening():
+		r.started = true
+		return nil
+	case err := <-errc:
+		return fmt.Errorf("snmp trap listener: %w", err)
+	}
+}
+
+// Shutdown closes the listener
+func (r *Receiver) Shutdown() {
+	if r.started {
+		r.listener.Close()
+	}
+}
+
+// handle a decoded trap
+func (r *Receiver) handle(packet *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+	event, err := r.event(packet, addr)
+	if err != nil {
+		glog.Errorf("snmp: dropping trap from %v: %v", addr, err)
+		return
+	}
+
+	if err := r.ingester.Ingest([]*events.Event{event}); err != nil {
+		glog.Errorf("snmp: error ingesting event %s: %v", event.EventType, err)
+	}
+}
+
+// authorize checks the trap's community or user against the configured ones. The authentication and
+// decryption of v3 traps is done by gosnmp with the configured user's passphrases
+func (r *Receiver) authorize(packet *gosnmp.SnmpPacket) error {
+	switch packet.Version {
+	case gosnmp.Version2c:
+		if r.community == "" || subtle.ConstantTimeCompare([]byte(packet.Community), []byte(r.community)) != 1 {
+			return fmt.Errorf("unknown community")
+		}
+	case gosnmp.Version3:
+		usm, ok := packet.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		if !ok || r.user == "" || usm.UserName != r.user {
+			return fmt.Errorf("unknown user")
+		}
+		if packet.MsgFlags&gosnmp.AuthPriv < r.msgFlags&gosnmp.AuthPriv {
+			return fmt.Errorf("security level is lower than the configured one")
+		}
+	default:
+		return fmt.Errorf("unsupported version %s", packet.Version)
+	}
+
+	if packet.PDUType != gosnmp.SNMPv2Trap {
+		return fmt.Errorf("unsupported pdu type %#x", byte(packet.PDUType))
+	}
+
+	return nil
+}
+
+// event converts a trap into an event. The varbinds are kept in the event data by their translated oids
+func (r *Receiver) event(packet *gosnmp.SnmpPacket, addr *net.UDPAddr) (*events.Event, error) {
+	if err := r.authorize(packet); err != nil {
+		return nil, err
+	}
+
+	host := addr.IP.String()
+	var trapOID string
+	var uptime interface{}
+	varbinds := make(map[string]interface{}, len(packet.Variables))
+	for _, pdu := range packet.Variables {
+		oid := trimOID(pdu.Name)
+		value := r.value(pdu)
+
+		switch oid {
+		case sysUpTimeOID:
+			uptime = value
+		case snmpTrapOID:
+			trapOID, _ = pdu.Value.(string)
+			trapOID = trimOID(trapOID)
+		case snmpTrapAddressOID:
+			// the trap was relayed, the agent sending it is in the varbind
+			if agent, ok := value.(string); ok && agent != "" {
+				host = agent
+			}
+		}
+
+		varbinds[r.mib.Translate(oid)] = value
+	}
+
+	if trapOID == "" {
+		return nil, fmt.Errorf("trap has no snmpTrapOID varbind")
+	}
+
+	trap := r.mib.Translate(trapOID)
+	data := map[string]interface{}{
+		"agent":    host,
+		"version":  packet.Version.String(),
+		"trap_oid": trapOID,
+		"trap":     trap,
+		"uptime":   uptime,
+		"varbinds": varbinds,
+	}
+	if usm, ok := packet.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok && packet.Version == gosnmp.Version3 {
+		data["user"] = usm.UserName
+	}
+
+	return &events.Event{
+		Source:             "snmp",
+		Data:               data,
+		ContentType:        "application/json",
+		EventTypeVersion:   "1.0",
+		CloudEventsVersion: "0.1",
+		SchemaURL:          "",
+		EventID:            uuid.NewV4().String(),
+		EventTime:          time.Now(),
+		EventType:          "snmp." + sinks.Segment(host) + "." + sinks.Segment(trap),
+	}, nil
+}
+
+// value converts a varbind value to a json friendly value
+func (r *Receiver) value(pdu gosnmp.SnmpPDU) interface{} {
+	switch pdu.Type {
+	case gosnmp.OctetString:
+		if b, ok := pdu.Value.([]byte); ok {
+			return string(b)
+		}
+	case gosnmp.ObjectIdentifier:
+		if oid, ok := pdu.Value.(string); ok {
+			return r.mib.Translate(oid)
+		}
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return nil
+	}
+	return pdu.Value
+}
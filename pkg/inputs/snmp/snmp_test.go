@@ -0,0 +1,249 @@
+package snmp
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/soniah/gosnmp"
+	"github.com/stretchr/testify/require"
+)
+
+type testIngester struct {
+	mu     sync.Mutex
+	events []*events.Event
+}
+
+func (i *testIngester) Ingest(batch []*events.Event) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.events = append(i.events, batch...)
+	return nil
+}
+
+func (i *testIngester) received() []*events.Event {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]*events.Event(nil), i.events...)
+}
+
+var linkDown = gosnmp.SnmpTrap{
+	Variables: []gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.3"},
+		{Name: ".1.3.6.1.2.1.2.2.1.1.2", Type: gosnmp.Integer, Value: 2},
+		{Name: ".1.3.6.1.2.1.2.2.1.2.2", Type: gosnmp.OctetString, Value: "eth1"},
+	},
+}
+
+var fanFailure = gosnmp.SnmpTrap{
+	Variables: []gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.674.10892.5.3.2.1.0.1304"},
+		{Name: ".1.3.6.1.6.3.18.1.3.0", Type: gosnmp.IPAddress, Value: "10.1.2.3"},
+		{Name: ".1.3.6.1.4.1.674.10892.5.3.1.1.0", Type: gosnmp.OctetString, Value: "Fan 2 failed"},
+	},
+}
+
+// localizedKey derives a sha1 usm key for the engine id as in rfc 3414 appendix a.2. gosnmp only derives
+// the keys of a sender on engine id discovery, which traps skip
+func localizedKey(passphrase, engineID string) []byte {
+	h := sha1.New()
+	for i := 0; i < 1048576; i++ {
+		h.Write([]byte{passphrase[i%len(passphrase)]})
+	}
+	ku := h.Sum(nil)
+
+	h.Reset()
+	h.Write(ku)
+	h.Write([]byte(engineID))
+	h.Write(ku)
+	return h.Sum(nil)
+}
+
+func sendTrap(t *testing.T, sender *gosnmp.GoSNMP, trap gosnmp.SnmpTrap) {
+	sender.Target = "127.0.0.1"
+	sender.Port = 21162
+	sender.Timeout = time.Second
+	require.NoError(t, sender.Connect())
+	defer sender.Conn.Close()
+
+	_, err := sender.SendTrap(trap)
+	require.NoError(t, err)
+}
+
+func TestReceiver(t *testing.T) {
+	mib, err := ParseMIB(strings.NewReader(`
+# dell idrac
+"fanFailure"	"1.3.6.1.4.1.674.10892.5.3.2.1.0.1304"
+"alertMessage"	"1.3.6.1.4.1.674.10892.5.3.1.1"
+`))
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		SNMPTrapAddr:       "127.0.0.1:21162",
+		SNMPCommunity:      "cortex",
+		SNMPUser:           "trapper",
+		SNMPAuthProtocol:   "sha",
+		SNMPAuthPassphrase: "authpassphrase",
+		SNMPPrivProtocol:   "aes",
+		SNMPPrivPassphrase: "privpassphrase",
+	}
+
+	ingester := &testIngester{}
+	receiver, err := New(cfg, ingester)
+	require.NoError(t, err)
+	for oid, name := range mib {
+		receiver.mib[oid] = name
+	}
+	require.NoError(t, receiver.Start())
+	defer receiver.Shutdown()
+
+	// v2c
+	sendTrap(t, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "cortex"}, linkDown)
+	sendTrap(t, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}, linkDown)
+
+	// v3 with authentication and privacy
+	sendTrap(t, &gosnmp.GoSNMP{
+		Version:       gosnmp.Version3,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 "trapper",
+			AuthoritativeEngineID:    "8000000001020304",
+			AuthoritativeEngineBoots: 1,
+			AuthoritativeEngineTime:  uint32(time.Now().Unix()),
+			AuthenticationProtocol:   gosnmp.SHA,
+			AuthenticationPassphrase: "authpassphrase",
+			PrivacyProtocol:          gosnmp.AES,
+			PrivacyPassphrase:        "privpassphrase",
+			SecretKey:                localizedKey("authpassphrase", "8000000001020304"),
+			PrivacyKey:               localizedKey("privpassphrase", "8000000001020304"),
+		},
+	}, fanFailure)
+
+	// v3 with the wrong passphrase
+	sendTrap(t, &gosnmp.GoSNMP{
+		Version:       gosnmp.Version3,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthNoPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 "trapper",
+			AuthoritativeEngineID:    "8000000001020304",
+			AuthenticationProtocol:   gosnmp.SHA,
+			AuthenticationPassphrase: "wrongpassphrase",
+			SecretKey:                localizedKey("wrongpassphrase", "8000000001020304"),
+		},
+	}, linkDown)
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, backoff.Retry(func() error {
+		if len(ingester.received()) < 2 {
+			return fmt.Errorf("received %d events", len(ingester.received()))
+		}
+		return nil
+	}, b))
+
+	// the rejected traps would have been received by now
+	time.Sleep(200 * time.Millisecond)
+	received := ingester.received()
+	require.Len(t, received, 2)
+
+	var eventTypes []string
+	for _, event := range received {
+		require.Equal(t, "snmp", event.Source)
+		eventTypes = append(eventTypes, event.EventType)
+	}
+	require.ElementsMatch(t, []string{"snmp.127_0_0_1.linkDown", "snmp.10_1_2_3.fanFailure"}, eventTypes)
+
+	for _, event := range received {
+		data := event.Data.(map[string]interface{})
+		varbinds := data["varbinds"].(map[string]interface{})
+		switch data["trap"] {
+		case "linkDown":
+			require.Equal(t, "2c", data["version"])
+			require.Equal(t, "1.3.6.1.6.3.1.1.5.3", data["trap_oid"])
+			require.Equal(t, "linkDown", varbinds["snmpTrapOID.0"])
+			require.Equal(t, 2, varbinds["ifIndex.2"])
+			require.Equal(t, "eth1", varbinds["ifDescr.2"])
+			require.NotNil(t, data["uptime"])
+		case "fanFailure":
+			require.Equal(t, "3", data["version"])
+			require.Equal(t, "trapper", data["user"])
+			require.Equal(t, "Fan 2 failed", varbinds["alertMessage.0"])
+		}
+	}
+}
+
+func TestReceiverEvent(t *testing.T) {
+	receiver, err := New(&config.Config{SNMPCommunity: "cortex"}, &testIngester{})
+	require.NoError(t, err)
+
+	addr := &net.UDPAddr{IP: net.ParseIP("192.168.1.10")}
+	packet := &gosnmp.SnmpPacket{
+		Version:   gosnmp.Version2c,
+		Community: "cortex",
+		PDUType:   gosnmp.SNMPv2Trap,
+		Variables: []gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.9999.1.2"},
+		},
+	}
+
+	// unknown oids are kept numeric
+	event, err := receiver.event(packet, addr)
+	require.NoError(t, err)
+	require.Equal(t, "snmp.192_168_1_10.1_3_6_1_4_1_9999_1_2", event.EventType)
+
+	packet.PDUType = gosnmp.GetRequest
+	_, err = receiver.event(packet, addr)
+	require.Error(t, err)
+
+	packet.PDUType = gosnmp.SNMPv2Trap
+	packet.Variables = nil
+	_, err = receiver.event(packet, addr)
+	require.Error(t, err)
+
+	// v3 traps are rejected without a configured user
+	packet.Version = gosnmp.Version3
+	_, err = receiver.event(packet, addr)
+	require.Error(t, err)
+}
+
+func TestReceiverConfig(t *testing.T) {
+	_, err := New(&config.Config{}, &testIngester{})
+	require.Error(t, err)
+
+	_, err = New(&config.Config{SNMPUser: "u", SNMPAuthProtocol: "sha256", SNMPAuthPassphrase: "p"}, &testIngester{})
+	require.Error(t, err)
+
+	_, err = New(&config.Config{SNMPUser: "u", SNMPPrivProtocol: "aes", SNMPPrivPassphrase: "p"}, &testIngester{})
+	require.Error(t, err)
+
+	_, err = New(&config.Config{SNMPUser: "u", SNMPAuthProtocol: "md5"}, &testIngester{})
+	require.Error(t, err)
+
+	_, err = New(&config.Config{SNMPCommunity: "c", SNMPMIB: "/does/not/exist"}, &testIngester{})
+	require.Error(t, err)
+}
+
+func TestMIB(t *testing.T) {
+	mib, err := ParseMIB(strings.NewReader("\"enterprises\"\t\t\"1.3.6.1.4.1\"\nacme .1.3.6.1.4.1.9999\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "acme.1.2", mib.Translate(".1.3.6.1.4.1.9999.1.2"))
+	require.Equal(t, "enterprises.8072", mib.Translate("1.3.6.1.4.1.8072"))
+	require.Equal(t, "1.3.6.1.2.1", mib.Translate("1.3.6.1.2.1"))
+	require.Equal(t, "linkUp", standardMIB.Translate("1.3.6.1.6.3.1.1.5.4"))
+
+	_, err = ParseMIB(strings.NewReader("acme"))
+	require.Error(t, err)
+
+	_, err = ParseMIB(strings.NewReader("acme 1.3.x"))
+	require.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package syslog
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/satori/go.uuid"
+	gsyslog "gopkg.in/mcuadros/go-syslog.v2"
+	"gopkg.in/mcuadros/go-syslog.v2/format"
+)
+
+// DefaultEventType is the event type template used when none is configured
+const DefaultEventType = "syslog.{{.Facility}}.{{.Severity}}.{{.Hostname}}.{{.AppName}}"
+
+const shutdownTimeout = 5 * time.Second
+
+var facilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp", "cron", "authpriv", "ftp",
+	"ntp", "security", "console", "solaris-cron", "local0", "local1", "local2", "local3", "local4", "local5",
+	"local6", "local7",
+}
+
+var severities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// Stasher stashes the received events
+type Stasher interface {
+	Stash(event *events.Event) error
+}
+
+// Message is the data the event type template is executed against. The fields are made safe to use as
+// event type segments: dots and spaces are replaced by underscores and empty fields are "unknown"
+type Message struct {
+	Facility string
+	Severity string
+	Hostname string
+	AppName  string
+	ProcID   string
+	MsgID    string
+}
+
+// Receiver listens for RFC 5424 and RFC 3164 syslog messages over udp, tcp and tls and stashes them as events
+type Receiver struct {
+	server    *gsyslog.Server
+	eventType *template.Template
+	stasher   Stasher
+}
+
+// Enabled returns true if a syslog listener is configured
+func Enabled(cfg *config.Config) bool {
+	return cfg.SyslogUDPAddr != "" || cfg.SyslogTCPAddr != "" || cfg.SyslogTLSAddr != ""
+}
+
+// New returns a receiver listening on the configured addresses. It starts receiving once booted with Start
+func New(cfg *config.Config, stasher Stasher) (*Receiver, error) {
+	text := cfg.SyslogEventType
+	if text == "" {
+		text = DefaultEventType
+	}
+
+	eventType, err := template.New("syslog").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog_event_type: %v", err)
+	}
+
+	r := &Receiver{
+		server:    gsyslog.NewServer(),
+		eventType: eventType,
+		stasher:   stasher,
+	}
+
+	switch cfg.SyslogFormat {
+	case "", "auto":
+		r.server.SetFormat(gsyslog.Automatic)
+	case "rfc5424":
+		r.server.SetFormat(gsyslog.RFC5424)
+	case "rfc3164":
+		r.server.SetFormat(gsyslog.RFC3164)
+	default:
+		return nil, fmt.Errorf("invalid syslog_format %s. expected auto, rfc5424 or rfc3164", cfg.SyslogFormat)
+	}
+	r.server.SetHandler(r)
+	r.server.SetTlsPeerNameFunc(tlsPeerName)
+
+	if cfg.SyslogUDPAddr != "" {
+		if err := r.server.ListenUDP(cfg.SyslogUDPAddr); err != nil {
+			return nil, fmt.Errorf("syslog udp listener: %w", err)
+		}
+	}
+
+	if cfg.SyslogTCPAddr != "" {
+		if err := r.server.ListenTCP(cfg.SyslogTCPAddr); err != nil {
+			r.server.Kill()
+			return nil, fmt.Errorf("syslog tcp listener: %w", err)
+		}
+	}
+
+	if cfg.SyslogTLSAddr != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.SyslogTLSCert, cfg.SyslogTLSKey)
+		if err != nil {
+			r.server.Kill()
+			return nil, fmt.Errorf("syslog tls certificate: %v", err)
+		}
+
+		if err := r.server.ListenTCPTLS(cfg.SyslogTLSAddr, &tls.Config{Certificates: []tls.Certificate{cert}}); err != nil {
+			r.server.Kill()
+			return nil, fmt.Errorf("syslog tls listener: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// Start receiving messages
+func (r *Receiver) Start() error {
+	return r.server.Boot()
+}
+
+// Shutdown closes the listeners and waits for the received messages to be stashed. Open tcp connections
+// are only closed by their clients, so it waits for them up to shutdownTimeout
+func (r *Receiver) Shutdown() error {
+	if err := r.server.Kill(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.server.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(shutdownTimeout):
+		return fmt.Errorf("timed out waiting for the syslog connections to close")
+	}
+}
+
+// Handle a parsed message
+func (r *Receiver) Handle(parts format.LogParts, length int64, err error) {
+	if err != nil {
+		glog.Errorf("syslog: dropping message from %v: %v", parts["client"], err)
+		return
+	}
+
+	event, err := r.event(parts)
+	if err != nil {
+		glog.Errorf("syslog: dropping message from %v: %v", parts["client"], err)
+		return
+	}
+
+	if err := r.stasher.Stash(event); err != nil {
+		glog.Errorf("syslog: error stashing event %s: %v", event.EventType, err)
+	}
+}
+
+// event converts the parts of a message into an event. The parts are kept as the event data
+func (r *Receiver) event(parts format.LogParts) (*events.Event, error) {
+	msg := Message{
+		Facility: name(facilities, parts["facility"]),
+		Severity: name(severities, parts["severity"]),
+		Hostname: sinks.Segment(parts["hostname"]),
+		AppName:  sinks.Segment(parts["app_name"]),
+		ProcID:   sinks.Segment(parts["proc_id"]),
+		MsgID:    sinks.Segment(parts["msg_id"]),
+	}
+
+	// rfc 3164 has a tag in place of the app name
+	if _, ok := parts["tag"]; ok {
+		msg.AppName = sinks.Segment(parts["tag"])
+	}
+
+	var buf bytes.Buffer
+	if err := r.eventType.Execute(&buf, msg); err != nil {
+		return nil, err
+	}
+
+	eventTime, ok := parts["timestamp"].(time.Time)
+	if !ok || eventTime.IsZero() {
+		eventTime = time.Now()
+	}
+
+	data := make(map[string]interface{}, len(parts))
+	for k, v := range parts {
+		data[k] = v
+	}
+
+	return &events.Event{
+		Source:             "syslog",
+		Data:               data,
+		ContentType:        "application/json",
+		EventTypeVersion:   "1.0",
+		CloudEventsVersion: "0.1",
+		SchemaURL:          "",
+		EventID:            uuid.NewV4().String(),
+		EventTime:          eventTime,
+		EventType:          buf.String(),
+	}, nil
+}
+
+// tlsPeerName accepts tls clients without a certificate, unlike the go-syslog default
+func tlsPeerName(conn *tls.Conn) (string, bool) {
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return "", true
+	}
+	return state.PeerCertificates[0].Subject.CommonName, true
+}
+
+func name(names []string, v interface{}) string {
+	i, ok := v.(int)
+	if !ok || i < 0 || i >= len(names) {
+		return "unknown"
+	}
+	return names[i]
+}
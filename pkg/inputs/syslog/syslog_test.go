@@ -0,0 +1,163 @@
+package syslog
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+type testStasher struct {
+	mu     sync.Mutex
+	events []*events.Event
+}
+
+func (s *testStasher) Stash(event *events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *testStasher) eventTypes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var types []string
+	for _, event := range s.events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+// writeTestCert writes a self signed certificate and its key to the dir
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestReceiver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslog_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeTestCert(t, dir)
+
+	cfg := &config.Config{
+		SyslogUDPAddr: "127.0.0.1:25514",
+		SyslogTCPAddr: "127.0.0.1:25515",
+		SyslogTLSAddr: "127.0.0.1:25516",
+		SyslogTLSCert: certFile,
+		SyslogTLSKey:  keyFile,
+	}
+	require.True(t, Enabled(cfg))
+
+	stasher := &testStasher{}
+	receiver, err := New(cfg, stasher)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start())
+	defer receiver.Shutdown()
+
+	udp, err := net.Dial("udp", cfg.SyslogUDPAddr)
+	require.NoError(t, err)
+	defer udp.Close()
+	_, err = fmt.Fprint(udp, "<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry")
+	require.NoError(t, err)
+
+	tcp, err := net.Dial("tcp", cfg.SyslogTCPAddr)
+	require.NoError(t, err)
+	defer tcp.Close()
+	_, err = fmt.Fprint(tcp, "<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8\n")
+	require.NoError(t, err)
+
+	tlsConn, err := tls.Dial("tcp", cfg.SyslogTLSAddr, &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	defer tlsConn.Close()
+	_, err = fmt.Fprint(tlsConn, "<11>1 2003-10-11T22:14:15.003Z db-1 postgres 42 - - disk full\n")
+	require.NoError(t, err)
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, backoff.Retry(func() error {
+		if len(stasher.eventTypes()) != 3 {
+			return fmt.Errorf("received %v", stasher.eventTypes())
+		}
+		return nil
+	}, b))
+
+	require.ElementsMatch(t, []string{
+		"syslog.local4.notice.mymachine_example_com.evntslog",
+		"syslog.auth.crit.mymachine.su",
+		"syslog.user.err.db-1.postgres",
+	}, stasher.eventTypes())
+
+	for _, event := range stasher.events {
+		require.Equal(t, "syslog", event.Source)
+		// rfc 3164 timestamps have no year
+		if event.Data.(map[string]interface{})["tag"] == nil {
+			require.Equal(t, 2003, event.EventTime.Year())
+		}
+	}
+}
+
+func TestReceiverAddrInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:25517")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// the error is kept so that a reload can retry while the old process holds the port
+	_, err = New(&config.Config{SyslogTCPAddr: "127.0.0.1:25517"}, &testStasher{})
+	require.True(t, errors.Is(err, syscall.EADDRINUSE), "%v", err)
+}
+
+func TestReceiverConfig(t *testing.T) {
+	_, err := New(&config.Config{SyslogFormat: "cef"}, &testStasher{})
+	require.Error(t, err)
+
+	_, err = New(&config.Config{SyslogEventType: "{{.Hostname"}, &testStasher{})
+	require.Error(t, err)
+
+	receiver, err := New(&config.Config{SyslogEventType: "{{.AppName}}.{{.Severity}}"}, &testStasher{})
+	require.NoError(t, err)
+
+	event, err := receiver.event(map[string]interface{}{"severity": 6, "facility": 3, "app_name": "nginx"})
+	require.NoError(t, err)
+	require.Equal(t, "nginx.info", event.EventType)
+	require.False(t, event.EventTime.IsZero())
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package tail
+
+import (
+	"os"
+	"syscall"
+)
+
+// inode identifies the file behind a path, which changes when the file is rotated
+func inode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Ino)
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+package tail
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type offset struct {
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// offsets are the read offsets of the tailed files by path, persisted as json
+type offsets struct {
+	path    string
+	entries map[string]offset
+	dirty   bool
+}
+
+func loadOffsets(path string) (*offsets, error) {
+	o := &offsets{path: path, entries: make(map[string]offset)}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &o.entries); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (o *offsets) get(path string) (offset, bool) {
+	entry, ok := o.entries[path]
+	return entry, ok
+}
+
+// byInode returns the path and offset saved for the inode
+func (o *offsets) byInode(inode uint64) (string, offset, bool) {
+	for path, entry := range o.entries {
+		if entry.Inode == inode {
+			return path, entry, true
+		}
+	}
+	return "", offset{}, false
+}
+
+func (o *offsets) set(path string, inode uint64, off int64) {
+	o.entries[path] = offset{Inode: inode, Offset: off}
+	o.dirty = true
+}
+
+// remove the offset of the file if it was not replaced by another file at the same path
+func (o *offsets) remove(path string, inode uint64) {
+	if entry, ok := o.entries[path]; ok && entry.Inode == inode {
+		delete(o.entries, path)
+		o.dirty = true
+	}
+}
+
+// save writes the offsets to a temporary file renamed over the previous one, so a crash leaves either
+func (o *offsets) save() error {
+	if !o.dirty {
+		return nil
+	}
+
+	b, err := json.Marshal(o.entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(o.path), filepath.Base(o.path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), o.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	o.dirty = false
+	return nil
+}
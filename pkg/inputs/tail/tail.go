@@ -0,0 +1,504 @@
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/inputs"
+)
+
+// DefaultEventType is the event type template used when none is configured
+const DefaultEventType = "tail.{{.file}}"
+
+const (
+	pollInterval        = time.Second
+	leaderCheckInterval = time.Second
+)
+
+// named groups of the line pattern which set the event fields instead of only the event data
+const (
+	groupEventTime = "event_time"
+	groupEventID   = "event_id"
+	groupSource    = "source"
+)
+
+// Stasher stashes the events read from the files
+type Stasher interface {
+	Stash(event *events.Event) error
+}
+
+// Node is the part of store.Node used to tail shared files on the leader only
+type Node interface {
+	IsLeader() bool
+}
+
+// Tailer follows the files matching the globs and stashes an event per line. Files are polled, which also
+// works on shared mounts. Files are followed by inode, so a rotated file still matching the globs continues
+// from its offset under its new path, and one no longer matching is read to its end. Truncated files are read
+// again from their start.
+//
+// The offset of each file is persisted once its lines are stashed, so a restart continues after the last
+// stashed line. Files found on the first scan without a persisted offset are followed from their end, files
+// created later or rotated while cortex was down from their start.
+//
+// Files are node local unless shared, shared files are only tailed while the node is the raft leader.
+type Tailer struct {
+	globs       []string
+	pattern     *regexp.Regexp
+	eventType   *template.Template
+	timeFormat  string
+	offsetsPath string
+	offsets     *offsets
+	stasher     Stasher
+	node        Node
+	shared      bool
+
+	files     map[fileKey]*tailedFile
+	firstScan bool
+	moved     map[string]uint64 // offsets moved to another path by this poll, removed once all paths are followed
+
+	leaderCheckInterval time.Duration
+}
+
+// fileKey identifies a followed file by its inode. Without inodes, e.g. on windows, by its path
+type fileKey struct {
+	inode uint64
+	path  string
+}
+
+func keyOf(path string, ino uint64) fileKey {
+	if ino == 0 {
+		return fileKey{path: path}
+	}
+	return fileKey{inode: ino}
+}
+
+type tailedFile struct {
+	path   string
+	file   *os.File
+	inode  uint64
+	offset int64
+}
+
+// Enabled returns true if files to tail are configured
+func Enabled(cfg *config.Config) bool {
+	return cfg.TailPaths != ""
+}
+
+// New returns a tailer for the config. It starts tailing with Run
+func New(cfg *config.Config, stasher Stasher, node Node) (*Tailer, error) {
+	var globs []string
+	for _, glob := range strings.Split(cfg.TailPaths, ",") {
+		if glob = strings.TrimSpace(glob); glob == "" {
+			continue
+		}
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid tail_paths glob %s: %v", glob, err)
+		}
+		globs = append(globs, glob)
+	}
+
+	if len(globs) == 0 {
+		return nil, fmt.Errorf("tail_paths is not set")
+	}
+
+	t := &Tailer{
+		globs:               globs,
+		timeFormat:          cfg.TailTimeFormat,
+		stasher:             stasher,
+		node:                node,
+		shared:              cfg.TailShared,
+		files:               make(map[fileKey]*tailedFile),
+		moved:               make(map[string]uint64),
+		firstScan:           true,
+		leaderCheckInterval: leaderCheckInterval,
+	}
+
+	if t.timeFormat == "" {
+		t.timeFormat = time.RFC3339
+	}
+
+	var err error
+	if cfg.TailPattern != "" {
+		if t.pattern, err = regexp.Compile(cfg.TailPattern); err != nil {
+			return nil, fmt.Errorf("invalid tail_pattern: %v", err)
+		}
+	}
+
+	text := cfg.TailEventType
+	if text == "" {
+		text = DefaultEventType
+	}
+
+	if t.eventType, err = template.New("tail").Option("missingkey=zero").Parse(text); err != nil {
+		return nil, fmt.Errorf("invalid tail_event_type: %v", err)
+	}
+
+	t.offsetsPath = cfg.TailOffsets
+	if t.offsetsPath == "" {
+		t.offsetsPath = filepath.Join(cfg.Dir, "tail_offsets.json")
+	}
+
+	if t.offsets, err = loadOffsets(t.offsetsPath); err != nil {
+		return nil, fmt.Errorf("error loading the tail offsets: %v", err)
+	}
+
+	return t, nil
+}
+
+// Run polls the files until the context is done. Shared files are polled while the node is the leader
+func (t *Tailer) Run(ctx context.Context) {
+	if !t.shared {
+		t.run(ctx)
+		return
+	}
+
+	ticker := time.NewTicker(t.leaderCheckInterval)
+	defer ticker.Stop()
+
+	var running *tailing
+	for {
+		leader := t.node.IsLeader()
+		switch {
+		case leader && running == nil:
+			glog.Infof("tail: leader, tailing %v", t.globs)
+			running = t.start(ctx)
+		case !leader && running != nil:
+			glog.Info("tail: leadership lost, stopping the tailer")
+			running.stop()
+			running = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if running != nil {
+				running.stop()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tailing is a running poll loop
+type tailing struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (r *tailing) stop() {
+	r.cancel()
+	<-r.done
+}
+
+// start polls until stopped. The offsets saved by the previous leader are loaded first, the offsets file is
+// expected on the shared mount as well
+func (t *Tailer) start(ctx context.Context) *tailing {
+	ctx, cancel := context.WithCancel(ctx)
+	running := &tailing{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(running.done)
+
+		loaded, err := loadOffsets(t.offsetsPath)
+		if err != nil {
+			glog.Errorf("tail: error loading the offsets, keeping the last ones: %v", err)
+		} else {
+			t.offsets = loaded
+		}
+		t.firstScan = true
+
+		t.run(ctx)
+	}()
+
+	return running
+}
+
+// run polls the files until the context is done
+func (t *Tailer) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		t.poll()
+
+		select {
+		case <-ctx.Done():
+			t.close()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tailer) close() {
+	for key, tf := range t.files {
+		tf.file.Close()
+		delete(t.files, key)
+	}
+}
+
+// poll reads the lines appended since the last poll and persists the offsets
+func (t *Tailer) poll() {
+	paths := make(map[string]bool)
+	for _, glob := range t.globs {
+		matches, _ := filepath.Glob(glob) // the pattern is validated by New
+		for _, path := range matches {
+			paths[path] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	// the first path of each file, a file linked at several paths is followed once
+	found := make(map[fileKey]string)
+	infos := make(map[string]os.FileInfo)
+	for _, path := range sorted {
+		info, err := os.Stat(path)
+		if err != nil {
+			glog.Errorf("tail: %s: %v", path, err)
+			continue
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		key := keyOf(path, inode(info))
+		if _, ok := found[key]; !ok {
+			found[key] = path
+			infos[path] = info
+		}
+	}
+
+	// files moved out of the globs or removed, e.g. by a rotation
+	for key, tf := range t.files {
+		if _, ok := found[key]; !ok {
+			t.drain(tf)
+		}
+	}
+
+	for _, path := range sorted {
+		info, ok := infos[path]
+		if !ok {
+			continue
+		}
+		if err := t.follow(path, info); err != nil {
+			glog.Errorf("tail: %s: %v", path, err)
+		}
+	}
+
+	for path, ino := range t.moved {
+		t.offsets.remove(path, ino)
+		delete(t.moved, path)
+	}
+	t.firstScan = false
+
+	if err := t.offsets.save(); err != nil {
+		glog.Errorf("tail: error saving the offsets: %v", err)
+	}
+}
+
+// follow reads the new lines of the file at the path, handling its rotation and truncation
+func (t *Tailer) follow(path string, info os.FileInfo) error {
+	ino := inode(info)
+	key := keyOf(path, ino)
+
+	tf := t.files[key]
+	if tf != nil && tf.path != path {
+		// rotated to a path still matching the globs, the file continues from its offset
+		glog.Infof("tail: %s was renamed to %s", tf.path, path)
+		t.moved[tf.path] = ino
+		tf.path = path
+		t.offsets.set(path, ino, tf.offset)
+	}
+
+	if tf == nil {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		tf = &tailedFile{path: path, file: file, inode: ino}
+		saved, ok := t.offsets.get(path)
+		switch {
+		case ok && saved.Inode == ino:
+			tf.offset = saved.Offset
+		case t.renamedOffset(path, ino, info.Size(), &tf.offset):
+			// rotated to this path while cortex was down
+		case ok:
+			// a file with another inode replaced the saved one while cortex was down, it is read from its start
+		case t.firstScan:
+			tf.offset = info.Size()
+		}
+		t.files[key] = tf
+	}
+
+	if info.Size() < tf.offset {
+		glog.Infof("tail: %s was truncated", path)
+		tf.offset = 0
+		t.offsets.set(path, ino, 0)
+	}
+
+	return t.read(tf, info.Size())
+}
+
+// renamedOffset sets the offset saved for the inode under another path, moving it to the path. The entry of the
+// other path is kept until the end of the poll, so a new file at that path is still read from its start
+func (t *Tailer) renamedOffset(path string, ino uint64, size int64, off *int64) bool {
+	if ino == 0 {
+		return false
+	}
+
+	from, saved, ok := t.offsets.byInode(ino)
+	if !ok || from == path || saved.Offset > size {
+		return false
+	}
+
+	t.moved[from] = ino
+	t.offsets.set(path, ino, saved.Offset)
+	*off = saved.Offset
+	return true
+}
+
+// drain reads the file to its end and stops following it
+func (t *Tailer) drain(tf *tailedFile) {
+	if info, err := tf.file.Stat(); err == nil {
+		if err := t.read(tf, info.Size()); err != nil {
+			glog.Errorf("tail: %s: %v", tf.path, err)
+		}
+	}
+
+	tf.file.Close()
+	delete(t.files, keyOf(tf.path, tf.inode))
+	t.offsets.remove(tf.path, tf.inode)
+}
+
+// read stashes the complete lines between the file's offset and size. The offset only moves past stashed
+// lines, a partial last line is read once complete
+func (t *Tailer) read(tf *tailedFile, size int64) error {
+	if size <= tf.offset {
+		return nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(tf.file, tf.offset, size-tf.offset))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := t.stash(tf, bytes.TrimRight(line, "\r\n")); err != nil {
+			return err
+		}
+
+		tf.offset += int64(len(line))
+		t.offsets.set(tf.path, tf.inode, tf.offset)
+	}
+}
+
+// stash returns an error only if the line must be read again, i.e. the cluster has no leader or it couldn't be
+// reached. lines rejected by the leader are skipped
+func (t *Tailer) stash(tf *tailedFile, line []byte) error {
+	if len(line) == 0 {
+		return nil
+	}
+
+	event, err := t.event(tf, string(line))
+	if err != nil {
+		glog.V(2).Infof("tail: skipping line at %s:%d: %v", tf.path, tf.offset, err)
+		return nil
+	}
+
+	if err := t.stasher.Stash(event); err != nil {
+		if inputs.IsRetryable(err) {
+			return err
+		}
+		glog.Errorf("tail: skipping line at %s:%d: %v", tf.path, tf.offset, err)
+	}
+
+	return nil
+}
+
+// event converts a line into an event. The named groups of the pattern and the line are the event data
+func (t *Tailer) event(tf *tailedFile, line string) (*events.Event, error) {
+	data := map[string]interface{}{
+		"line": line,
+		"path": tf.path,
+	}
+	fields := map[string]string{
+		"file": sinks.Segment(filepath.Base(tf.path)),
+	}
+
+	if t.pattern != nil {
+		match := t.pattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("line does not match tail_pattern")
+		}
+
+		for i, name := range t.pattern.SubexpNames() {
+			if name == "" {
+				continue
+			}
+			data[name] = match[i]
+			fields[name] = sinks.Segment(match[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := t.eventType.Execute(&buf, fields); err != nil {
+		return nil, err
+	}
+
+	event := &events.Event{
+		Source:             "tail",
+		Data:               data,
+		ContentType:        "application/json",
+		EventTypeVersion:   "1.0",
+		CloudEventsVersion: "0.1",
+		SchemaURL:          "",
+		// the same line gets the same id if it is read again, and another id once the file is truncated
+		EventID:   fmt.Sprintf("%s:%d:%d:%x", tf.path, tf.inode, tf.offset, crc32.ChecksumIEEE([]byte(line))),
+		EventTime: time.Now(),
+		EventType: buf.String(),
+	}
+
+	if v, _ := data[groupEventTime].(string); v != "" {
+		eventTime, err := time.Parse(t.timeFormat, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event_time: %v", err)
+		}
+		event.EventTime = eventTime
+	}
+
+	if v, _ := data[groupEventID].(string); v != "" {
+		event.EventID = v
+	}
+
+	if v, _ := data[groupSource].(string); v != "" {
+		event.Source = v
+	}
+
+	return event, nil
+}
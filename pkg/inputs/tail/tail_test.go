@@ -0,0 +1,327 @@
+package tail
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/inputs"
+	"github.com/stretchr/testify/require"
+)
+
+type testStasher struct {
+	events []*events.Event
+	err    error
+	reject string // line rejected by the leader
+}
+
+func (s *testStasher) Stash(event *events.Event) error {
+	if s.err != nil {
+		return s.err
+	}
+	if event.Data.(map[string]interface{})["line"] == s.reject {
+		return &inputs.ForwardError{Leader: "leader:4445", Path: "/event", Status: http.StatusBadRequest, Err: fmt.Errorf("invalid event")}
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *testStasher) lines() []string {
+	var lines []string
+	for _, event := range s.events {
+		lines = append(lines, event.Data.(map[string]interface{})["line"].(string))
+	}
+	return lines
+}
+
+type testNode struct {
+	mu     sync.Mutex
+	leader bool
+}
+
+func (n *testNode) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.leader
+}
+
+func (n *testNode) setLeader(leader bool) {
+	n.mu.Lock()
+	n.leader = leader
+	n.mu.Unlock()
+}
+
+func appendLines(t *testing.T, path string, lines ...string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+	for _, line := range lines {
+		_, err := f.WriteString(line + "\n")
+		require.NoError(t, err)
+	}
+}
+
+func testTailer(t *testing.T, dir string, stasher Stasher, pattern string) *Tailer {
+	tailer, err := New(&config.Config{
+		Dir:           dir,
+		TailPaths:     filepath.Join(dir, "*.log"),
+		TailPattern:   pattern,
+		TailEventType: "app.{{.file}}.{{.level}}",
+	}, stasher, &testNode{leader: true})
+	require.NoError(t, err)
+	return tailer
+}
+
+func TestTailerEvent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	appendLines(t, path, "2018-11-01T10:00:00Z info existing")
+
+	stasher := &testStasher{}
+	tailer := testTailer(t, dir, stasher, `^(?P<event_time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+
+	// the existing lines are skipped on the first scan
+	tailer.poll()
+	require.Empty(t, stasher.events)
+
+	appendLines(t, path, "2018-11-01T10:00:01Z error disk full", "not matching", "invalid error bad time")
+	tailer.poll()
+	require.Len(t, stasher.events, 1)
+
+	event := stasher.events[0]
+	require.Equal(t, "app.app_log.error", event.EventType)
+	require.Equal(t, "tail", event.Source)
+	require.True(t, time.Date(2018, 11, 1, 10, 0, 1, 0, time.UTC).Equal(event.EventTime))
+	require.Equal(t, map[string]interface{}{
+		"event_time": "2018-11-01T10:00:01Z",
+		"level":      "error",
+		"message":    "disk full",
+		"line":       "2018-11-01T10:00:01Z error disk full",
+		"path":       path,
+	}, event.Data)
+
+	// a partial line is read once complete
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("2018-11-01T10:00:02Z warn partial")
+	require.NoError(t, err)
+	tailer.poll()
+	require.Len(t, stasher.events, 1)
+	_, err = f.WriteString(" line\n")
+	require.NoError(t, err)
+	f.Close()
+	tailer.poll()
+	require.Equal(t, []string{"2018-11-01T10:00:01Z error disk full", "2018-11-01T10:00:02Z warn partial line"}, stasher.lines())
+
+	// files created later are read from their start
+	appendLines(t, filepath.Join(dir, "other.log"), "2018-11-01T10:00:03Z info started")
+	tailer.poll()
+	require.Len(t, stasher.events, 3)
+	require.Equal(t, "app.other_log.info", stasher.events[2].EventType)
+}
+
+func TestTailerRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	appendLines(t, path)
+
+	stasher := &testStasher{}
+	tailer := testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+
+	// lines written before the rotation are read from the rotated file
+	appendLines(t, path, "t1 info one")
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "app.log.1")))
+	appendLines(t, filepath.Join(dir, "app.log.1"), "t2 info two")
+	appendLines(t, path, "t3 info three")
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t2 info two", "t3 info three"}, stasher.lines())
+
+	// files rotated to a path still matching the globs continue from their offset
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "app.1.log")))
+	appendLines(t, filepath.Join(dir, "app.1.log"), "t3b info renamed")
+	appendLines(t, path)
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t2 info two", "t3 info three", "t3b info renamed"}, stasher.lines())
+	require.NoError(t, os.Remove(filepath.Join(dir, "app.1.log")))
+	tailer.poll()
+
+	// truncated files are read from their start
+	require.NoError(t, os.Truncate(path, 0))
+	appendLines(t, path, "t4 info four")
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t2 info two", "t3 info three", "t3b info renamed", "t4 info four"}, stasher.lines())
+	require.NotEqual(t, stasher.events[2].EventID, stasher.events[4].EventID)
+}
+
+func TestTailerRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	appendLines(t, path)
+
+	stasher := &testStasher{}
+	tailer := testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+
+	appendLines(t, path, "t1 info one")
+	tailer.poll()
+
+	// lines failing to be stashed are read again
+	stasher.err = &inputs.ForwardError{Leader: "leader:4445", Path: "/event", Err: fmt.Errorf("connection refused")}
+	appendLines(t, path, "t2 info two")
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one"}, stasher.lines())
+	tailer.close()
+
+	// the restarted tailer continues after the last stashed line
+	stasher.err = nil
+	appendLines(t, path, "t3 info three")
+	tailer = testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t2 info two", "t3 info three"}, stasher.lines())
+	tailer.close()
+
+	// a file rotated while stopped is read from its start
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "app.1")))
+	appendLines(t, path, "t4 info four")
+	tailer = testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t2 info two", "t3 info three", "t4 info four"}, stasher.lines())
+	tailer.close()
+}
+
+func TestTailerRejected(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	appendLines(t, path)
+
+	stasher := &testStasher{reject: "t2 info two"}
+	tailer := testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+
+	// a line rejected by the leader is skipped, the next lines are read
+	appendLines(t, path, "t1 info one", "t2 info two", "t3 info three")
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t3 info three"}, stasher.lines())
+
+	appendLines(t, path, "t4 info four")
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t3 info three", "t4 info four"}, stasher.lines())
+	tailer.close()
+}
+
+func TestTailerRestartRenamed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	appendLines(t, path)
+
+	stasher := &testStasher{}
+	tailer := testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+	appendLines(t, path, "t1 info one")
+	tailer.poll()
+	tailer.close()
+
+	// a file rotated to a path matching the globs while stopped continues from its offset
+	rotated := filepath.Join(dir, "app.1.log")
+	require.NoError(t, os.Rename(path, rotated))
+	appendLines(t, rotated, "t2 info two")
+	appendLines(t, path, "t3 info three")
+	tailer = testTailer(t, dir, stasher, `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`)
+	tailer.poll()
+	require.Equal(t, []string{"t1 info one", "t2 info two", "t3 info three"}, stasher.lines())
+	tailer.close()
+}
+
+func TestTailerShared(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	appendLines(t, path)
+
+	stasher := &lockedStasher{}
+	node := &testNode{}
+	tailer, err := New(&config.Config{
+		Dir:        dir,
+		TailPaths:  filepath.Join(dir, "*.log"),
+		TailShared: true,
+	}, stasher, node)
+	require.NoError(t, err)
+	tailer.leaderCheckInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		tailer.Run(ctx)
+		close(done)
+	}()
+
+	// followers don't tail the shared files
+	appendLines(t, path, "t1 info one")
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 0, stasher.len())
+
+	// the leader continues after the offsets saved by the previous one
+	offsets, err := loadOffsets(filepath.Join(dir, "tail_offsets.json"))
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	offsets.set(path, inode(info), info.Size())
+	require.NoError(t, offsets.save())
+
+	appendLines(t, path, "t2 info two")
+	node.setLeader(true)
+	require.Eventually(t, func() bool { return stasher.len() == 1 }, 3*time.Second, 10*time.Millisecond)
+
+	node.setLeader(false)
+	time.Sleep(100 * time.Millisecond)
+	appendLines(t, path, "t3 info three")
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 1, stasher.len())
+
+	cancel()
+	<-done
+}
+
+type lockedStasher struct {
+	mu     sync.Mutex
+	events []*events.Event
+}
+
+func (s *lockedStasher) Stash(event *events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *lockedStasher) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
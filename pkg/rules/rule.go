@@ -6,26 +6,118 @@ import (
 	"github.com/myntra/cortex/pkg/matcher"
 )
 
+const (
+	// WindowTimeProcessing measures the dwell window by the time the events are received
+	WindowTimeProcessing = "processing"
+	// WindowTimeEvent measures the dwell window by the events' event_time
+	WindowTimeEvent = "event"
+)
+
+const (
+	// LatePolicyDrop drops late events and counts them in the rule's next execution record
+	LatePolicyDrop = "drop"
+	// LatePolicyNextBucket adds late events to the open bucket, or a new bucket
+	LatePolicyNextBucket = "next_bucket"
+	// LatePolicyReexecute executes the rule's latest execution bucket again along with the late event
+	LatePolicyReexecute = "reexecute"
+)
+
+const (
+	// WindowTypeDwell windows wait dwell ms for events and expand by dwell, up to max_dwell, for events arriving after
+	// dwell_deadline
+	WindowTypeDwell = "dwell"
+	// WindowTypeTumbling windows are fixed, non overlapping windows of window_size ms
+	WindowTypeTumbling = "tumbling"
+	// WindowTypeSession windows close after session_gap ms without events
+	WindowTypeSession = "session"
+	// WindowTypeHopping windows are fixed windows of window_size ms starting every window_hop ms. windows overlap if
+	// the hop is smaller than the size, i.e sliding windows
+	WindowTypeHopping = "hopping"
+)
+
+const (
+	// UpdatePolicyRebind binds the rule's open bucket to the updated rule
+	UpdatePolicyRebind = "rebind"
+	// UpdatePolicyFlush flushes the rule's open bucket with the rule it was created with
+	UpdatePolicyFlush = "flush"
+	// UpdatePolicyDiscard discards the rule's open bucket
+	UpdatePolicyDiscard = "discard"
+)
+
 //go:generate msgp
 
 // Rule is the array of related service events
 type Rule struct {
-	Title             string   `json:"title"`
-	ID                string   `json:"id"`
-	ScriptID          string   `json:"script_id"`           // javascript script which is called before hookEndPoint is called.
-	HookEndpoint      string   `json:"hook_endpoint"`       // endpoint which accepts a POST json objects
-	HookRetry         int      `json:"hook_retry"`          // number of retries while attempting to post
-	EventTypePatterns []string `json:"event_type_patterns"` // a list of event types to look for. wildcards are allowed.
-	Dwell             uint64   `json:"dwell"`               // dwell duration in milliseconds for events to arrive
-	DwellDeadline     uint64   `json:"dwell_deadline"`      // dwell duration threshold after which arriving events expand the dwell window
-	MaxDwell          uint64   `json:"max_dwell"`           // maximum dwell duration including expansion
-	Regexes           []string `json:"regexes,omitempty"`   // generated regex string array from event types
-	Disabled          bool     `json:"disabled,omitempty"`  // if the rule is disabled
+	Title                string   `json:"title"`
+	ID                   string   `json:"id"`
+	ScriptID             string   `json:"script_id"`                        // javascript script which is called before hookEndPoint is called.
+	HookEndpoint         string   `json:"hook_endpoint"`                    // endpoint which accepts a POST json objects
+	HookRetry            int      `json:"hook_retry"`                       // number of retries while attempting to post
+	EventTypePatterns    []string `json:"event_type_patterns"`              // a list of event types to look for. wildcards are allowed.
+	Dwell                uint64   `json:"dwell"`                            // dwell duration in milliseconds for events to arrive
+	DwellDeadline        uint64   `json:"dwell_deadline"`                   // dwell duration threshold after which arriving events expand the dwell window
+	MaxDwell             uint64   `json:"max_dwell"`                        // maximum dwell duration including expansion
+	Regexes              []string `json:"regexes,omitempty"`                // generated regex string array from event types
+	Disabled             bool     `json:"disabled,omitempty"`               // if the rule is disabled
+	GroupKey             string   `json:"group_key,omitempty"`              // dotted event path(eg: data.host) used to key incidents along with the rule id
+	ResolvePatterns      []string `json:"resolve_patterns,omitempty"`       // a list of event types which resolve an open incident. wildcards are allowed.
+	ResolveRegexes       []string `json:"resolve_regexes,omitempty"`        // generated regex string array from resolve patterns
+	IncidentHookEndpoint string   `json:"incident_hook_endpoint,omitempty"` // endpoint which accepts incident open, update and resolve notifications
+	Priority             int      `json:"priority,omitempty"`               // buckets of higher priority rules are executed first
+	MaxConcurrency       int      `json:"max_concurrency,omitempty"`        // maximum concurrent executions of the rule. 0 is bounded only by the worker pool
+	WindowTime           string   `json:"window_time,omitempty"`            // processing(default) or event
+	AllowedLateness      uint64   `json:"allowed_lateness,omitempty"`       // duration in milliseconds an event time window waits for out of order events
+	LatePolicy           string   `json:"late_policy,omitempty"`            // drop(default), next_bucket or reexecute. applies to event time windows
+	WindowType           string   `json:"window_type,omitempty"`            // dwell(default), tumbling, session or hopping
+	WindowSize           uint64   `json:"window_size,omitempty"`            // tumbling and hopping window duration in milliseconds
+	WindowHop            uint64   `json:"window_hop,omitempty"`             // duration in milliseconds between the start of hopping windows
+	SessionGap           uint64   `json:"session_gap,omitempty"`            // inactivity in milliseconds after which a session window closes
+	UpdatePolicy         string   `json:"update_policy,omitempty"`          // rebind(default), flush or discard the open bucket when the rule is updated
 }
 
 // Validate rule data
 func (r *Rule) Validate() error {
 
+	if r.MaxConcurrency < 0 {
+		return fmt.Errorf("invalid max_concurrency %v, must not be negative", r.MaxConcurrency)
+	}
+
+	switch r.WindowTime {
+	case "", WindowTimeProcessing, WindowTimeEvent:
+	default:
+		return fmt.Errorf("invalid window_time %v, must be %v or %v", r.WindowTime, WindowTimeProcessing, WindowTimeEvent)
+	}
+
+	switch r.GetWindowType() {
+	case WindowTypeDwell:
+	case WindowTypeTumbling:
+		if r.WindowSize == 0 {
+			return fmt.Errorf("window_size is required for %v windows", r.WindowType)
+		}
+	case WindowTypeHopping:
+		if r.WindowSize == 0 || r.WindowHop == 0 || r.WindowHop > r.WindowSize {
+			return fmt.Errorf("window_size and window_hop, not greater than window_size, are required for %v windows", r.WindowType)
+		}
+	case WindowTypeSession:
+		if r.SessionGap == 0 {
+			return fmt.Errorf("session_gap is required for %v windows", r.WindowType)
+		}
+	default:
+		return fmt.Errorf("invalid window_type %v, must be %v, %v, %v or %v", r.WindowType, WindowTypeDwell, WindowTypeTumbling, WindowTypeSession, WindowTypeHopping)
+	}
+
+	switch r.UpdatePolicy {
+	case "", UpdatePolicyRebind, UpdatePolicyFlush, UpdatePolicyDiscard:
+	default:
+		return fmt.Errorf("invalid update_policy %v, must be %v, %v or %v", r.UpdatePolicy, UpdatePolicyRebind, UpdatePolicyFlush, UpdatePolicyDiscard)
+	}
+
+	switch r.LatePolicy {
+	case "", LatePolicyDrop, LatePolicyNextBucket, LatePolicyReexecute:
+	default:
+		return fmt.Errorf("invalid late_policy %v, must be %v, %v or %v", r.LatePolicy, LatePolicyDrop, LatePolicyNextBucket, LatePolicyReexecute)
+	}
+
 	for _, pattern := range r.EventTypePatterns {
 		m, err := matcher.New(pattern)
 		if err != nil {
@@ -35,9 +127,47 @@ func (r *Rule) Validate() error {
 		r.Regexes = append(r.Regexes, m.GetRegexString())
 	}
 
+	for _, pattern := range r.ResolvePatterns {
+		m, err := matcher.New(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid resolve pattern %v,  err: %v", pattern, err)
+		}
+
+		r.ResolveRegexes = append(r.ResolveRegexes, m.GetRegexString())
+	}
+
 	return nil
 }
 
+// IsEventTime returns true if the rule's windows are measured by event time
+func (r *Rule) IsEventTime() bool {
+	return r.WindowTime == WindowTimeEvent
+}
+
+// GetLatePolicy returns the late policy, drop if not set
+func (r *Rule) GetLatePolicy() string {
+	if r.LatePolicy == "" {
+		return LatePolicyDrop
+	}
+	return r.LatePolicy
+}
+
+// GetWindowType returns the window type, dwell if not set
+func (r *Rule) GetWindowType() string {
+	if r.WindowType == "" {
+		return WindowTypeDwell
+	}
+	return r.WindowType
+}
+
+// GetUpdatePolicy returns the update policy, rebind if not set
+func (r *Rule) GetUpdatePolicy() string {
+	if r.UpdatePolicy == "" {
+		return UpdatePolicyRebind
+	}
+	return r.UpdatePolicy
+}
+
 // HasMatching checks whether the rule has a matching event type pattern
 func (r *Rule) HasMatching(eventType string) bool {
 	if r.Disabled {
@@ -52,48 +182,101 @@ func (r *Rule) HasMatching(eventType string) bool {
 	return false
 }
 
+// HasResolveMatching checks whether the rule has a matching resolve pattern
+func (r *Rule) HasResolveMatching(eventType string) bool {
+	if r.Disabled {
+		return false
+	}
+	for _, regexStr := range r.ResolveRegexes {
+		m := matcher.NewCompile(regexStr)
+		if m.HasMatches(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
 // PublicRule is used to create, update a request and is returned as a response
 type PublicRule struct {
-	Title             string   `json:"title"`
-	ID                string   `json:"id"`
-	ScriptID          string   `json:"script_id"`           // javascript script which is called before hookEndPoint is called.
-	HookEndpoint      string   `json:"hook_endpoint"`       // endpoint which accepts a POST json objects
-	HookRetry         int      `json:"hook_retry"`          // number of retries while attempting to post
-	EventTypePatterns []string `json:"event_type_patterns"` // a list of event types to look for. wildcards are allowed.
-	Dwell             uint64   `json:"dwell"`               // dwell duration in milliseconds for events to arrive
-	DwellDeadline     uint64   `json:"dwell_deadline"`      // dwell duration threshold after which arriving events expand the dwell window
-	MaxDwell          uint64   `json:"max_dwell"`           // maximum dwell duration including expansion
-	Disabled          bool     `json:"disabled,omitempty"`  // if the rule is disabled
+	Title                string   `json:"title"`
+	ID                   string   `json:"id"`
+	ScriptID             string   `json:"script_id"`                        // javascript script which is called before hookEndPoint is called.
+	HookEndpoint         string   `json:"hook_endpoint"`                    // endpoint which accepts a POST json objects
+	HookRetry            int      `json:"hook_retry"`                       // number of retries while attempting to post
+	EventTypePatterns    []string `json:"event_type_patterns"`              // a list of event types to look for. wildcards are allowed.
+	Dwell                uint64   `json:"dwell"`                            // dwell duration in milliseconds for events to arrive
+	DwellDeadline        uint64   `json:"dwell_deadline"`                   // dwell duration threshold after which arriving events expand the dwell window
+	MaxDwell             uint64   `json:"max_dwell"`                        // maximum dwell duration including expansion
+	Disabled             bool     `json:"disabled,omitempty"`               // if the rule is disabled
+	GroupKey             string   `json:"group_key,omitempty"`              // dotted event path(eg: data.host) used to key incidents along with the rule id
+	ResolvePatterns      []string `json:"resolve_patterns,omitempty"`       // a list of event types which resolve an open incident. wildcards are allowed.
+	IncidentHookEndpoint string   `json:"incident_hook_endpoint,omitempty"` // endpoint which accepts incident open, update and resolve notifications
+	Priority             int      `json:"priority,omitempty"`               // buckets of higher priority rules are executed first
+	MaxConcurrency       int      `json:"max_concurrency,omitempty"`        // maximum concurrent executions of the rule. 0 is bounded only by the worker pool
+	WindowTime           string   `json:"window_time,omitempty"`            // processing(default) or event
+	AllowedLateness      uint64   `json:"allowed_lateness,omitempty"`       // duration in milliseconds an event time window waits for out of order events
+	LatePolicy           string   `json:"late_policy,omitempty"`            // drop(default), next_bucket or reexecute. applies to event time windows
+	WindowType           string   `json:"window_type,omitempty"`            // dwell(default), tumbling, session or hopping
+	WindowSize           uint64   `json:"window_size,omitempty"`            // tumbling and hopping window duration in milliseconds
+	WindowHop            uint64   `json:"window_hop,omitempty"`             // duration in milliseconds between the start of hopping windows
+	SessionGap           uint64   `json:"session_gap,omitempty"`            // inactivity in milliseconds after which a session window closes
+	UpdatePolicy         string   `json:"update_policy,omitempty"`          // rebind(default), flush or discard the open bucket when the rule is updated
 }
 
 // NewFromPublic creates a rule from a public rule
 func NewFromPublic(r *PublicRule) *Rule {
 	return &Rule{
-		Title:             r.Title,
-		ID:                r.ID,
-		ScriptID:          r.ScriptID,
-		HookEndpoint:      r.HookEndpoint,
-		HookRetry:         r.HookRetry,
-		EventTypePatterns: r.EventTypePatterns,
-		Dwell:             r.Dwell,
-		DwellDeadline:     r.DwellDeadline,
-		MaxDwell:          r.MaxDwell,
-		Disabled:          r.Disabled,
+		Title:                r.Title,
+		ID:                   r.ID,
+		ScriptID:             r.ScriptID,
+		HookEndpoint:         r.HookEndpoint,
+		HookRetry:            r.HookRetry,
+		EventTypePatterns:    r.EventTypePatterns,
+		Dwell:                r.Dwell,
+		DwellDeadline:        r.DwellDeadline,
+		MaxDwell:             r.MaxDwell,
+		Disabled:             r.Disabled,
+		GroupKey:             r.GroupKey,
+		ResolvePatterns:      r.ResolvePatterns,
+		IncidentHookEndpoint: r.IncidentHookEndpoint,
+		Priority:             r.Priority,
+		MaxConcurrency:       r.MaxConcurrency,
+		WindowTime:           r.WindowTime,
+		AllowedLateness:      r.AllowedLateness,
+		LatePolicy:           r.LatePolicy,
+		WindowType:           r.WindowType,
+		WindowSize:           r.WindowSize,
+		WindowHop:            r.WindowHop,
+		SessionGap:           r.SessionGap,
+		UpdatePolicy:         r.UpdatePolicy,
 	}
 }
 
 // NewFromPrivate creates public rule from a private rule
 func NewFromPrivate(r *Rule) *PublicRule {
 	return &PublicRule{
-		Title:             r.Title,
-		ID:                r.ID,
-		ScriptID:          r.ScriptID,
-		HookEndpoint:      r.HookEndpoint,
-		HookRetry:         r.HookRetry,
-		EventTypePatterns: r.EventTypePatterns,
-		Dwell:             r.Dwell,
-		DwellDeadline:     r.DwellDeadline,
-		MaxDwell:          r.MaxDwell,
-		Disabled:          r.Disabled,
+		Title:                r.Title,
+		ID:                   r.ID,
+		ScriptID:             r.ScriptID,
+		HookEndpoint:         r.HookEndpoint,
+		HookRetry:            r.HookRetry,
+		EventTypePatterns:    r.EventTypePatterns,
+		Dwell:                r.Dwell,
+		DwellDeadline:        r.DwellDeadline,
+		MaxDwell:             r.MaxDwell,
+		Disabled:             r.Disabled,
+		GroupKey:             r.GroupKey,
+		ResolvePatterns:      r.ResolvePatterns,
+		IncidentHookEndpoint: r.IncidentHookEndpoint,
+		Priority:             r.Priority,
+		MaxConcurrency:       r.MaxConcurrency,
+		WindowTime:           r.WindowTime,
+		AllowedLateness:      r.AllowedLateness,
+		LatePolicy:           r.LatePolicy,
+		WindowType:           r.WindowType,
+		WindowSize:           r.WindowSize,
+		WindowHop:            r.WindowHop,
+		SessionGap:           r.SessionGap,
+		UpdatePolicy:         r.UpdatePolicy,
 	}
 }
@@ -84,6 +84,83 @@ func (z *PublicRule) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "GroupKey":
+			z.GroupKey, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "ResolvePatterns":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.ResolvePatterns) >= int(zb0003) {
+				z.ResolvePatterns = (z.ResolvePatterns)[:zb0003]
+			} else {
+				z.ResolvePatterns = make([]string, zb0003)
+			}
+			for za0002 := range z.ResolvePatterns {
+				z.ResolvePatterns[za0002], err = dc.ReadString()
+				if err != nil {
+					return
+				}
+			}
+		case "IncidentHookEndpoint":
+			z.IncidentHookEndpoint, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Priority":
+			z.Priority, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "MaxConcurrency":
+			z.MaxConcurrency, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "WindowTime":
+			z.WindowTime, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "AllowedLateness":
+			z.AllowedLateness, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "LatePolicy":
+			z.LatePolicy, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "WindowType":
+			z.WindowType, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "WindowSize":
+			z.WindowSize, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "WindowHop":
+			z.WindowHop, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "SessionGap":
+			z.SessionGap, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "UpdatePolicy":
+			z.UpdatePolicy, err = dc.ReadString()
+			if err != nil {
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -96,9 +173,9 @@ func (z *PublicRule) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *PublicRule) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 10
+	// map header, size 23
 	// write "Title"
-	err = en.Append(0x8a, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
+	err = en.Append(0xde, 0x0, 0x17, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
 	if err != nil {
 		return
 	}
@@ -193,15 +270,138 @@ func (z *PublicRule) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "GroupKey"
+	err = en.Append(0xa8, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.GroupKey)
+	if err != nil {
+		return
+	}
+	// write "ResolvePatterns"
+	err = en.Append(0xaf, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.ResolvePatterns)))
+	if err != nil {
+		return
+	}
+	for za0002 := range z.ResolvePatterns {
+		err = en.WriteString(z.ResolvePatterns[za0002])
+		if err != nil {
+			return
+		}
+	}
+	// write "IncidentHookEndpoint"
+	err = en.Append(0xb4, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x6f, 0x6b, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.IncidentHookEndpoint)
+	if err != nil {
+		return
+	}
+	// write "Priority"
+	err = en.Append(0xa8, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.Priority)
+	if err != nil {
+		return
+	}
+	// write "MaxConcurrency"
+	err = en.Append(0xae, 0x4d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.MaxConcurrency)
+	if err != nil {
+		return
+	}
+	// write "WindowTime"
+	err = en.Append(0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x69, 0x6d, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.WindowTime)
+	if err != nil {
+		return
+	}
+	// write "AllowedLateness"
+	err = en.Append(0xaf, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.AllowedLateness)
+	if err != nil {
+		return
+	}
+	// write "LatePolicy"
+	err = en.Append(0xaa, 0x4c, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.LatePolicy)
+	if err != nil {
+		return
+	}
+	// write "WindowType"
+	err = en.Append(0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x79, 0x70, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.WindowType)
+	if err != nil {
+		return
+	}
+	// write "WindowSize"
+	err = en.Append(0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.WindowSize)
+	if err != nil {
+		return
+	}
+	// write "WindowHop"
+	err = en.Append(0xa9, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x48, 0x6f, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.WindowHop)
+	if err != nil {
+		return
+	}
+	// write "SessionGap"
+	err = en.Append(0xaa, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x47, 0x61, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.SessionGap)
+	if err != nil {
+		return
+	}
+	// write "UpdatePolicy"
+	err = en.Append(0xac, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.UpdatePolicy)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *PublicRule) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 10
+	// map header, size 23
 	// string "Title"
-	o = append(o, 0x8a, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
+	o = append(o, 0xde, 0x0, 0x17, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
 	o = msgp.AppendString(o, z.Title)
 	// string "ID"
 	o = append(o, 0xa2, 0x49, 0x44)
@@ -233,6 +433,48 @@ func (z *PublicRule) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "Disabled"
 	o = append(o, 0xa8, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64)
 	o = msgp.AppendBool(o, z.Disabled)
+	// string "GroupKey"
+	o = append(o, 0xa8, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.GroupKey)
+	// string "ResolvePatterns"
+	o = append(o, 0xaf, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.ResolvePatterns)))
+	for za0002 := range z.ResolvePatterns {
+		o = msgp.AppendString(o, z.ResolvePatterns[za0002])
+	}
+	// string "IncidentHookEndpoint"
+	o = append(o, 0xb4, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x6f, 0x6b, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74)
+	o = msgp.AppendString(o, z.IncidentHookEndpoint)
+	// string "Priority"
+	o = append(o, 0xa8, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79)
+	o = msgp.AppendInt(o, z.Priority)
+	// string "MaxConcurrency"
+	o = append(o, 0xae, 0x4d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79)
+	o = msgp.AppendInt(o, z.MaxConcurrency)
+	// string "WindowTime"
+	o = append(o, 0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x69, 0x6d, 0x65)
+	o = msgp.AppendString(o, z.WindowTime)
+	// string "AllowedLateness"
+	o = append(o, 0xaf, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73)
+	o = msgp.AppendUint64(o, z.AllowedLateness)
+	// string "LatePolicy"
+	o = append(o, 0xaa, 0x4c, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	o = msgp.AppendString(o, z.LatePolicy)
+	// string "WindowType"
+	o = append(o, 0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x79, 0x70, 0x65)
+	o = msgp.AppendString(o, z.WindowType)
+	// string "WindowSize"
+	o = append(o, 0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65)
+	o = msgp.AppendUint64(o, z.WindowSize)
+	// string "WindowHop"
+	o = append(o, 0xa9, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x48, 0x6f, 0x70)
+	o = msgp.AppendUint64(o, z.WindowHop)
+	// string "SessionGap"
+	o = append(o, 0xaa, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x47, 0x61, 0x70)
+	o = msgp.AppendUint64(o, z.SessionGap)
+	// string "UpdatePolicy"
+	o = append(o, 0xac, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	o = msgp.AppendString(o, z.UpdatePolicy)
 	return
 }
 
@@ -314,6 +556,83 @@ func (z *PublicRule) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "GroupKey":
+			z.GroupKey, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "ResolvePatterns":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.ResolvePatterns) >= int(zb0003) {
+				z.ResolvePatterns = (z.ResolvePatterns)[:zb0003]
+			} else {
+				z.ResolvePatterns = make([]string, zb0003)
+			}
+			for za0002 := range z.ResolvePatterns {
+				z.ResolvePatterns[za0002], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "IncidentHookEndpoint":
+			z.IncidentHookEndpoint, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Priority":
+			z.Priority, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "MaxConcurrency":
+			z.MaxConcurrency, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowTime":
+			z.WindowTime, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "AllowedLateness":
+			z.AllowedLateness, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "LatePolicy":
+			z.LatePolicy, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowType":
+			z.WindowType, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowSize":
+			z.WindowSize, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowHop":
+			z.WindowHop, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "SessionGap":
+			z.SessionGap, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "UpdatePolicy":
+			z.UpdatePolicy, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -327,11 +646,15 @@ func (z *PublicRule) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *PublicRule) Msgsize() (s int) {
-	s = 1 + 6 + msgp.StringPrefixSize + len(z.Title) + 3 + msgp.StringPrefixSize + len(z.ID) + 9 + msgp.StringPrefixSize + len(z.ScriptID) + 13 + msgp.StringPrefixSize + len(z.HookEndpoint) + 10 + msgp.IntSize + 18 + msgp.ArrayHeaderSize
+	s = 3 + 6 + msgp.StringPrefixSize + len(z.Title) + 3 + msgp.StringPrefixSize + len(z.ID) + 9 + msgp.StringPrefixSize + len(z.ScriptID) + 13 + msgp.StringPrefixSize + len(z.HookEndpoint) + 10 + msgp.IntSize + 18 + msgp.ArrayHeaderSize
 	for za0001 := range z.EventTypePatterns {
 		s += msgp.StringPrefixSize + len(z.EventTypePatterns[za0001])
 	}
-	s += 6 + msgp.Uint64Size + 14 + msgp.Uint64Size + 9 + msgp.Uint64Size + 9 + msgp.BoolSize
+	s += 6 + msgp.Uint64Size + 14 + msgp.Uint64Size + 9 + msgp.Uint64Size + 9 + msgp.BoolSize + 9 + msgp.StringPrefixSize + len(z.GroupKey) + 16 + msgp.ArrayHeaderSize
+	for za0002 := range z.ResolvePatterns {
+		s += msgp.StringPrefixSize + len(z.ResolvePatterns[za0002])
+	}
+	s += 21 + msgp.StringPrefixSize + len(z.IncidentHookEndpoint) + 9 + msgp.IntSize + 15 + msgp.IntSize + 11 + msgp.StringPrefixSize + len(z.WindowTime) + 16 + msgp.Uint64Size + 11 + msgp.StringPrefixSize + len(z.LatePolicy) + 11 + msgp.StringPrefixSize + len(z.WindowType) + 11 + msgp.Uint64Size + 10 + msgp.Uint64Size + 11 + msgp.Uint64Size + 13 + msgp.StringPrefixSize + len(z.UpdatePolicy)
 	return
 }
 
@@ -430,6 +753,100 @@ func (z *Rule) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "GroupKey":
+			z.GroupKey, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "ResolvePatterns":
+			var zb0004 uint32
+			zb0004, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.ResolvePatterns) >= int(zb0004) {
+				z.ResolvePatterns = (z.ResolvePatterns)[:zb0004]
+			} else {
+				z.ResolvePatterns = make([]string, zb0004)
+			}
+			for za0003 := range z.ResolvePatterns {
+				z.ResolvePatterns[za0003], err = dc.ReadString()
+				if err != nil {
+					return
+				}
+			}
+		case "ResolveRegexes":
+			var zb0005 uint32
+			zb0005, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.ResolveRegexes) >= int(zb0005) {
+				z.ResolveRegexes = (z.ResolveRegexes)[:zb0005]
+			} else {
+				z.ResolveRegexes = make([]string, zb0005)
+			}
+			for za0004 := range z.ResolveRegexes {
+				z.ResolveRegexes[za0004], err = dc.ReadString()
+				if err != nil {
+					return
+				}
+			}
+		case "IncidentHookEndpoint":
+			z.IncidentHookEndpoint, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Priority":
+			z.Priority, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "MaxConcurrency":
+			z.MaxConcurrency, err = dc.ReadInt()
+			if err != nil {
+				return
+			}
+		case "WindowTime":
+			z.WindowTime, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "AllowedLateness":
+			z.AllowedLateness, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "LatePolicy":
+			z.LatePolicy, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "WindowType":
+			z.WindowType, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "WindowSize":
+			z.WindowSize, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "WindowHop":
+			z.WindowHop, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "SessionGap":
+			z.SessionGap, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "UpdatePolicy":
+			z.UpdatePolicy, err = dc.ReadString()
+			if err != nil {
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -442,9 +859,9 @@ func (z *Rule) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Rule) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 11
+	// map header, size 25
 	// write "Title"
-	err = en.Append(0x8b, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
+	err = en.Append(0xde, 0x0, 0x19, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
 	if err != nil {
 		return
 	}
@@ -554,15 +971,153 @@ func (z *Rule) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "GroupKey"
+	err = en.Append(0xa8, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.GroupKey)
+	if err != nil {
+		return
+	}
+	// write "ResolvePatterns"
+	err = en.Append(0xaf, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.ResolvePatterns)))
+	if err != nil {
+		return
+	}
+	for za0003 := range z.ResolvePatterns {
+		err = en.WriteString(z.ResolvePatterns[za0003])
+		if err != nil {
+			return
+		}
+	}
+	// write "ResolveRegexes"
+	err = en.Append(0xae, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x52, 0x65, 0x67, 0x65, 0x78, 0x65, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.ResolveRegexes)))
+	if err != nil {
+		return
+	}
+	for za0004 := range z.ResolveRegexes {
+		err = en.WriteString(z.ResolveRegexes[za0004])
+		if err != nil {
+			return
+		}
+	}
+	// write "IncidentHookEndpoint"
+	err = en.Append(0xb4, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x6f, 0x6b, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.IncidentHookEndpoint)
+	if err != nil {
+		return
+	}
+	// write "Priority"
+	err = en.Append(0xa8, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.Priority)
+	if err != nil {
+		return
+	}
+	// write "MaxConcurrency"
+	err = en.Append(0xae, 0x4d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.MaxConcurrency)
+	if err != nil {
+		return
+	}
+	// write "WindowTime"
+	err = en.Append(0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x69, 0x6d, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.WindowTime)
+	if err != nil {
+		return
+	}
+	// write "AllowedLateness"
+	err = en.Append(0xaf, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.AllowedLateness)
+	if err != nil {
+		return
+	}
+	// write "LatePolicy"
+	err = en.Append(0xaa, 0x4c, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.LatePolicy)
+	if err != nil {
+		return
+	}
+	// write "WindowType"
+	err = en.Append(0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x79, 0x70, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.WindowType)
+	if err != nil {
+		return
+	}
+	// write "WindowSize"
+	err = en.Append(0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.WindowSize)
+	if err != nil {
+		return
+	}
+	// write "WindowHop"
+	err = en.Append(0xa9, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x48, 0x6f, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.WindowHop)
+	if err != nil {
+		return
+	}
+	// write "SessionGap"
+	err = en.Append(0xaa, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x47, 0x61, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.SessionGap)
+	if err != nil {
+		return
+	}
+	// write "UpdatePolicy"
+	err = en.Append(0xac, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.UpdatePolicy)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Rule) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 11
+	// map header, size 25
 	// string "Title"
-	o = append(o, 0x8b, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
+	o = append(o, 0xde, 0x0, 0x19, 0xa5, 0x54, 0x69, 0x74, 0x6c, 0x65)
 	o = msgp.AppendString(o, z.Title)
 	// string "ID"
 	o = append(o, 0xa2, 0x49, 0x44)
@@ -600,6 +1155,54 @@ func (z *Rule) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "Disabled"
 	o = append(o, 0xa8, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64)
 	o = msgp.AppendBool(o, z.Disabled)
+	// string "GroupKey"
+	o = append(o, 0xa8, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79)
+	o = msgp.AppendString(o, z.GroupKey)
+	// string "ResolvePatterns"
+	o = append(o, 0xaf, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.ResolvePatterns)))
+	for za0003 := range z.ResolvePatterns {
+		o = msgp.AppendString(o, z.ResolvePatterns[za0003])
+	}
+	// string "ResolveRegexes"
+	o = append(o, 0xae, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x52, 0x65, 0x67, 0x65, 0x78, 0x65, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.ResolveRegexes)))
+	for za0004 := range z.ResolveRegexes {
+		o = msgp.AppendString(o, z.ResolveRegexes[za0004])
+	}
+	// string "IncidentHookEndpoint"
+	o = append(o, 0xb4, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x48, 0x6f, 0x6f, 0x6b, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74)
+	o = msgp.AppendString(o, z.IncidentHookEndpoint)
+	// string "Priority"
+	o = append(o, 0xa8, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79)
+	o = msgp.AppendInt(o, z.Priority)
+	// string "MaxConcurrency"
+	o = append(o, 0xae, 0x4d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79)
+	o = msgp.AppendInt(o, z.MaxConcurrency)
+	// string "WindowTime"
+	o = append(o, 0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x69, 0x6d, 0x65)
+	o = msgp.AppendString(o, z.WindowTime)
+	// string "AllowedLateness"
+	o = append(o, 0xaf, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73)
+	o = msgp.AppendUint64(o, z.AllowedLateness)
+	// string "LatePolicy"
+	o = append(o, 0xaa, 0x4c, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	o = msgp.AppendString(o, z.LatePolicy)
+	// string "WindowType"
+	o = append(o, 0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x54, 0x79, 0x70, 0x65)
+	o = msgp.AppendString(o, z.WindowType)
+	// string "WindowSize"
+	o = append(o, 0xaa, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65)
+	o = msgp.AppendUint64(o, z.WindowSize)
+	// string "WindowHop"
+	o = append(o, 0xa9, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x48, 0x6f, 0x70)
+	o = msgp.AppendUint64(o, z.WindowHop)
+	// string "SessionGap"
+	o = append(o, 0xaa, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x47, 0x61, 0x70)
+	o = msgp.AppendUint64(o, z.SessionGap)
+	// string "UpdatePolicy"
+	o = append(o, 0xac, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79)
+	o = msgp.AppendString(o, z.UpdatePolicy)
 	return
 }
 
@@ -698,6 +1301,100 @@ func (z *Rule) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "GroupKey":
+			z.GroupKey, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "ResolvePatterns":
+			var zb0004 uint32
+			zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.ResolvePatterns) >= int(zb0004) {
+				z.ResolvePatterns = (z.ResolvePatterns)[:zb0004]
+			} else {
+				z.ResolvePatterns = make([]string, zb0004)
+			}
+			for za0003 := range z.ResolvePatterns {
+				z.ResolvePatterns[za0003], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "ResolveRegexes":
+			var zb0005 uint32
+			zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.ResolveRegexes) >= int(zb0005) {
+				z.ResolveRegexes = (z.ResolveRegexes)[:zb0005]
+			} else {
+				z.ResolveRegexes = make([]string, zb0005)
+			}
+			for za0004 := range z.ResolveRegexes {
+				z.ResolveRegexes[za0004], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "IncidentHookEndpoint":
+			z.IncidentHookEndpoint, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Priority":
+			z.Priority, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "MaxConcurrency":
+			z.MaxConcurrency, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowTime":
+			z.WindowTime, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "AllowedLateness":
+			z.AllowedLateness, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "LatePolicy":
+			z.LatePolicy, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowType":
+			z.WindowType, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowSize":
+			z.WindowSize, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "WindowHop":
+			z.WindowHop, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "SessionGap":
+			z.SessionGap, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "UpdatePolicy":
+			z.UpdatePolicy, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -711,7 +1408,7 @@ func (z *Rule) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *Rule) Msgsize() (s int) {
-	s = 1 + 6 + msgp.StringPrefixSize + len(z.Title) + 3 + msgp.StringPrefixSize + len(z.ID) + 9 + msgp.StringPrefixSize + len(z.ScriptID) + 13 + msgp.StringPrefixSize + len(z.HookEndpoint) + 10 + msgp.IntSize + 18 + msgp.ArrayHeaderSize
+	s = 3 + 6 + msgp.StringPrefixSize + len(z.Title) + 3 + msgp.StringPrefixSize + len(z.ID) + 9 + msgp.StringPrefixSize + len(z.ScriptID) + 13 + msgp.StringPrefixSize + len(z.HookEndpoint) + 10 + msgp.IntSize + 18 + msgp.ArrayHeaderSize
 	for za0001 := range z.EventTypePatterns {
 		s += msgp.StringPrefixSize + len(z.EventTypePatterns[za0001])
 	}
@@ -719,6 +1416,14 @@ func (z *Rule) Msgsize() (s int) {
 	for za0002 := range z.Regexes {
 		s += msgp.StringPrefixSize + len(z.Regexes[za0002])
 	}
-	s += 9 + msgp.BoolSize
+	s += 9 + msgp.BoolSize + 9 + msgp.StringPrefixSize + len(z.GroupKey) + 16 + msgp.ArrayHeaderSize
+	for za0003 := range z.ResolvePatterns {
+		s += msgp.StringPrefixSize + len(z.ResolvePatterns[za0003])
+	}
+	s += 15 + msgp.ArrayHeaderSize
+	for za0004 := range z.ResolveRegexes {
+		s += msgp.StringPrefixSize + len(z.ResolveRegexes[za0004])
+	}
+	s += 21 + msgp.StringPrefixSize + len(z.IncidentHookEndpoint) + 9 + msgp.IntSize + 15 + msgp.IntSize + 11 + msgp.StringPrefixSize + len(z.WindowTime) + 16 + msgp.Uint64Size + 11 + msgp.StringPrefixSize + len(z.LatePolicy) + 11 + msgp.StringPrefixSize + len(z.WindowType) + 11 + msgp.Uint64Size + 10 + msgp.Uint64Size + 11 + msgp.Uint64Size + 13 + msgp.StringPrefixSize + len(z.UpdatePolicy)
 	return
 }
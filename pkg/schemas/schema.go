@@ -0,0 +1,249 @@
+package schemas
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/matcher"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// ModeReject rejects the events failing validation
+	ModeReject = "reject"
+	// ModeTag accepts the events failing validation and tags them as invalid in their extensions
+	ModeTag = "tag"
+)
+
+const (
+	// ExtensionInvalid is the extension set to true on an event failing validation in the tag mode
+	ExtensionInvalid = "schema_invalid"
+	// ExtensionErrors is the extension listing the validation errors of an event in the tag mode
+	ExtensionErrors = "schema_errors"
+)
+
+//go:generate msgp
+//msgp:ignore InvalidEventError SchemaError compileFailure offlineLoader offlineLoaderFactory offlineReferenceLoader
+
+const (
+	compileRetryMin = time.Second // delay before compiling a failed schema again, doubled on each failure
+	compileRetryMax = time.Minute
+)
+
+// Schema is a json schema validating the data of the events whose type matches the event type patterns, or
+// whose schemaURL is the schema url
+type Schema struct {
+	ID                string      `json:"id"`
+	URL               string      `json:"url,omitempty"`
+	EventTypePatterns []string    `json:"event_type_patterns,omitempty"`
+	Schema            interface{} `json:"schema"`
+
+	matchers []*matcher.Matcher
+	compiled atomic.Value // *gojsonschema.Schema
+	failure  atomic.Value // *compileFailure of the last compile by ValidateEvent
+}
+
+// compileFailure is cached until retryAt, so an unreachable remote $ref isn't fetched for every event
+type compileFailure struct {
+	err     *SchemaError
+	retryAt time.Time
+	delay   time.Duration
+}
+
+// Validate the schema and compile it, loading the remote $refs of the schema
+func (s *Schema) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("schema id is required")
+	}
+
+	if s.URL == "" && len(s.EventTypePatterns) == 0 {
+		return fmt.Errorf("url or event_type_patterns is required")
+	}
+
+	if s.Schema == nil {
+		return fmt.Errorf("schema is required")
+	}
+
+	matchers, err := s.compileMatchers()
+	if err != nil {
+		return err
+	}
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(s.Schema))
+	if err != nil {
+		return fmt.Errorf("invalid json schema: %v", err)
+	}
+
+	s.matchers = matchers
+	s.compiled.Store(compiled)
+	return nil
+}
+
+// Compile the event type patterns and the schema without loading its remote $refs, for the schemas applied by raft.
+// A schema with remote $refs fails to compile here and is compiled by ValidateEvent instead
+func (s *Schema) Compile() error {
+	matchers, err := s.compileMatchers()
+	if err != nil {
+		return err
+	}
+	s.matchers = matchers
+
+	compiled, err := gojsonschema.NewSchema(offlineLoader{gojsonschema.NewGoLoader(s.Schema)})
+	if err != nil {
+		return fmt.Errorf("invalid json schema: %v", err)
+	}
+
+	s.compiled.Store(compiled)
+	return nil
+}
+
+func (s *Schema) compileMatchers() ([]*matcher.Matcher, error) {
+	var matchers []*matcher.Matcher
+	for _, pattern := range s.EventTypePatterns {
+		m, err := matcher.New(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event type pattern %v, err: %v", pattern, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// compile returns the compiled schema, compiling it with its remote $refs if Compile could not. A failure is
+// returned again until its retry delay passed
+func (s *Schema) compile() (*gojsonschema.Schema, error) {
+	if compiled, ok := s.compiled.Load().(*gojsonschema.Schema); ok {
+		return compiled, nil
+	}
+
+	now := time.Now()
+	failure, _ := s.failure.Load().(*compileFailure)
+	if failure != nil && now.Before(failure.retryAt) {
+		return nil, failure.err
+	}
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(s.Schema))
+	if err != nil {
+		delay := compileRetryMin
+		if failure != nil {
+			delay = failure.delay * 2
+		}
+		if delay > compileRetryMax {
+			delay = compileRetryMax
+		}
+
+		schemaErr := &SchemaError{SchemaID: s.ID, Err: err}
+		s.failure.Store(&compileFailure{err: schemaErr, retryAt: now.Add(delay), delay: delay})
+		return nil, schemaErr
+	}
+
+	s.compiled.Store(compiled)
+	return compiled, nil
+}
+
+// offlineLoader loads the schema without its remote $refs
+type offlineLoader struct {
+	gojsonschema.JSONLoader
+}
+
+func (offlineLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return offlineLoaderFactory{}
+}
+
+type offlineLoaderFactory struct{}
+
+func (offlineLoaderFactory) New(source string) gojsonschema.JSONLoader {
+	return offlineReferenceLoader{gojsonschema.NewReferenceLoader(source)}
+}
+
+type offlineReferenceLoader struct {
+	gojsonschema.JSONLoader
+}
+
+func (l offlineReferenceLoader) LoadJSON() (interface{}, error) {
+	return nil, fmt.Errorf("remote $ref %v is not loaded", l.JsonSource())
+}
+
+func (offlineReferenceLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return offlineLoaderFactory{}
+}
+
+// HasMatching checks whether the schema has a matching event type pattern
+func (s *Schema) HasMatching(eventType string) bool {
+	for _, m := range s.matchers {
+		if m.HasMatches(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEvent returns the validation errors of the event's data. A schema not compiled yet is compiled first, a
+// SchemaError is returned if the schema can't be compiled or applied
+func (s *Schema) ValidateEvent(event *events.Event) ([]string, error) {
+	compiled, err := s.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := compiled.Validate(gojsonschema.NewGoLoader(event.Data))
+	if err != nil {
+		return nil, &SchemaError{SchemaID: s.ID, Err: fmt.Errorf("error validating event %s: %v", event.EventID, err)}
+	}
+
+	var errs []string
+	for _, e := range result.Errors() {
+		errs = append(errs, s.ID+": "+e.String())
+	}
+
+	return errs, nil
+}
+
+// Tag the event as invalid in its extensions
+func Tag(event *events.Event, errs []string) {
+	extensions := event.ExtensionsMap()
+
+	list := make([]interface{}, len(errs))
+	for i, e := range errs {
+		list[i] = e
+	}
+
+	extensions[ExtensionInvalid] = true
+	extensions[ExtensionErrors] = list
+}
+
+// InvalidEventError is returned for an event failing validation in the reject mode
+type InvalidEventError struct {
+	EventID string
+	Errors  []string
+}
+
+func (e *InvalidEventError) Error() string {
+	return fmt.Sprintf("event %s is invalid: %s", e.EventID, strings.Join(e.Errors, "; "))
+}
+
+// SchemaError is returned if a schema fails to compile, e.g. its remote $refs can't be loaded, or to validate an
+// event. It is a fault of the schema, not of the event
+type SchemaError struct {
+	SchemaID string
+	Err      error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("schema %s is unavailable: %v", e.SchemaID, e.Err)
+}
+
+// IsSchemaError returns true if the error is a SchemaError
+func IsSchemaError(err error) bool {
+	_, ok := err.(*SchemaError)
+	return ok
+}
+
+// IsInvalidEvent returns true if the error is an InvalidEventError
+func IsInvalidEvent(err error) bool {
+	_, ok := err.(*InvalidEventError)
+	return ok
+}
@@ -0,0 +1,152 @@
+package schemas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchema() *Schema {
+	return &Schema{
+		ID:                "disk",
+		EventTypePatterns: []string{"acme.*.disk_full"},
+		Schema: map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"host", "used"},
+			"properties": map[string]interface{}{
+				"host": map[string]interface{}{"type": "string"},
+				"used": map[string]interface{}{"type": "number", "maximum": 100},
+			},
+		},
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	require.NoError(t, testSchema().Validate())
+
+	schema := testSchema()
+	schema.ID = ""
+	require.Error(t, schema.Validate())
+
+	schema = testSchema()
+	schema.EventTypePatterns = nil
+	require.Error(t, schema.Validate())
+	schema.URL = "https://schemas.acme.com/disk.json"
+	require.NoError(t, schema.Validate())
+
+	schema = testSchema()
+	schema.EventTypePatterns = []string{"acme."}
+	require.Error(t, schema.Validate())
+
+	schema = testSchema()
+	schema.Schema = map[string]interface{}{"type": "nothing"}
+	require.Error(t, schema.Validate())
+}
+
+func TestSchemaValidateEvent(t *testing.T) {
+	schema := testSchema()
+	require.NoError(t, schema.Validate())
+
+	require.True(t, schema.HasMatching("acme.prod.disk_full"))
+	require.False(t, schema.HasMatching("acme.prod.cpu"))
+
+	errs, err := schema.ValidateEvent(&events.Event{EventID: "1", Data: map[string]interface{}{"host": "web-1", "used": 99}})
+	require.NoError(t, err)
+	require.Empty(t, errs)
+
+	event := &events.Event{EventID: "2", Data: map[string]interface{}{"host": "web-1", "used": 120}, Extensions: map[string]interface{}{"team": "infra"}}
+	errs, err = schema.ValidateEvent(event)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0], "disk: used")
+
+	Tag(event, errs)
+	require.Equal(t, map[string]interface{}{
+		"team":           "infra",
+		ExtensionInvalid: true,
+		ExtensionErrors:  []interface{}{errs[0]},
+	}, event.Extensions)
+
+	errs, err = schema.ValidateEvent(&events.Event{EventID: "3"})
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+
+	// schemas not compiled yet are compiled first
+	errs, err = testSchema().ValidateEvent(event)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+
+	schema = testSchema()
+	schema.Schema = map[string]interface{}{"type": "nothing"}
+	_, err = schema.ValidateEvent(event)
+	require.Error(t, err)
+}
+
+func TestSchemaCompile(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"type": "string"}`))
+	}))
+	defer server.Close()
+
+	schema := testSchema()
+	schema.Schema = map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"host": map[string]interface{}{"$ref": server.URL + "/host.json"}},
+	}
+
+	// remote $refs are not loaded by Compile
+	require.Error(t, schema.Compile())
+	require.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	require.True(t, schema.HasMatching("acme.prod.disk_full"))
+
+	// but on the first validated event
+	errs, err := schema.ValidateEvent(&events.Event{EventID: "1", Data: map[string]interface{}{"host": 1}})
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// a failed compile is cached until its retry delay passed, which doubles on each failure
+	var unavailable int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&unavailable, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	schema = testSchema()
+	schema.Schema = map[string]interface{}{"$ref": down.URL + "/disk.json"}
+	require.Error(t, schema.Compile())
+
+	for i := 0; i < 3; i++ {
+		_, err = schema.ValidateEvent(&events.Event{EventID: "1"})
+		require.True(t, IsSchemaError(err))
+		require.False(t, IsInvalidEvent(err))
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&unavailable))
+
+	failure := schema.failure.Load().(*compileFailure)
+	require.Equal(t, compileRetryMin, failure.delay)
+	failure.retryAt = time.Now()
+	_, err = schema.ValidateEvent(&events.Event{EventID: "1"})
+	require.True(t, IsSchemaError(err))
+	require.Equal(t, int32(2), atomic.LoadInt32(&unavailable))
+	require.Equal(t, 2*compileRetryMin, schema.failure.Load().(*compileFailure).delay)
+
+	// local $refs are
+	schema = testSchema()
+	schema.Schema = map[string]interface{}{
+		"definitions": map[string]interface{}{"host": map[string]interface{}{"type": "string"}},
+		"properties":  map[string]interface{}{"host": map[string]interface{}{"$ref": "#/definitions/host"}},
+	}
+	require.NoError(t, schema.Compile())
+	errs, err = schema.ValidateEvent(&events.Event{EventID: "2", Data: map[string]interface{}{"host": 1}})
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/imdario/mergo"
+	"github.com/myntra/cortex/pkg/cortexpb"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/js"
+	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
+	"github.com/myntra/cortex/pkg/store"
+	"github.com/satori/go.uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// grpcServer serves the grpc api of the node. Like the leaderProxy of the rest api, writes received by a
+// follower are forwarded to the leader
+type grpcServer struct {
+	cortexpb.UnimplementedCortexServer
+	node *store.Node
+
+	mu         sync.Mutex
+	leaderAddr string
+	leaderConn *grpc.ClientConn
+}
+
+func newGRPCServer(node *store.Node) *grpcServer {
+	return &grpcServer{node: node}
+}
+
+// leader returns a client of the leader, or nil if the node is the leader
+func (g *grpcServer) leader() (cortexpb.CortexClient, error) {
+	leaderAddr := g.node.LeaderGRPCAddr()
+	if leaderAddr == "" {
+		return nil, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.leaderConn == nil || g.leaderAddr != leaderAddr {
+		if g.leaderConn != nil {
+			g.leaderConn.Close()
+		}
+
+		conn, err := grpc.Dial(leaderAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			g.leaderConn = nil
+			return nil, status.Errorf(codes.Unavailable, "error connecting to leader %s: %v", leaderAddr, err)
+		}
+
+		glog.Infof("forwarding grpc requests to leader at %v", leaderAddr)
+		g.leaderAddr = leaderAddr
+		g.leaderConn = conn
+	}
+
+	return cortexpb.NewCortexClient(g.leaderConn), nil
+}
+
+func (g *grpcServer) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.leaderConn != nil {
+		g.leaderConn.Close()
+		g.leaderConn = nil
+	}
+}
+
+// grpcError maps a node error to a status with the code, unavailable if the request can be retried or invalid
+// argument for an event failing validation
+func grpcError(err error, code codes.Code) error {
+	if store.IsUnavailable(err) || schemas.IsSchemaError(err) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if schemas.IsInvalidEvent(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(code, err.Error())
+}
+
+// checkDeadline returns the status of the context error, if the request was cancelled or its deadline passed
+// before it is applied
+func checkDeadline(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// PublishEvents ingests each request's events with batched raft applies and acks them in order
+func (g *grpcServer) PublishEvents(stream cortexpb.Cortex_PublishEventsServer) error {
+	leader, err := g.leader()
+	if err != nil {
+		return err
+	}
+	if leader != nil {
+		return g.forwardPublishEvents(stream, leader)
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := checkDeadline(stream.Context()); err != nil {
+			return err
+		}
+
+		batch := make([]*events.Event, 0, len(req.Events))
+		for i, pbEvent := range req.Events {
+			event, err := toEvent(pbEvent)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "event %d: %v", i, err)
+			}
+			batch = append(batch, event)
+		}
+
+		results, err := g.node.IngestBatch(batch)
+		if err != nil {
+			return grpcError(err, codes.Internal)
+		}
+
+		resp := &cortexpb.PublishEventsResponse{Acks: make([]*cortexpb.EventAck, 0, len(results))}
+		for _, result := range results {
+			resp.Acks = append(resp.Acks, fromIngestResult(result))
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// forwardPublishEvents relays the stream to the leader, request by request
+func (g *grpcServer) forwardPublishEvents(stream cortexpb.Cortex_PublishEventsServer, leader cortexpb.CortexClient) error {
+	leaderStream, err := leader.PublishEvents(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return leaderStream.CloseSend()
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := leaderStream.Send(req); err != nil {
+			// the leader's status is returned by Recv
+			_, err = leaderStream.Recv()
+			return err
+		}
+
+		resp, err := leaderStream.Recv()
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *grpcServer) GetRules(ctx context.Context, req *cortexpb.GetRulesRequest) (*cortexpb.GetRulesResponse, error) {
+	resp := &cortexpb.GetRulesResponse{}
+	for _, rule := range g.node.GetRules() {
+		resp.Rules = append(resp.Rules, fromRule(rules.NewFromPrivate(rule)))
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) GetRule(ctx context.Context, req *cortexpb.GetRuleRequest) (*cortexpb.Rule, error) {
+	rule := g.node.GetRule(req.Id)
+	if rule == nil {
+		return nil, status.Errorf(codes.NotFound, "rule %s not found", req.Id)
+	}
+	return fromRule(rules.NewFromPrivate(rule)), nil
+}
+
+func (g *grpcServer) AddRule(ctx context.Context, req *cortexpb.Rule) (*cortexpb.Rule, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.AddRule(ctx, req)
+	}
+
+	if err := checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	rule := toRule(req)
+	if rule.ID == "" {
+		rule.ID = uuid.NewV4().String()
+	}
+
+	if err := g.node.AddRule(rules.NewFromPublic(rule)); err != nil {
+		return nil, grpcError(err, codes.InvalidArgument)
+	}
+
+	return fromRule(rule), nil
+}
+
+func (g *grpcServer) UpdateRule(ctx context.Context, req *cortexpb.Rule) (*cortexpb.Rule, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.UpdateRule(ctx, req)
+	}
+
+	if err := checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	existingRule := g.node.GetRule(req.Id)
+	if existingRule == nil {
+		return nil, status.Errorf(codes.NotFound, "rule %s not found", req.Id)
+	}
+
+	rule := toRule(req)
+	if err := mergo.Merge(rule, rules.NewFromPrivate(existingRule)); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := g.node.UpdateRule(rules.NewFromPublic(rule)); err != nil {
+		return nil, grpcError(err, codes.InvalidArgument)
+	}
+
+	return fromRule(rule), nil
+}
+
+func (g *grpcServer) RemoveRule(ctx context.Context, req *cortexpb.RemoveRuleRequest) (*emptypb.Empty, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.RemoveRule(ctx, req)
+	}
+
+	if err := checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := g.node.RemoveRule(req.Id); err != nil {
+		return nil, grpcError(err, codes.NotFound)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (g *grpcServer) GetScripts(ctx context.Context, req *cortexpb.GetScriptsRequest) (*cortexpb.GetScriptsResponse, error) {
+	return &cortexpb.GetScriptsResponse{Ids: g.node.GetScripts()}, nil
+}
+
+func (g *grpcServer) GetScript(ctx context.Context, req *cortexpb.GetScriptRequest) (*cortexpb.Script, error) {
+	script := g.node.GetScript(req.Id)
+	if script == nil || len(script.Data) == 0 {
+		return nil, status.Errorf(codes.NotFound, "script %s not found", req.Id)
+	}
+	return &cortexpb.Script{Id: script.ID, Data: script.Data}, nil
+}
+
+func (g *grpcServer) AddScript(ctx context.Context, req *cortexpb.Script) (*emptypb.Empty, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.AddScript(ctx, req)
+	}
+
+	return g.applyScript(ctx, req, g.node.AddScript)
+}
+
+func (g *grpcServer) UpdateScript(ctx context.Context, req *cortexpb.Script) (*emptypb.Empty, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.UpdateScript(ctx, req)
+	}
+
+	return g.applyScript(ctx, req, g.node.UpdateScript)
+}
+
+func (g *grpcServer) applyScript(ctx context.Context, req *cortexpb.Script, apply func(*js.Script) error) (*emptypb.Empty, error) {
+	if err := checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	sr := &ScriptRequest{ID: req.Id, Data: req.Data}
+	if err := sr.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := apply(&js.Script{ID: sr.ID, Data: sr.Data}); err != nil {
+		return nil, grpcError(err, codes.InvalidArgument)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (g *grpcServer) RemoveScript(ctx context.Context, req *cortexpb.RemoveScriptRequest) (*emptypb.Empty, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.RemoveScript(ctx, req)
+	}
+
+	if err := checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := g.node.RemoveScript(req.Id); err != nil {
+		return nil, grpcError(err, codes.NotFound)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (g *grpcServer) GetRuleExecutions(ctx context.Context, req *cortexpb.GetRuleExecutionsRequest) (*cortexpb.GetRuleExecutionsResponse, error) {
+	page, err := g.node.QueryRuleExecutions(toExecutionsQuery(req))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &cortexpb.GetRuleExecutionsResponse{NextCursor: page.NextCursor}
+	for _, record := range page.Records {
+		pbRecord, err := fromRecord(record)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "record %s: %v", record.ID, err)
+		}
+		resp.Records = append(resp.Records, pbRecord)
+	}
+
+	return resp, nil
+}
+
+// GetExecutionQueue returns the leader's execution pool stats, which is the only node executing buckets
+func (g *grpcServer) GetExecutionQueue(ctx context.Context, req *cortexpb.GetExecutionQueueRequest) (*cortexpb.ExecutionQueue, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.GetExecutionQueue(ctx, req)
+	}
+
+	return fromExecutionPoolStats(g.node.GetExecutionPoolStats()), nil
+}
+
+func (g *grpcServer) GetRetention(ctx context.Context, req *cortexpb.GetRetentionRequest) (*cortexpb.Retention, error) {
+	return fromRetention(g.node.GetRetention()), nil
+}
+
+func (g *grpcServer) UpdateRetention(ctx context.Context, req *cortexpb.Retention) (*cortexpb.Retention, error) {
+	leader, err := g.leader()
+	if err != nil {
+		return nil, err
+	}
+	if leader != nil {
+		return leader.UpdateRetention(ctx, req)
+	}
+
+	if err := checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	retention := toRetention(req)
+	if err := g.node.SetRetention(retention); err != nil {
+		return nil, grpcError(err, codes.InvalidArgument)
+	}
+
+	return fromRetention(retention), nil
+}
+
+// grpcLogger logs the failed grpc requests
+func grpcLogger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		glog.Errorf("grpc %s: %v", info.FullMethod, err)
+	}
+	return resp, err
+}
+
+func newGRPC(node *store.Node) (*grpc.Server, *grpcServer) {
+	srv := grpc.NewServer(grpc.UnaryInterceptor(grpcLogger))
+	api := newGRPCServer(node)
+	cortexpb.RegisterCortexServer(srv, api)
+	return srv, api
+}
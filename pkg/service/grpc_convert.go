@@ -0,0 +1,268 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/myntra/cortex/pkg/cortexpb"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/store"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// conversions between the grpc messages and the types of the rest api
+
+func toEvent(e *cortexpb.Event) (*events.Event, error) {
+	event := &events.Event{
+		EventType:          e.EventType,
+		EventTypeVersion:   e.EventTypeVersion,
+		CloudEventsVersion: e.CloudEventsVersion,
+		Source:             e.Source,
+		EventID:            e.EventId,
+		SchemaURL:          e.SchemaUrl,
+		ContentType:        e.ContentType,
+	}
+
+	if e.EventTime != nil {
+		if err := e.EventTime.CheckValid(); err != nil {
+			return nil, err
+		}
+		event.EventTime = e.EventTime.AsTime()
+	}
+
+	if e.Extensions != nil {
+		event.Extensions = e.Extensions.AsInterface()
+	}
+
+	if e.Data != nil {
+		event.Data = e.Data.AsInterface()
+	}
+
+	return event, nil
+}
+
+func fromEvent(e *events.Event) (*cortexpb.Event, error) {
+	event := &cortexpb.Event{
+		EventType:          e.EventType,
+		EventTypeVersion:   e.EventTypeVersion,
+		CloudEventsVersion: e.CloudEventsVersion,
+		Source:             e.Source,
+		EventId:            e.EventID,
+		EventTime:          fromTime(e.EventTime),
+		SchemaUrl:          e.SchemaURL,
+		ContentType:        e.ContentType,
+	}
+
+	var err error
+	if event.Extensions, err = toValue(e.Extensions); err != nil {
+		return nil, err
+	}
+
+	if event.Data, err = toValue(e.Data); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// toValue converts the json representation of v, which may hold any type restored by msgp
+func toValue(v interface{}) (*structpb.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	value := &structpb.Value{}
+	if err := protojson.Unmarshal(b, value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func fromTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toTime(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
+
+func fromIngestResult(r *store.IngestResult) *cortexpb.EventAck {
+	ack := &cortexpb.EventAck{
+		EventId:      r.EventID,
+		MatchedRules: r.MatchedRules,
+		Error:        r.Error,
+	}
+
+	for _, b := range r.Buckets {
+		ack.Buckets = append(ack.Buckets, &cortexpb.StashResult{
+			RuleId:              b.RuleID,
+			Joined:              b.Joined,
+			NewBucket:           b.NewBucket,
+			Deduplicated:        b.Deduplicated,
+			Late:                b.Late,
+			Reexecuted:          b.Reexecuted,
+			BucketCreatedAt:     fromTime(b.BucketCreatedAt),
+			ReexecutionDeferred: b.ReexecutionDeferred,
+		})
+	}
+
+	return ack
+}
+
+func toRule(r *cortexpb.Rule) *rules.PublicRule {
+	return &rules.PublicRule{
+		Title:                r.Title,
+		ID:                   r.Id,
+		ScriptID:             r.ScriptId,
+		HookEndpoint:         r.HookEndpoint,
+		HookRetry:            int(r.HookRetry),
+		EventTypePatterns:    r.EventTypePatterns,
+		Dwell:                r.Dwell,
+		DwellDeadline:        r.DwellDeadline,
+		MaxDwell:             r.MaxDwell,
+		Disabled:             r.Disabled,
+		GroupKey:             r.GroupKey,
+		ResolvePatterns:      r.ResolvePatterns,
+		IncidentHookEndpoint: r.IncidentHookEndpoint,
+		Priority:             int(r.Priority),
+		MaxConcurrency:       int(r.MaxConcurrency),
+		WindowTime:           r.WindowTime,
+		AllowedLateness:      r.AllowedLateness,
+		LatePolicy:           r.LatePolicy,
+		WindowType:           r.WindowType,
+		WindowSize:           r.WindowSize,
+		WindowHop:            r.WindowHop,
+		SessionGap:           r.SessionGap,
+		UpdatePolicy:         r.UpdatePolicy,
+	}
+}
+
+func fromRule(r *rules.PublicRule) *cortexpb.Rule {
+	return &cortexpb.Rule{
+		Title:                r.Title,
+		Id:                   r.ID,
+		ScriptId:             r.ScriptID,
+		HookEndpoint:         r.HookEndpoint,
+		HookRetry:            int32(r.HookRetry),
+		EventTypePatterns:    r.EventTypePatterns,
+		Dwell:                r.Dwell,
+		DwellDeadline:        r.DwellDeadline,
+		MaxDwell:             r.MaxDwell,
+		Disabled:             r.Disabled,
+		GroupKey:             r.GroupKey,
+		ResolvePatterns:      r.ResolvePatterns,
+		IncidentHookEndpoint: r.IncidentHookEndpoint,
+		Priority:             int32(r.Priority),
+		MaxConcurrency:       int32(r.MaxConcurrency),
+		WindowTime:           r.WindowTime,
+		AllowedLateness:      r.AllowedLateness,
+		LatePolicy:           r.LatePolicy,
+		WindowType:           r.WindowType,
+		WindowSize:           r.WindowSize,
+		WindowHop:            r.WindowHop,
+		SessionGap:           r.SessionGap,
+		UpdatePolicy:         r.UpdatePolicy,
+	}
+}
+
+func toExecutionsQuery(r *cortexpb.GetRuleExecutionsRequest) *executions.Query {
+	return &executions.Query{
+		RuleID:         r.RuleId,
+		From:           toTime(r.From),
+		To:             toTime(r.To),
+		HookStatusCode: int(r.HookStatusCode),
+		ScriptStatus:   r.ScriptStatus,
+		Order:          r.Order,
+		Limit:          int(r.Limit),
+		Cursor:         r.Cursor,
+	}
+}
+
+func fromRecord(r *executions.Record) (*cortexpb.ExecutionRecord, error) {
+	record := &cortexpb.ExecutionRecord{
+		Id:             r.ID,
+		ScriptStatus:   r.ScriptStatus,
+		HookStatusCode: int32(r.HookStatusCode),
+		LateEvents:     int32(r.LateEvents),
+		CreatedAt:      fromTime(r.CreatedAt),
+		Bucket: &cortexpb.Bucket{
+			Rule:       fromRule(rules.NewFromPrivate(&r.Bucket.Rule)),
+			FlushLock:  r.Bucket.FlushLock,
+			UpdatedAt:  fromTime(r.Bucket.UpdatedAt),
+			CreatedAt:  fromTime(r.Bucket.CreatedAt),
+			LateEvents: int32(r.Bucket.LateEvents),
+			Extension:  r.Bucket.Extension,
+		},
+	}
+
+	var err error
+	if record.ScriptResult, err = toValue(r.ScriptResult); err != nil {
+		return nil, err
+	}
+
+	for _, e := range r.Bucket.Events {
+		event, err := fromEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		record.Bucket.Events = append(record.Bucket.Events, event)
+	}
+
+	return record, nil
+}
+
+func fromExecutionPoolStats(s *store.ExecutionPoolStats) *cortexpb.ExecutionQueue {
+	queue := &cortexpb.ExecutionQueue{
+		Workers:       int32(s.Workers),
+		QueueCapacity: int32(s.QueueCapacity),
+		Queued:        int32(s.Queued),
+		Running:       int32(s.Running),
+		Executed:      s.Executed,
+		Deferred:      s.Deferred,
+		Backpressure:  s.Backpressure,
+		QueuedByRule:  make(map[string]int32, len(s.QueuedByRule)),
+		RunningByRule: make(map[string]int32, len(s.RunningByRule)),
+	}
+
+	for ruleID, n := range s.QueuedByRule {
+		queue.QueuedByRule[ruleID] = int32(n)
+	}
+
+	for ruleID, n := range s.RunningByRule {
+		queue.RunningByRule[ruleID] = int32(n)
+	}
+
+	return queue
+}
+
+func toRetention(r *cortexpb.Retention) *executions.Retention {
+	return &executions.Retention{
+		MaxRecords:        int(r.MaxRecords),
+		MaxRecordsPerRule: int(r.MaxRecordsPerRule),
+		MaxAge:            r.MaxAge,
+	}
+}
+
+func fromRetention(r *executions.Retention) *cortexpb.Retention {
+	return &cortexpb.Retention{
+		MaxRecords:        int32(r.MaxRecords),
+		MaxRecordsPerRule: int32(r.MaxRecordsPerRule),
+		MaxAge:            r.MaxAge,
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myntra/cortex/pkg/cortexpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcClient connects to the grpc server of the service at the http url, whose port is the http port + 1
+func grpcClient(t *testing.T, url string) (cortexpb.CortexClient, func()) {
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+	httpPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	conn, err := grpc.Dial(net.JoinHostPort(host, strconv.Itoa(httpPort+1)), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	return cortexpb.NewCortexClient(conn), func() { conn.Close() }
+}
+
+func requireCode(t *testing.T, code codes.Code, err error) {
+	require.Error(t, err)
+	require.Equal(t, code, status.Code(err), err.Error())
+}
+
+// grpctest writes with the writer client and reads with the reader client
+func grpctest(t *testing.T, writer, reader cortexpb.CortexClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// rules
+	rule, err := writer.AddRule(ctx, &cortexpb.Rule{
+		Title:             "grpc",
+		HookEndpoint:      "http://localhost:3000/testrule",
+		EventTypePatterns: []string{"acme.grpc.*"},
+		Dwell:             1000,
+		DwellDeadline:     800,
+		MaxDwell:          2000,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, rule.Id)
+
+	got, err := reader.GetRule(ctx, &cortexpb.GetRuleRequest{Id: rule.Id})
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme.grpc.*"}, got.EventTypePatterns)
+
+	rulesResp, err := reader.GetRules(ctx, &cortexpb.GetRulesRequest{})
+	require.NoError(t, err)
+	require.Len(t, rulesResp.Rules, 1)
+
+	updated, err := writer.UpdateRule(ctx, &cortexpb.Rule{Id: rule.Id, Title: "grpc updated"})
+	require.NoError(t, err)
+	require.Equal(t, "grpc updated", updated.Title)
+	require.Equal(t, []string{"acme.grpc.*"}, updated.EventTypePatterns)
+
+	_, err = writer.UpdateRule(ctx, &cortexpb.Rule{Id: "missing"})
+	requireCode(t, codes.NotFound, err)
+
+	_, err = writer.AddRule(ctx, &cortexpb.Rule{EventTypePatterns: []string{"acme.*"}, WindowType: "sliding"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	// events
+	stream, err := writer.PublishEvents(ctx)
+	require.NoError(t, err)
+
+	data, err := structpb.NewValue(map[string]interface{}{"host": "web-1"})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, stream.Send(&cortexpb.PublishEventsRequest{Events: []*cortexpb.Event{
+			{EventType: "acme.grpc.disk_full", EventId: "grpc-" + strconv.Itoa(i), Source: "grpc_test", CloudEventsVersion: "0.1", EventTime: timestamppb.Now(), Data: data},
+			{EventType: "acme.other.disk_full", EventId: "other-" + strconv.Itoa(i), Source: "grpc_test", CloudEventsVersion: "0.1"},
+		}}))
+
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.Len(t, resp.Acks, 2)
+		require.Equal(t, "grpc-"+strconv.Itoa(i), resp.Acks[0].EventId)
+		require.Equal(t, []string{rule.Id}, resp.Acks[0].MatchedRules)
+		require.Len(t, resp.Acks[0].Buckets, 1)
+		require.Equal(t, i == 0, resp.Acks[0].Buckets[0].NewBucket)
+		require.Empty(t, resp.Acks[1].MatchedRules)
+	}
+
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+
+	// scripts
+	_, err = writer.AddScript(ctx, &cortexpb.Script{Id: scriptRequest.ID, Data: scriptRequest.Data})
+	require.NoError(t, err)
+
+	_, err = writer.AddScript(ctx, &cortexpb.Script{Id: "empty"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = writer.UpdateScript(ctx, &cortexpb.Script{Id: scriptRequestUpdated.ID, Data: scriptRequestUpdated.Data})
+	require.NoError(t, err)
+
+	scripts, err := reader.GetScripts(ctx, &cortexpb.GetScriptsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{scriptRequest.ID}, scripts.Ids)
+
+	script, err := reader.GetScript(ctx, &cortexpb.GetScriptRequest{Id: scriptRequest.ID})
+	require.NoError(t, err)
+	require.Equal(t, scriptRequestUpdated.Data, script.Data)
+
+	_, err = writer.RemoveScript(ctx, &cortexpb.RemoveScriptRequest{Id: scriptRequest.ID})
+	require.NoError(t, err)
+
+	_, err = reader.GetScript(ctx, &cortexpb.GetScriptRequest{Id: scriptRequest.ID})
+	requireCode(t, codes.NotFound, err)
+
+	// executions
+	retention, err := writer.UpdateRetention(ctx, &cortexpb.Retention{MaxRecords: 500, MaxRecordsPerRule: 50, MaxAge: 60000})
+	require.NoError(t, err)
+	require.Equal(t, int32(50), retention.MaxRecordsPerRule)
+
+	retention, err = reader.GetRetention(ctx, &cortexpb.GetRetentionRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(500), retention.MaxRecords)
+
+	_, err = writer.UpdateRetention(ctx, &cortexpb.Retention{MaxRecords: -1})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = reader.GetRuleExecutions(ctx, &cortexpb.GetRuleExecutionsRequest{RuleId: rule.Id})
+	require.NoError(t, err)
+
+	_, err = reader.GetRuleExecutions(ctx, &cortexpb.GetRuleExecutionsRequest{RuleId: rule.Id, Order: "random"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = writer.GetExecutionQueue(ctx, &cortexpb.GetExecutionQueueRequest{})
+	require.NoError(t, err)
+
+	// deadlines
+	expired, cancelExpired := context.WithTimeout(ctx, -time.Second)
+	defer cancelExpired()
+	_, err = writer.RemoveRule(expired, &cortexpb.RemoveRuleRequest{Id: rule.Id})
+	requireCode(t, codes.DeadlineExceeded, err)
+
+	_, err = writer.RemoveRule(ctx, &cortexpb.RemoveRuleRequest{Id: rule.Id})
+	require.NoError(t, err)
+
+	_, err = reader.GetRule(ctx, &cortexpb.GetRuleRequest{Id: rule.Id})
+	requireCode(t, codes.NotFound, err)
+}
+
+func TestGRPCSingleService(t *testing.T) {
+	singleService(t, func(url string) {
+		client, closeClient := grpcClient(t, url)
+		defer closeClient()
+		grpctest(t, client, client)
+	})
+}
+
+func TestGRPCMultiService(t *testing.T) {
+	multiService(t, func(urls []string) {
+		leader, closeLeader := grpcClient(t, urls[0])
+		defer closeLeader()
+		follower, closeFollower := grpcClient(t, urls[1])
+		defer closeFollower()
+
+		// the writes on the follower are forwarded to the leader
+		grpctest(t, follower, leader)
+	})
+}
@@ -1,21 +1,31 @@
 package service
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/myntra/cortex/pkg/executions"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/glog"
 	"github.com/imdario/mergo"
+	"github.com/myntra/cortex/pkg/enrichment"
 	"github.com/myntra/cortex/pkg/events"
 	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
+	"github.com/myntra/cortex/pkg/store"
 	"github.com/myntra/cortex/pkg/util"
 	"github.com/satori/go.uuid"
 )
@@ -58,14 +68,120 @@ func (s *Service) eventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.node.Stash(&event)
+	// async acknowledges the event before it is stashed, failures are only logged
+	if r.URL.Query().Get("async") == "true" {
+		go func() {
+			if _, err := s.node.Ingest(&event); err != nil {
+				glog.Errorf("async stash event %v err %v", event.EventID, err)
+			}
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	result, err := s.node.Ingest(&event)
+	if err != nil {
+		stashErr(w, r, "error stashing event", err)
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		util.ErrStatus(w, r, "error writing match results", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// batchEventHandler expects a json array of events, or newline delimited events, in the request body
+func (s *Service) batchEventHandler(w http.ResponseWriter, r *http.Request) {
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected cloudevents.io events", http.StatusNotAcceptable, err)
+		return
+	}
+
+	defer r.Body.Close()
+
+	batch, err := decodeEvents(body)
+	if err != nil {
+		util.ErrStatus(w, r, "parsing failed, expected a json array or newline delimited cloudevents.io events", http.StatusNotAcceptable, err)
+		return
+	}
+
+	results, err := s.node.IngestBatch(batch)
+	if err != nil {
+		stashErr(w, r, "error stashing events", err)
+		return
+	}
+
+	b, err := json.Marshal(results)
 	if err != nil {
-		util.ErrStatus(w, r, "error stashing event", http.StatusInternalServerError, err)
+		util.ErrStatus(w, r, "error writing match results", http.StatusInternalServerError, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// decodeEvents decodes a json array of events or newline delimited events. null events are rejected
+func decodeEvents(body []byte) ([]*events.Event, error) {
+	body = bytes.TrimSpace(body)
+	batch := make([]*events.Event, 0)
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		for {
+			var event *events.Event
+			err := decoder.Decode(&event)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			batch = append(batch, event)
+		}
+	}
+
+	for i, event := range batch {
+		if event == nil {
+			return nil, fmt.Errorf("event %d is null", i)
+		}
+	}
+
+	return batch, nil
+}
+
+// stashErrStatus returns 503 for errors which the producer should retry, i.e leadership changes, raft timeouts and
+// schemas which can't be compiled, and 400 for events failing the validation of their schemas
+func stashErrStatus(err error) int {
+	if store.IsUnavailable(err) || schemas.IsSchemaError(err) {
+		return http.StatusServiceUnavailable
+	}
+	if schemas.IsInvalidEvent(err) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// stashErr writes the stash error. The validation errors of an invalid event are returned to the producer
+func stashErr(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if schemas.IsInvalidEvent(err) {
+		message = err.Error()
+	}
+	util.ErrStatus(w, r, message, stashErrStatus(err), err)
 }
 
 func (s *Service) addRuleHandler(w http.ResponseWriter, r *http.Request) {
@@ -203,24 +319,130 @@ func (s *Service) getRulesHandler(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// getRulesExecutions returns a page of the rule's executions. the query parameters from, to(RFC3339), status(hook status code),
+// script_status, order(asc, desc), limit and cursor filter and paginate the records. the cursor for the next page is
+// returned in the X-Next-Cursor header.
 func (s *Service) getRulesExecutions(w http.ResponseWriter, r *http.Request) {
-	ruleID := chi.URLParam(r, "id")
-	records := make([]*executions.Record, 0)
-	rs := s.node.GetRuleExectutions(ruleID)
-	records = append(records, rs...)
+	query, err := executionsQuery(chi.URLParam(r, "id"), r.URL.Query())
+	if err != nil {
+		util.ErrStatus(w, r, "invalid executions query", http.StatusBadRequest, err)
+		return
+	}
 
-	b, err := json.Marshal(records)
+	page, err := s.node.QueryRuleExecutions(query)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid executions query", http.StatusBadRequest, err)
+		return
+	}
+
+	b, err := json.Marshal(page.Records)
 	if err != nil {
 		util.ErrStatus(w, r, "records marshalling failed", http.StatusNotFound, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if page.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
 	w.WriteHeader(http.StatusOK)
 	w.Write(b)
 
 }
 
+func executionsQuery(ruleID string, values url.Values) (*executions.Query, error) {
+	query := &executions.Query{
+		RuleID:       ruleID,
+		ScriptStatus: values.Get("script_status"),
+		Order:        values.Get("order"),
+		Cursor:       values.Get("cursor"),
+	}
+
+	var err error
+	if v := values.Get("from"); v != "" {
+		if query.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("invalid from %v", err)
+		}
+	}
+
+	if v := values.Get("to"); v != "" {
+		if query.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("invalid to %v", err)
+		}
+	}
+
+	if v := values.Get("status"); v != "" {
+		if query.HookStatusCode, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid status %v", err)
+		}
+	}
+
+	if v := values.Get("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid limit %v", err)
+		}
+	}
+
+	return query, nil
+}
+
+func (s *Service) getExecutionQueueHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.node.GetExecutionPoolStats())
+	if err != nil {
+		util.ErrStatus(w, r, "execution queue stats marshalling failed", http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) getRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.node.GetRetention())
+	if err != nil {
+		util.ErrStatus(w, r, "retention marshalling failed", http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) updateRetentionHandler(w http.ResponseWriter, r *http.Request) {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected a valid retention", http.StatusNotAcceptable, err)
+		return
+	}
+
+	defer r.Body.Close()
+
+	var retention executions.Retention
+	err = json.Unmarshal(reqBody, &retention)
+	if err != nil {
+		util.ErrStatus(w, r, "retention parsing failed", http.StatusNotAcceptable, err)
+		return
+	}
+
+	err = s.node.SetRetention(&retention)
+	if err != nil {
+		util.ErrStatus(w, r, "updating retention failed", http.StatusNotAcceptable, err)
+		return
+	}
+
+	b, err := json.Marshal(&retention)
+	if err != nil {
+		util.ErrStatus(w, r, "retention marshalling failed", http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
 // ScriptRequest is the container for add/update script
 type ScriptRequest struct {
 	ID   string `json:"id"`
@@ -369,8 +591,26 @@ func (s *Service) getScriptListHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
-func (s *Service) site247AlertHandler(w http.ResponseWriter, r *http.Request) {
+// sinkHandler converts the payload with the sink and stashes the events
+func (s *Service) sinkHandler(sink sinks.Sink) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.ingestSink(w, r, sink)
+	}
+}
 
+// genericSinkHandler converts the payload with the sink definition served at the path and stashes the events
+func (s *Service) genericSinkHandler(w http.ResponseWriter, r *http.Request) {
+	path := chi.URLParam(r, "path")
+	sink := s.node.GetSinkByPath(path)
+	if sink == nil {
+		util.ErrStatus(w, r, "sink not found", http.StatusNotFound, fmt.Errorf("no sink at path %s", path))
+		return
+	}
+
+	s.ingestSink(w, r, sink)
+}
+
+func (s *Service) ingestSink(w http.ResponseWriter, r *http.Request, sink sinks.Sink) {
 	alertData, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		util.ErrStatus(w, r, "invalid request body", http.StatusNotAcceptable, err)
@@ -378,24 +618,130 @@ func (s *Service) site247AlertHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	defer r.Body.Close()
-	alert := &sinks.Site247Alert{}
-	err = json.Unmarshal(alertData, alert)
+
+	batch, err := sink.Decode(alertData)
 	if err != nil {
-		util.ErrStatus(w, r, "invalid request body", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "invalid request body for sink "+sink.Name(), decodeErrStatus(err), err)
+		return
+	}
+
+	results, err := s.node.IngestBatch(batch)
+	if err != nil {
+		stashErr(w, r, "error stashing events", err)
+		return
+	}
+
+	var response interface{} = results
+	if responder, ok := sink.(sinks.EventResponder); ok && responder.RespondsWithEvent() && len(batch) == 1 {
+		response = batch[0]
+	}
+
+	b, err := json.Marshal(response)
+	if err != nil {
+		util.ErrStatus(w, r, "error writing match results", http.StatusInternalServerError, err)
 		return
 	}
 
-	event := sinks.EventFromSite247(*alert)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// decodeErrStatus returns 406 for a payload which isn't json of the sink's alert format and 400 for alerts which the
+// sink can't convert into events
+func decodeErrStatus(err error) int {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return http.StatusNotAcceptable
+	}
+	return http.StatusBadRequest
+}
+
+// sinkErrStatus maps a sink, schema or enrichment add/update/remove error to a http status
+func sinkErrStatus(err error) int {
+	if store.IsUnavailable(err) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusNotAcceptable
+}
+
+func decodeSink(r *http.Request) (*sinks.GenericSink, error) {
+	sinkData, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+	sink := &sinks.GenericSink{}
+	err = json.Unmarshal(sinkData, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	return sink, sink.Validate()
+}
 
-	err = s.node.Stash(event)
+func (s *Service) addSinkHandler(w http.ResponseWriter, r *http.Request) {
+	sink, err := decodeSink(r)
 	if err != nil {
-		util.ErrStatus(w, r, "error stashing event", http.StatusInternalServerError, err)
+		util.ErrStatus(w, r, "invalid request body, expected a valid sink", http.StatusNotAcceptable, err)
 		return
 	}
 
-	b, err := json.Marshal(event)
+	err = s.node.AddSink(sink)
 	if err != nil {
-		util.ErrStatus(w, r, "error writing event data", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "error adding sink", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) updateSinkHandler(w http.ResponseWriter, r *http.Request) {
+	sink, err := decodeSink(r)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected a valid sink", http.StatusNotAcceptable, err)
+		return
+	}
+
+	err = s.node.UpdateSink(sink)
+	if err != nil {
+		util.ErrStatus(w, r, "error updating sink", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) removeSinkHandler(w http.ResponseWriter, r *http.Request) {
+	sinkID := chi.URLParam(r, "id")
+	err := s.node.RemoveSink(sinkID)
+	if err != nil {
+		status := http.StatusNotFound
+		if store.IsUnavailable(err) {
+			status = http.StatusServiceUnavailable
+		}
+		util.ErrStatus(w, r, "could not remove sink", status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) getSinkHandler(w http.ResponseWriter, r *http.Request) {
+	sinkID := chi.URLParam(r, "id")
+	sink := s.node.GetSink(sinkID)
+	if sink == nil {
+		util.ErrStatus(w, r, "sink not found", http.StatusNotFound, fmt.Errorf("sink %s not found", sinkID))
+		return
+	}
+
+	b, err := json.Marshal(sink)
+	if err != nil {
+		util.ErrStatus(w, r, "error writing sink data", http.StatusInternalServerError, err)
 		return
 	}
 
@@ -404,33 +750,193 @@ func (s *Service) site247AlertHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
-func (s *Service) icingaAlertHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Service) getSinksHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.node.GetSinks())
+	if err != nil {
+		util.ErrStatus(w, r, "sinks list parsing failed", http.StatusInternalServerError, err)
+		return
+	}
 
-	alertData, err := ioutil.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func decodeSchema(r *http.Request) (*schemas.Schema, error) {
+	schemaData, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		util.ErrStatus(w, r, "invalid request body", http.StatusNotAcceptable, err)
+		return nil, err
+	}
+
+	defer r.Body.Close()
+	schema := &schemas.Schema{}
+	err = json.Unmarshal(schemaData, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, schema.Validate()
+}
+
+func (s *Service) addSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	schema, err := decodeSchema(r)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected a valid schema", http.StatusNotAcceptable, err)
 		return
 	}
 
+	err = s.node.AddSchema(schema)
+	if err != nil {
+		util.ErrStatus(w, r, "error adding schema", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) updateSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	schema, err := decodeSchema(r)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected a valid schema", http.StatusNotAcceptable, err)
+		return
+	}
+
+	err = s.node.UpdateSchema(schema)
+	if err != nil {
+		util.ErrStatus(w, r, "error updating schema", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) removeSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	schemaID := chi.URLParam(r, "id")
+	err := s.node.RemoveSchema(schemaID)
+	if err != nil {
+		status := http.StatusNotFound
+		if store.IsUnavailable(err) {
+			status = http.StatusServiceUnavailable
+		}
+		util.ErrStatus(w, r, "could not remove schema", status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) getSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	schemaID := chi.URLParam(r, "id")
+	schema := s.node.GetSchema(schemaID)
+	if schema == nil {
+		util.ErrStatus(w, r, "schema not found", http.StatusNotFound, fmt.Errorf("schema %s not found", schemaID))
+		return
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		util.ErrStatus(w, r, "error writing schema data", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) getSchemasHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.node.GetSchemas())
+	if err != nil {
+		util.ErrStatus(w, r, "schemas list parsing failed", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// decodeTable parses a lookup table from a csv body, if the content type is text/csv, else from a json body. The
+// key column is set with the key query parameter
+func decodeTable(r *http.Request) (*enrichment.Table, error) {
 	defer r.Body.Close()
-	alert := &sinks.IcingaAlert{}
-	err = json.Unmarshal(alertData, alert)
+
+	id := chi.URLParam(r, "id")
+	key := r.URL.Query().Get("key")
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		return enrichment.ParseCSV(id, key, r.Body)
+	}
+
+	return enrichment.ParseJSON(id, key, r.Body)
+}
+
+func (s *Service) addTableHandler(w http.ResponseWriter, r *http.Request) {
+	table, err := decodeTable(r)
 	if err != nil {
-		util.ErrStatus(w, r, "invalid request body", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "invalid request body, expected a csv or json table", http.StatusNotAcceptable, err)
 		return
 	}
 
-	event := sinks.EventFromIcinga(*alert)
+	err = s.node.AddTable(table)
+	if err != nil {
+		util.ErrStatus(w, r, "error adding table", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) updateTableHandler(w http.ResponseWriter, r *http.Request) {
+	table, err := decodeTable(r)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected a csv or json table", http.StatusNotAcceptable, err)
+		return
+	}
 
-	err = s.node.Stash(event)
+	err = s.node.UpdateTable(table)
 	if err != nil {
-		util.ErrStatus(w, r, "error stashing event", http.StatusInternalServerError, err)
+		util.ErrStatus(w, r, "error updating table", sinkErrStatus(err), err)
 		return
 	}
 
-	b, err := json.Marshal(event)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) removeTableHandler(w http.ResponseWriter, r *http.Request) {
+	tableID := chi.URLParam(r, "id")
+	if s.node.GetTable(tableID) == nil {
+		util.ErrStatus(w, r, "table not found", http.StatusNotFound, fmt.Errorf("table %s not found", tableID))
+		return
+	}
+
+	err := s.node.RemoveTable(tableID)
 	if err != nil {
-		util.ErrStatus(w, r, "error writing event data", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "could not remove table", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) getTableHandler(w http.ResponseWriter, r *http.Request) {
+	tableID := chi.URLParam(r, "id")
+	table := s.node.GetTable(tableID)
+	if table == nil {
+		util.ErrStatus(w, r, "table not found", http.StatusNotFound, fmt.Errorf("table %s not found", tableID))
+		return
+	}
+
+	b, err := json.Marshal(table)
+	if err != nil {
+		util.ErrStatus(w, r, "error writing table data", http.StatusInternalServerError, err)
 		return
 	}
 
@@ -439,33 +945,124 @@ func (s *Service) icingaAlertHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
-func (s *Service) azureAlertHandler(w http.ResponseWriter, r *http.Request) {
+// getTablesHandler lists the table ids, the rows are returned per table
+func (s *Service) getTablesHandler(w http.ResponseWriter, r *http.Request) {
+	ids := make([]string, 0)
+	for _, table := range s.node.GetTables() {
+		ids = append(ids, table.ID)
+	}
 
-	alertData, err := ioutil.ReadAll(r.Body)
+	b, err := json.Marshal(ids)
 	if err != nil {
-		util.ErrStatus(w, r, "invalid request body", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "tables list parsing failed", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) getPipelineHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.node.GetPipeline())
+	if err != nil {
+		util.ErrStatus(w, r, "enrichment steps parsing failed", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) setPipelineHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected enrichment steps", http.StatusNotAcceptable, err)
 		return
 	}
 
 	defer r.Body.Close()
-	alert := &sinks.AzureAlert{}
-	err = json.Unmarshal(alertData, alert)
+	pipeline := &enrichment.Pipeline{}
+	err = json.Unmarshal(body, pipeline)
 	if err != nil {
-		util.ErrStatus(w, r, "invalid request body", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "invalid request body, expected enrichment steps", http.StatusNotAcceptable, err)
+		return
+	}
+
+	err = s.node.SetPipeline(pipeline)
+	if err != nil {
+		util.ErrStatus(w, r, "error setting enrichment steps", sinkErrStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) getIncidentsHandler(w http.ResponseWriter, r *http.Request) {
+	list := make([]*incidents.Incident, 0)
+	list = append(list, s.node.GetIncidents(r.URL.Query().Get("status"))...)
+
+	b, err := json.Marshal(&list)
+	if err != nil {
+		util.ErrStatus(w, r, "incidents parsing failed", http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) getIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	incidentID := chi.URLParam(r, "id")
+
+	incident := s.node.GetIncident(incidentID)
+	if incident == nil {
+		util.ErrStatus(w, r, "incident not found", http.StatusNotFound, fmt.Errorf("incident is nil"))
+		return
+	}
+
+	b, err := json.Marshal(incident)
+	if err != nil {
+		util.ErrStatus(w, r, "incident parsing failed", http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func (s *Service) ackIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	incidentID := chi.URLParam(r, "id")
+	err := s.node.AcknowledgeIncident(incidentID)
+	if err != nil {
+		util.ErrStatus(w, r, "could not acknowledge incident", http.StatusNotAcceptable, err)
 		return
 	}
 
-	event := sinks.EventFromAzure(*alert)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
 
-	err = s.node.Stash(event)
+func (s *Service) resolveIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	incidentID := chi.URLParam(r, "id")
+	err := s.node.ResolveIncident(incidentID)
 	if err != nil {
-		util.ErrStatus(w, r, "error stashing event", http.StatusInternalServerError, err)
+		util.ErrStatus(w, r, "could not resolve incident", http.StatusNotAcceptable, err)
 		return
 	}
 
-	b, err := json.Marshal(event)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) getBucketsHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.node.GetBuckets())
 	if err != nil {
-		util.ErrStatus(w, r, "error writing event data", http.StatusNotAcceptable, err)
+		util.ErrStatus(w, r, "buckets marshalling failed", http.StatusNotFound, err)
 		return
 	}
 
@@ -474,6 +1071,92 @@ func (s *Service) azureAlertHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
+func (s *Service) getBucketHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := chi.URLParam(r, "ruleID")
+
+	bucket := s.node.GetBucket(ruleID)
+	if bucket == nil {
+		util.ErrStatus(w, r, "bucket not found", http.StatusNotFound, fmt.Errorf("bucket is nil"))
+		return
+	}
+
+	b, err := json.Marshal(bucket)
+	if err != nil {
+		util.ErrStatus(w, r, "bucket marshalling failed", http.StatusNotFound, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// bucketErrStatus maps a bucket flush, discard or extend error to a http status, fallback is used when the store is
+// available
+func bucketErrStatus(err error, fallback int) int {
+	if store.IsUnavailable(err) {
+		return http.StatusServiceUnavailable
+	}
+	return fallback
+}
+
+func (s *Service) flushBucketHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := chi.URLParam(r, "ruleID")
+	err := s.node.FlushBucket(ruleID)
+	if err != nil {
+		util.ErrStatus(w, r, "could not flush bucket", bucketErrStatus(err, http.StatusNotFound), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) discardBucketHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := chi.URLParam(r, "ruleID")
+	err := s.node.DiscardBucket(ruleID)
+	if err != nil {
+		util.ErrStatus(w, r, "could not discard bucket", bucketErrStatus(err, http.StatusNotFound), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// ExtendBucketRequest pushes a bucket's flush out by extension milliseconds
+type ExtendBucketRequest struct {
+	Extension uint64 `json:"extension"`
+}
+
+func (s *Service) extendBucketHandler(w http.ResponseWriter, r *http.Request) {
+	ruleID := chi.URLParam(r, "ruleID")
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		util.ErrStatus(w, r, "invalid request body, expected a valid extension", http.StatusNotAcceptable, err)
+		return
+	}
+
+	defer r.Body.Close()
+
+	var extendRequest ExtendBucketRequest
+	err = json.Unmarshal(reqBody, &extendRequest)
+	if err != nil {
+		util.ErrStatus(w, r, "extension parsing failed", http.StatusNotAcceptable, err)
+		return
+	}
+
+	err = s.node.ExtendBucket(ruleID, extendRequest.Extension)
+	if err != nil {
+		util.ErrStatus(w, r, "could not extend bucket", bucketErrStatus(err, http.StatusNotAcceptable), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
 func (s *Service) leaveHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	err := s.node.Leave(id)
@@ -15,27 +15,62 @@ import (
 
 	"github.com/GeertJohan/go.rice"
 	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/store"
+	"google.golang.org/grpc"
 )
 
 // Service encapsulates the http server and the raft store
 type Service struct {
 	srv              *http.Server
 	node             *store.Node
+	sinks            *sinks.Registry
 	listener         net.Listener
 	snapshotInterval int
 	httpAddr         string
+	grpcSrv          *grpc.Server
+	grpcAPI          *grpcServer
+	grpcListener     net.Listener
 }
 
 // Shutdown the service
 func (s *Service) Shutdown(ctx context.Context) error {
 	s.srv.Shutdown(ctx)
+	if s.grpcSrv != nil {
+		s.shutdownGRPC(ctx)
+	}
 	if err := s.node.Shutdown(); err != nil {
 		return err
 	}
 	return nil
 }
 
+// shutdownGRPC waits for the grpc requests and streams to finish until the context is done
+func (s *Service) shutdownGRPC(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcSrv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpcSrv.Stop()
+	}
+	s.grpcAPI.close()
+}
+
+// Node returns the raft node of the service
+func (s *Service) Node() *store.Node {
+	return s.node
+}
+
+// Sinks returns the sinks served by the service, for the inputs decoding with them
+func (s *Service) Sinks() *sinks.Registry {
+	return s.sinks
+}
+
 // Start the service
 func (s *Service) Start() error {
 
@@ -51,6 +86,15 @@ func (s *Service) Start() error {
 		}
 	}()
 
+	// start the grpc service
+	if s.grpcSrv != nil {
+		go func() {
+			if err := s.grpcSrv.Serve(s.grpcListener); err != nil {
+				glog.Infof("grpc server closed %v", err)
+			}
+		}()
+	}
+
 	go func() {
 		ticker := time.NewTicker(time.Minute * time.Duration(s.snapshotInterval))
 		for {
@@ -97,6 +141,17 @@ func fileServer(r chi.Router, path string) {
 // New returns the http service wrapper for the store.
 func New(cfg *config.Config) (*Service, error) {
 
+	// built-in sinks with the configured event type templates
+	registry, err := sinks.NewRegistry(
+		sinks.NewAlertmanagerSink(cfg.AlertmanagerEventType),
+		sinks.NewIcingaSink(cfg.IcingaEventType),
+		sinks.NewSite247Sink(cfg.Site247EventType),
+		sinks.NewAzureSink(cfg.AzureEventType),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	node, err := store.NewNode(cfg)
 	if err != nil {
 		return nil, err
@@ -104,6 +159,7 @@ func New(cfg *config.Config) (*Service, error) {
 
 	svc := &Service{
 		node:             node,
+		sinks:            registry,
 		snapshotInterval: cfg.SnapshotInterval,
 		httpAddr:         cfg.HTTPAddr,
 	}
@@ -122,9 +178,11 @@ func New(cfg *config.Config) (*Service, error) {
 	}
 
 	router.Post("/event", svc.leaderProxy(svc.eventHandler))
-	router.Post("/event/sink/site247", svc.leaderProxy(svc.site247AlertHandler))
-	router.Post("/event/sink/icinga", svc.leaderProxy(svc.icingaAlertHandler))
-	router.Post("/event/sink/azure", svc.leaderProxy(svc.azureAlertHandler))
+	router.Post("/events/batch", svc.leaderProxy(svc.batchEventHandler))
+	for _, sink := range registry.Sinks() {
+		router.Post("/event/sink/"+sink.Name(), svc.leaderProxy(svc.sinkHandler(sink)))
+	}
+	router.Post("/event/sink/{path}", svc.leaderProxy(svc.genericSinkHandler))
 
 	router.Get("/rules", svc.getRulesHandler)
 	router.Get("/rules/{id}", svc.getRuleHandler)
@@ -133,12 +191,47 @@ func New(cfg *config.Config) (*Service, error) {
 	router.Put("/rules", svc.leaderProxy(svc.updateRuleHandler))
 	router.Delete("/rules/{id}", svc.leaderProxy(svc.removeRuleHandler))
 
+	router.Get("/executions/queue", svc.leaderProxy(svc.getExecutionQueueHandler))
+	router.Get("/executions/retention", svc.getRetentionHandler)
+	router.Put("/executions/retention", svc.leaderProxy(svc.updateRetentionHandler))
+
+	router.Get("/buckets", svc.getBucketsHandler)
+	router.Get("/buckets/{ruleID}", svc.getBucketHandler)
+	router.Post("/buckets/{ruleID}/flush", svc.leaderProxy(svc.flushBucketHandler))
+	router.Post("/buckets/{ruleID}/extend", svc.leaderProxy(svc.extendBucketHandler))
+	router.Delete("/buckets/{ruleID}", svc.leaderProxy(svc.discardBucketHandler))
+
 	router.Get("/scripts", svc.getScriptListHandler)
 	router.Get("/scripts/{id}", svc.getScriptHandler)
 	router.Post("/scripts", svc.leaderProxy(svc.addScriptHandler))
 	router.Put("/scripts", svc.leaderProxy(svc.updateScriptHandler))
 	router.Delete("/scripts/{id}", svc.leaderProxy(svc.removeScriptHandler))
 
+	router.Get("/sinks", svc.getSinksHandler)
+	router.Get("/sinks/{id}", svc.getSinkHandler)
+	router.Post("/sinks", svc.leaderProxy(svc.addSinkHandler))
+	router.Put("/sinks", svc.leaderProxy(svc.updateSinkHandler))
+	router.Delete("/sinks/{id}", svc.leaderProxy(svc.removeSinkHandler))
+
+	router.Get("/schemas", svc.getSchemasHandler)
+	router.Get("/schemas/{id}", svc.getSchemaHandler)
+	router.Post("/schemas", svc.leaderProxy(svc.addSchemaHandler))
+	router.Put("/schemas", svc.leaderProxy(svc.updateSchemaHandler))
+	router.Delete("/schemas/{id}", svc.leaderProxy(svc.removeSchemaHandler))
+
+	router.Get("/enrichment/tables", svc.getTablesHandler)
+	router.Get("/enrichment/tables/{id}", svc.getTableHandler)
+	router.Post("/enrichment/tables/{id}", svc.leaderProxy(svc.addTableHandler))
+	router.Put("/enrichment/tables/{id}", svc.leaderProxy(svc.updateTableHandler))
+	router.Delete("/enrichment/tables/{id}", svc.leaderProxy(svc.removeTableHandler))
+	router.Get("/enrichment/steps", svc.getPipelineHandler)
+	router.Put("/enrichment/steps", svc.leaderProxy(svc.setPipelineHandler))
+
+	router.Get("/incidents", svc.getIncidentsHandler)
+	router.Get("/incidents/{id}", svc.getIncidentHandler)
+	router.Post("/incidents/{id}/ack", svc.leaderProxy(svc.ackIncidentHandler))
+	router.Post("/incidents/{id}/resolve", svc.leaderProxy(svc.resolveIncidentHandler))
+
 	router.Get("/leave/{id}", svc.leaveHandler)
 	router.Post("/join", svc.joinHandler)
 
@@ -156,5 +249,15 @@ func New(cfg *config.Config) (*Service, error) {
 	svc.srv = srv
 	svc.listener = cfg.HTTPListener
 
+	if cfg.GRPCAddr != "" {
+		svc.grpcListener = cfg.GRPCListener
+		if svc.grpcListener == nil {
+			if svc.grpcListener, err = net.Listen("tcp", cfg.GRPCAddr); err != nil {
+				return nil, err
+			}
+		}
+		svc.grpcSrv, svc.grpcAPI = newGRPC(node)
+	}
+
 	return svc, nil
 }
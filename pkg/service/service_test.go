@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"strings"
@@ -31,6 +32,7 @@ import (
 	"github.com/myntra/cortex/pkg/config"
 	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/store"
 	"gopkg.in/gavv/httpexpect.v1"
 )
 
@@ -238,6 +240,7 @@ func singleService(t *testing.T, f func(url string)) {
 		SnapshotInterval:     30,
 		HTTPAddr:             httpAddr,
 		RaftAddr:             raftAddr,
+		GRPCAddr:             ":6880",
 		HTTPListener:         httpListener,
 		RaftListener:         raftListener,
 	}
@@ -281,6 +284,7 @@ func multiService(t *testing.T, f func(urls []string)) {
 		SnapshotInterval:     30,
 		HTTPAddr:             httpAddr,
 		RaftAddr:             raftAddr,
+		GRPCAddr:             ":7880",
 		HTTPListener:         httpListener,
 		RaftListener:         raftListener,
 	}
@@ -315,6 +319,7 @@ func multiService(t *testing.T, f func(urls []string)) {
 		SnapshotInterval:     30,
 		HTTPAddr:             httpAddr1,
 		RaftAddr:             raftAddr1,
+		GRPCAddr:             ":8880",
 		HTTPListener:         httpListener1,
 		RaftListener:         raftListener1,
 	}
@@ -349,6 +354,7 @@ func multiService(t *testing.T, f func(urls []string)) {
 		SnapshotInterval:     30,
 		HTTPAddr:             httpAddr2,
 		RaftAddr:             raftAddr2,
+		GRPCAddr:             ":9980",
 		HTTPListener:         httpListener2,
 		RaftListener:         raftListener2,
 	}
@@ -743,32 +749,39 @@ func TestConcurrentMultiEventMultiRuleMultiService(t *testing.T) {
 	})
 }
 
-func TestSite247Handler(t *testing.T) {
-	singleService(t, func(url string) {
-		e := httpexpect.New(t, url)
+// postSinkAlert posts the alert to the sink and returns the converted event of the site247, icinga and azure sinks
+func postSinkAlert(t *testing.T, url, sink string, alert interface{}) *events.Event {
+	s, err := json.Marshal(alert)
+	require.NoError(t, err)
 
-		// post event
-		e.POST("/event/sink/site247").WithJSON(testalertsite247).Expect().Status(http.StatusOK)
+	resp, err := http.Post(url+"/event/sink/"+sink, "application/json", bytes.NewReader(s))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-		// fetch rule executions
-		s, err := json.Marshal(testalertsite247)
-		require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
 
-		resp, err := http.Post(url+"/event/sink/site247", "application/json", bytes.NewReader(s))
-		require.NoError(t, err)
+	defer resp.Body.Close()
 
-		body, err := ioutil.ReadAll(resp.Body)
-		require.NoError(t, err)
+	var eventBody events.Event
+	err = json.Unmarshal(body, &eventBody)
+	require.NoError(t, err)
 
-		defer resp.Body.Close()
+	return &eventBody
+}
 
-		var eventBody events.Event
-		err = json.Unmarshal(body, &eventBody)
-		require.NoError(t, err)
+func TestSite247Handler(t *testing.T) {
+	singleService(t, func(url string) {
+		e := httpexpect.New(t, url)
 
+		// post event
+		e.POST("/event/sink/site247").WithJSON(testalertsite247).Expect().Status(http.StatusOK)
+
+		eventBody := postSinkAlert(t, url, "site247", testalertsite247)
 		require.True(t, eventBody.EventType == fmt.Sprintf("site247.%s.%s.%s",
 			testalertsite247.MonitorGroupName, testalertsite247.MonitorName, testalertsite247.Status))
 
+		e.POST("/event/sink/site247").WithBytes([]byte("{")).Expect().Status(http.StatusNotAcceptable)
 	})
 }
 
@@ -779,26 +792,11 @@ func TestIcingaHandler(t *testing.T) {
 		// post event
 		e.POST("/event/sink/icinga").WithJSON(testIcingaAlert).Expect().Status(http.StatusOK)
 
-		// fetch rule executions
-		s, err := json.Marshal(testIcingaAlert)
-		require.NoError(t, err)
-
-		resp, err := http.Post(url+"/event/sink/icinga", "application/json", bytes.NewReader(s))
-		require.NoError(t, err)
-
-		body, err := ioutil.ReadAll(resp.Body)
-		require.NoError(t, err)
-
-		defer resp.Body.Close()
-
-		var eventBody events.Event
-		err = json.Unmarshal(body, &eventBody)
-		require.NoError(t, err)
-
+		eventBody := postSinkAlert(t, url, "icinga", testIcingaAlert)
 		require.True(t, reflect.DeepEqual(eventBody.Data, structs.New(testIcingaAlert).Map()))
 
 		require.True(t, eventBody.EventType == fmt.Sprintf("%s.%s.%s", testIcingaAlert.ServiceDisplayName,
-			testIcingaAlert.HostDisplayName, testIcingaAlert.ServiceOutput))
+			testIcingaAlert.HostDisplayName, testIcingaAlert.ServiceState))
 	})
 }
 
@@ -809,24 +807,222 @@ func TestAzureHandler(t *testing.T) {
 		// post event
 		e.POST("/event/sink/azure").WithJSON(testAzureAlert).Expect().Status(http.StatusOK)
 
-		// fetch rule executions
-		s, err := json.Marshal(testAzureAlert)
-		require.NoError(t, err)
+		eventBody := postSinkAlert(t, url, "azure", testAzureAlert)
+		require.True(t, reflect.DeepEqual(eventBody.Data, structs.New(testAzureAlert).Map()))
 
-		resp, err := http.Post(url+"/event/sink/azure", "application/json", bytes.NewReader(s))
+		require.True(t, eventBody.EventType == fmt.Sprintf("azure.%s", sinks.Segment(testAzureAlert.Data.Context.Activity.ResourceID)))
+	})
+}
+
+func TestAlertmanagerHandler(t *testing.T) {
+	singleService(t, func(url string) {
+		e := httpexpect.New(t, url)
+
+		rule := testRule
+		rule.ID = "alertmanager"
+		rule.EventTypePatterns = []string{"alertmanager.HighLatency.*"}
+		e.POST("/rules").WithJSON(rule).Expect().Status(http.StatusOK)
+
+		webhook := []byte(`{
+			"status": "firing",
+			"receiver": "cortex",
+			"alerts": [
+				{"status": "firing", "labels": {"alertname": "HighLatency"}, "startsAt": "2018-08-03T09:52:26Z", "fingerprint": "a1"},
+				{"status": "resolved", "labels": {"alertname": "HighLatency"}, "endsAt": "2018-08-03T09:55:26Z", "fingerprint": "a2"}
+			]
+		}`)
+
+		resp, err := http.Post(url+"/event/sink/alertmanager", "application/json", bytes.NewReader(webhook))
 		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
 
 		body, err := ioutil.ReadAll(resp.Body)
 		require.NoError(t, err)
 
 		defer resp.Body.Close()
 
-		var eventBody events.Event
-		err = json.Unmarshal(body, &eventBody)
+		var results []*store.IngestResult
+		err = json.Unmarshal(body, &results)
 		require.NoError(t, err)
 
-		require.True(t, reflect.DeepEqual(eventBody.Data, structs.New(testAzureAlert).Map()))
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.Equal(t, []string{rule.ID}, result.MatchedRules)
+		}
+
+		e.POST("/event/sink/alertmanager").WithBytes([]byte(`{"alerts": [{"status": "pending"}]}`)).
+			Expect().Status(http.StatusBadRequest)
+		e.POST("/event/sink/alertmanager").WithBytes([]byte("{")).Expect().Status(http.StatusNotAcceptable)
+	})
+}
+
+func TestGenericSinks(t *testing.T) {
+	singleService(t, func(url string) {
+		e := httpexpect.New(t, url)
+
+		sink := sinks.GenericSink{
+			ID:        "nagios",
+			Path:      "nagios",
+			EventType: "nagios.{{.host}}.{{.state}}",
+			Split:     "$.alerts",
+		}
+
+		e.POST("/sinks").WithJSON(sink).Expect().Status(http.StatusOK)
+		e.POST("/sinks").WithJSON(sink).Expect().Status(http.StatusNotAcceptable)
+		e.POST("/sinks").WithJSON(sinks.GenericSink{ID: "bad", Path: "azure", EventType: "x"}).
+			Expect().Status(http.StatusNotAcceptable)
+		e.GET("/sinks/" + sink.ID).Expect().Status(http.StatusOK).JSON().Equal(sink)
+		e.GET("/sinks").Expect().Status(http.StatusOK).JSON().Array().Length().Equal(1)
+
+		rule := testRule
+		rule.ID = "nagios"
+		rule.EventTypePatterns = []string{"nagios.*.down"}
+		e.POST("/rules").WithJSON(rule).Expect().Status(http.StatusOK)
+
+		results := e.POST("/event/sink/nagios").
+			WithBytes([]byte(`{"alerts": [{"host": "web-1", "state": "down"}, {"host": "web-2", "state": "up"}]}`)).
+			Expect().Status(http.StatusOK).JSON().Array()
+		results.Length().Equal(2)
+		results.Element(0).Object().Value("matched_rules").Array().Equal([]string{rule.ID})
+		results.Element(1).Object().Value("matched_rules").Array().Empty()
+
+		e.POST("/event/sink/nagios").WithBytes([]byte(`{}`)).Expect().Status(http.StatusBadRequest)
+		e.POST("/event/sink/nagios").WithBytes([]byte("{")).Expect().Status(http.StatusBadRequest)
+
+		sink.Path = "nagios2"
+		e.PUT("/sinks").WithJSON(sink).Expect().Status(http.StatusOK)
+		e.POST("/event/sink/nagios").WithBytes([]byte(`{"alerts": []}`)).Expect().Status(http.StatusNotFound)
+		e.POST("/event/sink/nagios2").WithBytes([]byte(`{"alerts": []}`)).Expect().Status(http.StatusOK)
+
+		e.DELETE("/sinks/" + sink.ID).Expect().Status(http.StatusOK)
+		e.GET("/sinks/" + sink.ID).Expect().Status(http.StatusNotFound)
+		e.DELETE("/sinks/" + sink.ID).Expect().Status(http.StatusNotFound)
+	})
+}
+
+func TestSchemas(t *testing.T) {
+	singleService(t, func(url string) {
+		e := httpexpect.New(t, url)
+
+		schema := map[string]interface{}{
+			"id":                  "orders",
+			"event_type_patterns": []string{"acme.orders.*"},
+			"schema": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"order_id"},
+				"properties": map[string]interface{}{
+					"order_id": map[string]interface{}{"type": "string"},
+				},
+			},
+		}
+
+		e.POST("/schemas").WithJSON(schema).Expect().Status(http.StatusOK)
+		e.POST("/schemas").WithJSON(schema).Expect().Status(http.StatusNotAcceptable)
+		e.POST("/schemas").WithJSON(map[string]interface{}{"id": "bad", "event_type_patterns": []string{"acme.*"}, "schema": map[string]interface{}{"type": 42}}).
+			Expect().Status(http.StatusNotAcceptable)
+		e.GET("/schemas/orders").Expect().Status(http.StatusOK).JSON().Object().Value("event_type_patterns").Array().Equal([]string{"acme.orders.*"})
+		e.GET("/schemas").Expect().Status(http.StatusOK).JSON().Array().Length().Equal(1)
+
+		valid := events.Event{EventType: "acme.orders.created", EventID: "1", Source: "test", CloudEventsVersion: "0.1", Data: map[string]interface{}{"order_id": "o-1"}}
+		invalid := events.Event{EventType: "acme.orders.created", EventID: "2", Source: "test", CloudEventsVersion: "0.1", Data: map[string]interface{}{"order_id": 1}}
+		other := events.Event{EventType: "acme.payments.created", EventID: "3", Source: "test", CloudEventsVersion: "0.1"}
+
+		e.POST("/event").WithJSON(valid).Expect().Status(http.StatusOK)
+		e.POST("/event").WithJSON(other).Expect().Status(http.StatusOK)
+		e.POST("/event").WithJSON(invalid).Expect().Status(http.StatusBadRequest).Body().Contains("order_id")
+		e.POST("/events/batch").WithJSON([]events.Event{valid, invalid}).Expect().Status(http.StatusBadRequest)
+
+		// the schemaURL selects the schema regardless of the event type
+		schema["url"] = "https://schemas.acme.com/orders.json"
+		e.PUT("/schemas").WithJSON(schema).Expect().Status(http.StatusOK)
+		other.SchemaURL = "https://schemas.acme.com/orders.json"
+		e.POST("/event").WithJSON(other).Expect().Status(http.StatusBadRequest)
+
+		e.DELETE("/schemas/orders").Expect().Status(http.StatusOK)
+		e.GET("/schemas/orders").Expect().Status(http.StatusNotFound)
+		e.POST("/event").WithJSON(invalid).Expect().Status(http.StatusOK)
+
+		// a remote $ref which can't be loaded any more is a server fault, the producer retries the event
+		remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"type": "object"}`))
+		}))
+		e.POST("/schemas").WithJSON(map[string]interface{}{
+			"id":                  "remote",
+			"event_type_patterns": []string{"acme.orders.*"},
+			"schema":              map[string]interface{}{"$ref": remote.URL + "/orders.json"},
+		}).Expect().Status(http.StatusOK)
+		remote.Close()
+		e.POST("/event").WithJSON(valid).Expect().Status(http.StatusServiceUnavailable)
+	})
+}
 
-		require.True(t, eventBody.EventType == fmt.Sprintf("azure.%s", testAzureAlert.Data.Context.Activity.ResourceID))
+func TestEnrichment(t *testing.T) {
+	singleService(t, func(url string) {
+		e := httpexpect.New(t, url)
+
+		e.POST("/enrichment/tables/hosts").WithQuery("key", "host").WithHeader("Content-Type", "text/csv").
+			WithText("host,team\nweb-1,checkout\n").Expect().Status(http.StatusOK)
+		e.POST("/enrichment/tables/hosts").WithHeader("Content-Type", "text/csv").
+			WithText("host,team\nweb-1,checkout\n").Expect().Status(http.StatusNotAcceptable)
+		e.POST("/enrichment/tables/dcs").WithQuery("key", "network").
+			WithJSON([]map[string]string{{"network": "10.1.0.0/16", "dc": "eu-west"}}).Expect().Status(http.StatusOK)
+		e.POST("/enrichment/tables/bad").WithHeader("Content-Type", "text/csv").
+			WithText("host,team\nweb-1\n").Expect().Status(http.StatusNotAcceptable)
+
+		e.GET("/enrichment/tables").Expect().Status(http.StatusOK).JSON().Array().Equal([]string{"dcs", "hosts"})
+		e.GET("/enrichment/tables/hosts").Expect().Status(http.StatusOK).JSON().Object().
+			Value("rows").Object().Value("web-1").Object().Value("team").Equal("checkout")
+
+		// steps must reference existing tables
+		e.PUT("/enrichment/steps").WithJSON(map[string]interface{}{"steps": []map[string]string{{"table": "services", "key": "data.service"}}}).
+			Expect().Status(http.StatusNotAcceptable)
+
+		steps := map[string]interface{}{"steps": []map[string]string{
+			{"table": "hosts", "key": "data.host"},
+			{"table": "dcs", "key": "data.ip", "match": "cidr"},
+		}}
+		e.PUT("/enrichment/steps").WithJSON(steps).Expect().Status(http.StatusOK)
+		e.GET("/enrichment/steps").Expect().Status(http.StatusOK).JSON().Object().Value("steps").Array().Length().Equal(2)
+
+		event := events.Event{EventType: "acme.prod.disk_full", EventID: "1", Source: "test", CloudEventsVersion: "0.1",
+			Data: map[string]interface{}{"host": "web-1", "ip": "10.1.2.3"}}
+		e.POST("/event").WithJSON(event).Expect().Status(http.StatusOK)
+
+		e.PUT("/enrichment/tables/hosts").WithQuery("key", "host").WithHeader("Content-Type", "text/csv").
+			WithText("host,team\nweb-1,storage\n").Expect().Status(http.StatusOK)
+		e.GET("/enrichment/tables/hosts").Expect().Status(http.StatusOK).JSON().Object().
+			Value("rows").Object().Value("web-1").Object().Value("team").Equal("storage")
+
+		// tables used by a step can't be removed
+		e.DELETE("/enrichment/tables/hosts").Expect().Status(http.StatusNotAcceptable)
+		e.PUT("/enrichment/steps").WithJSON(map[string]interface{}{"steps": []interface{}{}}).Expect().Status(http.StatusOK)
+		e.DELETE("/enrichment/tables/hosts").Expect().Status(http.StatusOK)
+		e.DELETE("/enrichment/tables/hosts").Expect().Status(http.StatusNotFound)
 	})
-}
\ No newline at end of file
+}
+
+func TestDecodeEvents(t *testing.T) {
+	array := []byte(`[{"eventType": "acme.a", "eventID": "1"}, {"eventType": "acme.b", "eventID": "2"}]`)
+	batch, err := decodeEvents(array)
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	require.Equal(t, "acme.b", batch[1].EventType)
+
+	ndjson := []byte("{\"eventType\": \"acme.a\", \"eventID\": \"1\"}\n{\"eventType\": \"acme.b\", \"eventID\": \"2\"}\n")
+	batch, err = decodeEvents(ndjson)
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	require.Equal(t, "2", batch[1].EventID)
+
+	_, err = decodeEvents([]byte("{\"eventType\": \"acme.a\"}\n{"))
+	require.Error(t, err)
+
+	_, err = decodeEvents([]byte(`[null]`))
+	require.Error(t, err)
+
+	_, err = decodeEvents([]byte(`[{"eventType": "acme.a", "eventID": "1"}, null]`))
+	require.Error(t, err)
+
+	_, err = decodeEvents([]byte("{\"eventType\": \"acme.a\"}\nnull\n"))
+	require.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package store
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/golang/glog"
 	"github.com/myntra/cortex/pkg/events"
 	"github.com/myntra/cortex/pkg/rules"
@@ -11,12 +14,15 @@ type bucketStorage struct {
 	rs *ruleStorage
 }
 
-func (b *bucketStorage) stash(ruleID string, event *events.Event) error {
+func (b *bucketStorage) stash(ruleID string, event *events.Event, now time.Time) (*StashResult, error) {
 	glog.Info("stash event ==>  ", event)
 	if b.es.bucketExists(ruleID) {
-		return b.es.stash(rules.Rule{ID: ruleID}, event)
+		return b.es.stash(rules.Rule{ID: ruleID}, event, now)
 	}
 
 	rule := b.rs.getRule(ruleID)
-	return b.es.stash(*rule, event)
+	if rule == nil {
+		return nil, fmt.Errorf("rule id %v does not exist", ruleID)
+	}
+	return b.es.stash(*rule, event, now)
 }
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// checkpointStorage holds the time an input has read up to, so that a new leader resumes the input where the
+// previous one stopped
+type checkpointStorage struct {
+	mu sync.RWMutex
+	m  map[string]time.Time
+}
+
+// setCheckpoint only moves the checkpoint forward
+func (c *checkpointStorage) setCheckpoint(id string, t time.Time) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.After(c.m[id]) {
+		c.m[id] = t
+	}
+
+	return nil
+}
+
+func (c *checkpointStorage) getCheckpoint(id string) time.Time {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.m[id]
+}
+
+func (c *checkpointStorage) clone() map[string]time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m := make(map[string]time.Time)
+	for k, v := range c.m {
+		m[k] = v
+	}
+	return m
+}
+
+func (c *checkpointStorage) restore(m map[string]time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m = m
+}
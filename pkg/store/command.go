@@ -1,22 +1,44 @@
 package store
 
 import (
+	"time"
+
+	"github.com/myntra/cortex/pkg/enrichment"
 	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
 )
 
 //go:generate msgp
 
 // Command is the container for a raft command
 type Command struct {
-	Op       string             `json:"op"` // stash or evict
-	Rule     *rules.Rule        `json:"rule,omitempty"`
-	RuleID   string             `json:"ruleID,omitempty"`
-	Event    *events.Event      `json:"event,omitempty"`
-	ScriptID string             `json:"script_id,omitempty"`
-	Script   *js.Script         `json:"script,omitempty"`
-	Record   *executions.Record `json:"record,omitempty"`
-	RecordID string             `json:"record_id,omitempty"`
+	Op          string                `json:"op"` // stash, batch or evict
+	Rule        *rules.Rule           `json:"rule,omitempty"`
+	RuleID      string                `json:"ruleID,omitempty"`
+	Event       *events.Event         `json:"event,omitempty"`
+	ScriptID    string                `json:"script_id,omitempty"`
+	Script      *js.Script            `json:"script,omitempty"`
+	Record      *executions.Record    `json:"record,omitempty"`
+	RecordID    string                `json:"record_id,omitempty"`
+	Incident    *incidents.Incident   `json:"incident,omitempty"`
+	RecordIDs   []string              `json:"record_ids,omitempty"`
+	IncidentIDs []string              `json:"incident_ids,omitempty"`
+	Retention   *executions.Retention `json:"retention,omitempty"`
+	Timestamp   time.Time             `json:"timestamp,omitempty"` // stamped by the leader
+	Extension   uint64                `json:"extension,omitempty"` // milliseconds to push a bucket's flush out by
+	Commands    []Command             `json:"commands,omitempty"`  // commands of a batch, applied in order
+	SinkID      string                `json:"sink_id,omitempty"`
+	Sink        *sinks.GenericSink    `json:"sink,omitempty"`
+	SchemaID    string                `json:"schema_id,omitempty"`
+	Schema      *schemas.Schema       `json:"schema,omitempty"`
+	TableID     string                `json:"table_id,omitempty"`
+	Table       *enrichment.Table     `json:"table,omitempty"`
+	Pipeline    *enrichment.Pipeline  `json:"pipeline,omitempty"`
+	InputID     string                `json:"input_id,omitempty"`   // input of a checkpoint
+	ReadUntil   time.Time             `json:"read_until,omitempty"` // checkpoint of the input
 }
@@ -3,10 +3,14 @@ package store
 // Code generated by github.com/tinylib/msgp DO NOT EDIT.
 
 import (
+	"github.com/myntra/cortex/pkg/enrichment"
 	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
 	"github.com/tinylib/msgp/msgp"
 )
 
@@ -110,6 +114,188 @@ func (z *Command) DecodeMsg(dc *msgp.Reader) (err error) {
 			if err != nil {
 				return
 			}
+		case "Incident":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Incident = nil
+			} else {
+				if z.Incident == nil {
+					z.Incident = new(incidents.Incident)
+				}
+				err = z.Incident.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "RecordIDs":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.RecordIDs) >= int(zb0002) {
+				z.RecordIDs = (z.RecordIDs)[:zb0002]
+			} else {
+				z.RecordIDs = make([]string, zb0002)
+			}
+			for za0001 := range z.RecordIDs {
+				z.RecordIDs[za0001], err = dc.ReadString()
+				if err != nil {
+					return
+				}
+			}
+		case "IncidentIDs":
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.IncidentIDs) >= int(zb0003) {
+				z.IncidentIDs = (z.IncidentIDs)[:zb0003]
+			} else {
+				z.IncidentIDs = make([]string, zb0003)
+			}
+			for za0002 := range z.IncidentIDs {
+				z.IncidentIDs[za0002], err = dc.ReadString()
+				if err != nil {
+					return
+				}
+			}
+		case "Retention":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Retention = nil
+			} else {
+				if z.Retention == nil {
+					z.Retention = new(executions.Retention)
+				}
+				err = z.Retention.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "Timestamp":
+			z.Timestamp, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
+		case "Extension":
+			z.Extension, err = dc.ReadUint64()
+			if err != nil {
+				return
+			}
+		case "Commands":
+			var zb0004 uint32
+			zb0004, err = dc.ReadArrayHeader()
+			if err != nil {
+				return
+			}
+			if cap(z.Commands) >= int(zb0004) {
+				z.Commands = (z.Commands)[:zb0004]
+			} else {
+				z.Commands = make([]Command, zb0004)
+			}
+			for za0003 := range z.Commands {
+				err = z.Commands[za0003].DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "SinkID":
+			z.SinkID, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Sink":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Sink = nil
+			} else {
+				if z.Sink == nil {
+					z.Sink = new(sinks.GenericSink)
+				}
+				err = z.Sink.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "SchemaID":
+			z.SchemaID, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Schema":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Schema = nil
+			} else {
+				if z.Schema == nil {
+					z.Schema = new(schemas.Schema)
+				}
+				err = z.Schema.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "TableID":
+			z.TableID, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "Table":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Table = nil
+			} else {
+				if z.Table == nil {
+					z.Table = new(enrichment.Table)
+				}
+				err = z.Table.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "Pipeline":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					return
+				}
+				z.Pipeline = nil
+			} else {
+				if z.Pipeline == nil {
+					z.Pipeline = new(enrichment.Pipeline)
+				}
+				err = z.Pipeline.DecodeMsg(dc)
+				if err != nil {
+					return
+				}
+			}
+		case "InputID":
+			z.InputID, err = dc.ReadString()
+			if err != nil {
+				return
+			}
+		case "ReadUntil":
+			z.ReadUntil, err = dc.ReadTime()
+			if err != nil {
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -122,9 +308,9 @@ func (z *Command) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *Command) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 8
+	// map header, size 24
 	// write "Op"
-	err = en.Append(0x88, 0xa2, 0x4f, 0x70)
+	err = en.Append(0xde, 0x0, 0x18, 0xa2, 0x4f, 0x70)
 	if err != nil {
 		return
 	}
@@ -223,15 +409,219 @@ func (z *Command) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
+	// write "Incident"
+	err = en.Append(0xa8, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74)
+	if err != nil {
+		return
+	}
+	if z.Incident == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Incident.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "RecordIDs"
+	err = en.Append(0xa9, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.RecordIDs)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.RecordIDs {
+		err = en.WriteString(z.RecordIDs[za0001])
+		if err != nil {
+			return
+		}
+	}
+	// write "IncidentIDs"
+	err = en.Append(0xab, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.IncidentIDs)))
+	if err != nil {
+		return
+	}
+	for za0002 := range z.IncidentIDs {
+		err = en.WriteString(z.IncidentIDs[za0002])
+		if err != nil {
+			return
+		}
+	}
+	// write "Retention"
+	err = en.Append(0xa9, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e)
+	if err != nil {
+		return
+	}
+	if z.Retention == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Retention.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "Timestamp"
+	err = en.Append(0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.Timestamp)
+	if err != nil {
+		return
+	}
+	// write "Extension"
+	err = en.Append(0xa9, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Extension)
+	if err != nil {
+		return
+	}
+	// write "Commands"
+	err = en.Append(0xa8, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Commands)))
+	if err != nil {
+		return
+	}
+	for za0003 := range z.Commands {
+		err = z.Commands[za0003].EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "SinkID"
+	err = en.Append(0xa6, 0x53, 0x69, 0x6e, 0x6b, 0x49, 0x44)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.SinkID)
+	if err != nil {
+		return
+	}
+	// write "Sink"
+	err = en.Append(0xa4, 0x53, 0x69, 0x6e, 0x6b)
+	if err != nil {
+		return
+	}
+	if z.Sink == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Sink.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "SchemaID"
+	err = en.Append(0xa8, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x49, 0x44)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.SchemaID)
+	if err != nil {
+		return
+	}
+	// write "Schema"
+	err = en.Append(0xa6, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61)
+	if err != nil {
+		return
+	}
+	if z.Schema == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Schema.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "TableID"
+	err = en.Append(0xa7, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x44)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.TableID)
+	if err != nil {
+		return
+	}
+	// write "Table"
+	err = en.Append(0xa5, 0x54, 0x61, 0x62, 0x6c, 0x65)
+	if err != nil {
+		return
+	}
+	if z.Table == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Table.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "Pipeline"
+	err = en.Append(0xa8, 0x50, 0x69, 0x70, 0x65, 0x6c, 0x69, 0x6e, 0x65)
+	if err != nil {
+		return
+	}
+	if z.Pipeline == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Pipeline.EncodeMsg(en)
+		if err != nil {
+			return
+		}
+	}
+	// write "InputID"
+	err = en.Append(0xa7, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x49, 0x44)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.InputID)
+	if err != nil {
+		return
+	}
+	// write "ReadUntil"
+	err = en.Append(0xa9, 0x52, 0x65, 0x61, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c)
+	if err != nil {
+		return
+	}
+	err = en.WriteTime(z.ReadUntil)
+	if err != nil {
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z *Command) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 8
+	// map header, size 24
 	// string "Op"
-	o = append(o, 0x88, 0xa2, 0x4f, 0x70)
+	o = append(o, 0xde, 0x0, 0x18, 0xa2, 0x4f, 0x70)
 	o = msgp.AppendString(o, z.Op)
 	// string "Rule"
 	o = append(o, 0xa4, 0x52, 0x75, 0x6c, 0x65)
@@ -282,6 +672,108 @@ func (z *Command) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "RecordID"
 	o = append(o, 0xa8, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44)
 	o = msgp.AppendString(o, z.RecordID)
+	// string "Incident"
+	o = append(o, 0xa8, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74)
+	if z.Incident == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Incident.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "RecordIDs"
+	o = append(o, 0xa9, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.RecordIDs)))
+	for za0001 := range z.RecordIDs {
+		o = msgp.AppendString(o, z.RecordIDs[za0001])
+	}
+	// string "IncidentIDs"
+	o = append(o, 0xab, 0x49, 0x6e, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.IncidentIDs)))
+	for za0002 := range z.IncidentIDs {
+		o = msgp.AppendString(o, z.IncidentIDs[za0002])
+	}
+	// string "Retention"
+	o = append(o, 0xa9, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e)
+	if z.Retention == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Retention.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "Timestamp"
+	o = append(o, 0xa9, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70)
+	o = msgp.AppendTime(o, z.Timestamp)
+	// string "Extension"
+	o = append(o, 0xa9, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e)
+	o = msgp.AppendUint64(o, z.Extension)
+	// string "Commands"
+	o = append(o, 0xa8, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Commands)))
+	for za0003 := range z.Commands {
+		o, err = z.Commands[za0003].MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "SinkID"
+	o = append(o, 0xa6, 0x53, 0x69, 0x6e, 0x6b, 0x49, 0x44)
+	o = msgp.AppendString(o, z.SinkID)
+	// string "Sink"
+	o = append(o, 0xa4, 0x53, 0x69, 0x6e, 0x6b)
+	if z.Sink == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Sink.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "SchemaID"
+	o = append(o, 0xa8, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x49, 0x44)
+	o = msgp.AppendString(o, z.SchemaID)
+	// string "Schema"
+	o = append(o, 0xa6, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61)
+	if z.Schema == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Schema.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "TableID"
+	o = append(o, 0xa7, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x49, 0x44)
+	o = msgp.AppendString(o, z.TableID)
+	// string "Table"
+	o = append(o, 0xa5, 0x54, 0x61, 0x62, 0x6c, 0x65)
+	if z.Table == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Table.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "Pipeline"
+	o = append(o, 0xa8, 0x50, 0x69, 0x70, 0x65, 0x6c, 0x69, 0x6e, 0x65)
+	if z.Pipeline == nil {
+		o = msgp.AppendNil(o)
+	} else {
+		o, err = z.Pipeline.MarshalMsg(o)
+		if err != nil {
+			return
+		}
+	}
+	// string "InputID"
+	o = append(o, 0xa7, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x49, 0x44)
+	o = msgp.AppendString(o, z.InputID)
+	// string "ReadUntil"
+	o = append(o, 0xa9, 0x52, 0x65, 0x61, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c)
+	o = msgp.AppendTime(o, z.ReadUntil)
 	return
 }
 
@@ -385,6 +877,188 @@ func (z *Command) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			if err != nil {
 				return
 			}
+		case "Incident":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Incident = nil
+			} else {
+				if z.Incident == nil {
+					z.Incident = new(incidents.Incident)
+				}
+				bts, err = z.Incident.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "RecordIDs":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.RecordIDs) >= int(zb0002) {
+				z.RecordIDs = (z.RecordIDs)[:zb0002]
+			} else {
+				z.RecordIDs = make([]string, zb0002)
+			}
+			for za0001 := range z.RecordIDs {
+				z.RecordIDs[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "IncidentIDs":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.IncidentIDs) >= int(zb0003) {
+				z.IncidentIDs = (z.IncidentIDs)[:zb0003]
+			} else {
+				z.IncidentIDs = make([]string, zb0003)
+			}
+			for za0002 := range z.IncidentIDs {
+				z.IncidentIDs[za0002], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "Retention":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Retention = nil
+			} else {
+				if z.Retention == nil {
+					z.Retention = new(executions.Retention)
+				}
+				bts, err = z.Retention.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "Timestamp":
+			z.Timestamp, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Extension":
+			z.Extension, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				return
+			}
+		case "Commands":
+			var zb0004 uint32
+			zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.Commands) >= int(zb0004) {
+				z.Commands = (z.Commands)[:zb0004]
+			} else {
+				z.Commands = make([]Command, zb0004)
+			}
+			for za0003 := range z.Commands {
+				bts, err = z.Commands[za0003].UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "SinkID":
+			z.SinkID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Sink":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Sink = nil
+			} else {
+				if z.Sink == nil {
+					z.Sink = new(sinks.GenericSink)
+				}
+				bts, err = z.Sink.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "SchemaID":
+			z.SchemaID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Schema":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Schema = nil
+			} else {
+				if z.Schema == nil {
+					z.Schema = new(schemas.Schema)
+				}
+				bts, err = z.Schema.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "TableID":
+			z.TableID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "Table":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Table = nil
+			} else {
+				if z.Table == nil {
+					z.Table = new(enrichment.Table)
+				}
+				bts, err = z.Table.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "Pipeline":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Pipeline = nil
+			} else {
+				if z.Pipeline == nil {
+					z.Pipeline = new(enrichment.Pipeline)
+				}
+				bts, err = z.Pipeline.UnmarshalMsg(bts)
+				if err != nil {
+					return
+				}
+			}
+		case "InputID":
+			z.InputID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		case "ReadUntil":
+			z.ReadUntil, bts, err = msgp.ReadTimeBytes(bts)
+			if err != nil {
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -398,7 +1072,7 @@ func (z *Command) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *Command) Msgsize() (s int) {
-	s = 1 + 3 + msgp.StringPrefixSize + len(z.Op) + 5
+	s = 3 + 3 + msgp.StringPrefixSize + len(z.Op) + 5
 	if z.Rule == nil {
 		s += msgp.NilSize
 	} else {
@@ -422,6 +1096,54 @@ func (z *Command) Msgsize() (s int) {
 	} else {
 		s += z.Record.Msgsize()
 	}
-	s += 9 + msgp.StringPrefixSize + len(z.RecordID)
+	s += 9 + msgp.StringPrefixSize + len(z.RecordID) + 9
+	if z.Incident == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Incident.Msgsize()
+	}
+	s += 10 + msgp.ArrayHeaderSize
+	for za0001 := range z.RecordIDs {
+		s += msgp.StringPrefixSize + len(z.RecordIDs[za0001])
+	}
+	s += 12 + msgp.ArrayHeaderSize
+	for za0002 := range z.IncidentIDs {
+		s += msgp.StringPrefixSize + len(z.IncidentIDs[za0002])
+	}
+	s += 10
+	if z.Retention == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Retention.Msgsize()
+	}
+	s += 10 + msgp.TimeSize + 10 + msgp.Uint64Size + 9 + msgp.ArrayHeaderSize
+	for za0003 := range z.Commands {
+		s += z.Commands[za0003].Msgsize()
+	}
+	s += 7 + msgp.StringPrefixSize + len(z.SinkID) + 5
+	if z.Sink == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Sink.Msgsize()
+	}
+	s += 9 + msgp.StringPrefixSize + len(z.SchemaID) + 7
+	if z.Schema == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Schema.Msgsize()
+	}
+	s += 8 + msgp.StringPrefixSize + len(z.TableID) + 6
+	if z.Table == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Table.Msgsize()
+	}
+	s += 9
+	if z.Pipeline == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Pipeline.Msgsize()
+	}
+	s += 8 + msgp.StringPrefixSize + len(z.InputID) + 10 + msgp.TimeSize
 	return
 }
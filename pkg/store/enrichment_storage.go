@@ -0,0 +1,152 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/myntra/cortex/pkg/enrichment"
+	"github.com/myntra/cortex/pkg/events"
+)
+
+type enrichmentStorage struct {
+	mu       sync.RWMutex
+	m        map[string]*enrichment.Table
+	pipeline *enrichment.Pipeline
+}
+
+func (e *enrichmentStorage) addTable(table *enrichment.Table) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.m[table.ID]; ok {
+		return fmt.Errorf("table id already exists. table id must be unique")
+	}
+
+	e.m[table.ID] = table
+	return nil
+}
+
+func (e *enrichmentStorage) updateTable(table *enrichment.Table) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.m[table.ID]; !ok {
+		return fmt.Errorf("table id not found. can't update")
+	}
+
+	e.m[table.ID] = table
+	return nil
+}
+
+func (e *enrichmentStorage) removeTable(id string) error {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.m[id]; !ok {
+		return fmt.Errorf("table id not found. can't remove")
+	}
+
+	if e.pipeline != nil {
+		for i, step := range e.pipeline.Steps {
+			if step.Table == id {
+				return fmt.Errorf("table %s is used by enrichment step %d. can't remove", id, i)
+			}
+		}
+	}
+
+	delete(e.m, id)
+	return nil
+}
+
+func (e *enrichmentStorage) setPipeline(pipeline *enrichment.Pipeline) error {
+	if err := pipeline.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, step := range pipeline.Steps {
+		if _, ok := e.m[step.Table]; !ok {
+			return fmt.Errorf("step %d: table %s not found", i, step.Table)
+		}
+	}
+
+	e.pipeline = pipeline
+	return nil
+}
+
+func (e *enrichmentStorage) getTable(id string) *enrichment.Table {
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.m[id]
+}
+
+func (e *enrichmentStorage) getTables() []*enrichment.Table {
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	list := make([]*enrichment.Table, 0, len(e.m))
+	for _, table := range e.m {
+		list = append(list, table)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	return list
+}
+
+func (e *enrichmentStorage) getPipeline() *enrichment.Pipeline {
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.pipeline == nil {
+		return &enrichment.Pipeline{Steps: []enrichment.Step{}}
+	}
+
+	return e.pipeline
+}
+
+// enrich the event with the steps of the pipeline
+func (e *enrichmentStorage) enrich(event *events.Event) {
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.pipeline == nil {
+		return
+	}
+
+	e.pipeline.Enrich(event, e.m)
+}
+
+func (e *enrichmentStorage) clone() (map[string]*enrichment.Table, *enrichment.Pipeline) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	m := make(map[string]*enrichment.Table)
+	for k, v := range e.m {
+		m[k] = v
+	}
+	return m, e.pipeline
+}
+
+func (e *enrichmentStorage) restore(m map[string]*enrichment.Table, pipeline *enrichment.Pipeline) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.m = m
+	e.pipeline = pipeline
+}
@@ -4,29 +4,67 @@ import (
 	"bytes"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/myntra/cortex/pkg/events"
 	"github.com/myntra/cortex/pkg/rules"
 )
 
+// BucketStatus describes an open bucket
+type BucketStatus struct {
+	RuleID     string    `json:"rule_id"`
+	WindowType string    `json:"window_type"`
+	Events     int       `json:"events"`
+	LateEvents int       `json:"late_events"`
+	FlushLock  bool      `json:"flush_lock"` // true if the bucket is being flushed
+	Extension  uint64    `json:"extension"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	FlushAt    time.Time `json:"flush_at"`
+	CanFlushIn int64     `json:"can_flush_in"` // milliseconds left to flush, negative if overdue
+}
+
+func newBucketStatus(bucket *events.Bucket, now time.Time) *BucketStatus {
+	flushAt := bucket.FlushAt()
+	return &BucketStatus{
+		RuleID:     bucket.Rule.ID,
+		WindowType: bucket.Rule.GetWindowType(),
+		Events:     len(bucket.Events),
+		LateEvents: bucket.LateEvents,
+		FlushLock:  bucket.FlushLock,
+		Extension:  bucket.Extension,
+		CreatedAt:  bucket.CreatedAt,
+		UpdatedAt:  bucket.UpdatedAt,
+		FlushAt:    flushAt,
+		CanFlushIn: int64(flushAt.Sub(now) / time.Millisecond),
+	}
+}
+
 type eventStorage struct {
-	mu sync.RWMutex
-	m  map[string]*events.Bucket // [ruleID]
+	mu   sync.RWMutex
+	m    map[string]*events.Bucket // [ruleID]
+	late map[string]int            // [ruleID] late events dropped while the rule had no open bucket
 }
 
-func (e *eventStorage) stash(rule rules.Rule, event *events.Event) error {
+// stash adds the event to the rule's bucket at the time stamped by the leader
+func (e *eventStorage) stash(rule rules.Rule, event *events.Event, now time.Time) (*StashResult, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	glog.Infof("stash event ==>  %+v", event)
 	ruleID := rule.ID
 	if _, ok := e.m[ruleID]; !ok {
-		bucket := events.NewBucket(rule)
-		bucket.Events = append(bucket.Events, event)
+		bucket := events.NewBucketAt(rule, now)
+		bucket.AddEventAt(event, now)
+		// the dropped late events are counted in the rule's next record
+		bucket.LateEvents = e.late[ruleID]
+		delete(e.late, ruleID)
 		e.m[ruleID] = bucket
-		return nil
+		return &StashResult{RuleID: ruleID, Joined: true, NewBucket: true, BucketCreatedAt: bucket.CreatedAt}, nil
 	}
 
+	result := &StashResult{RuleID: ruleID, BucketCreatedAt: e.m[ruleID].CreatedAt}
+
 	// dedup, reschedule flusher(sliding wait window), frequency count
 	dup := false
 	for _, existingEvent := range e.m[ruleID].Events {
@@ -40,12 +78,64 @@ func (e *eventStorage) stash(rule rules.Rule, event *events.Event) error {
 	}
 	// is a duplicate event, skip appending event to bucket
 	if dup {
-		return nil
+		result.Deduplicated = true
+		return result, nil
 	}
 	// update event
-	e.m[ruleID].AddEvent(event)
+	e.m[ruleID].AddEventAt(event, now)
+	result.Joined = true
 
-	return nil
+	return result, nil
+}
+
+// stashLate adds a late event to the rule's open bucket, or a new bucket, and counts it as late
+func (e *eventStorage) stashLate(rule rules.Rule, event *events.Event, now time.Time) (*StashResult, error) {
+	result, err := e.stash(rule, event, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Late = true
+	if result.Joined {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.m[rule.ID].LateEvents++
+	}
+	return result, nil
+}
+
+// dropLate counts a dropped late event in the rule's open bucket, else in the rule's next bucket
+func (e *eventStorage) dropLate(ruleID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if bucket, ok := e.m[ruleID]; ok {
+		bucket.LateEvents++
+		return
+	}
+
+	if e.late == nil {
+		e.late = make(map[string]int)
+	}
+	e.late[ruleID]++
+}
+
+// isLate returns true if the event time is behind the watermark, i.e the stash time less the rule's allowed lateness,
+// and is not within the open bucket's window
+func (e *eventStorage) isLate(rule rules.Rule, eventTime, now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	watermark := now.Add(-time.Millisecond * time.Duration(rule.AllowedLateness))
+	if !eventTime.Before(watermark) {
+		return false
+	}
+
+	if bucket, ok := e.m[rule.ID]; ok && !eventTime.Before(bucket.CreatedAt) {
+		return false
+	}
+
+	return true
 }
 
 func (e *eventStorage) flushLock(ruleID string) error {
@@ -64,7 +154,58 @@ func (e *eventStorage) flushLock(ruleID string) error {
 	return nil
 }
 
-func (e *eventStorage) flushBucket(ruleID string) error {
+// flushBucket removes the bucket, or moves tumbling and hopping buckets to their next window. returns the flushed
+// window to execute. the next window picks up the rule, which a flush update policy held back until the flush
+func (e *eventStorage) flushBucket(ruleID string, rule *rules.Rule) (*events.Bucket, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bucket, ok := e.m[ruleID]
+	if !ok {
+		return nil, fmt.Errorf("bucket with id %v not found", ruleID)
+	}
+
+	window := bucket.Window()
+	if bucket.Advance() {
+		if rule != nil {
+			bucket.Rule = *rule
+		}
+		return window, nil
+	}
+
+	delete(e.m, ruleID)
+	return window, nil
+}
+
+// updateRule applies the rule's update policy to its open bucket. the bucket of a disabled rule is discarded.
+func (e *eventStorage) updateRule(rule *rules.Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bucket, ok := e.m[rule.ID]
+	if !ok {
+		return
+	}
+
+	if rule.Disabled {
+		glog.Infof("rule %v disabled, discard bucket", rule.ID)
+		delete(e.m, rule.ID)
+		return
+	}
+
+	glog.Infof("rule %v updated, %v bucket", rule.ID, rule.GetUpdatePolicy())
+	switch rule.GetUpdatePolicy() {
+	case rules.UpdatePolicyFlush:
+		// the bucket flushes with the old rule, flushBucket rebinds the next window to the updated rule
+		bucket.FlushLock = true
+	case rules.UpdatePolicyDiscard:
+		delete(e.m, rule.ID)
+	default:
+		bucket.Rule = *rule
+	}
+}
+
+func (e *eventStorage) discardBucket(ruleID string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -76,6 +217,56 @@ func (e *eventStorage) flushBucket(ruleID string) error {
 	return nil
 }
 
+// extendBucket pushes the bucket's flush out by extension ms
+func (e *eventStorage) extendBucket(ruleID string, extension uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bucket, ok := e.m[ruleID]
+	if !ok {
+		return fmt.Errorf("bucket with id %v not found", ruleID)
+	}
+
+	if bucket.FlushLock {
+		return fmt.Errorf("bucket with id %v is being flushed", ruleID)
+	}
+
+	bucket.Extension += extension
+	return nil
+}
+
+// buckets returns the status of every open bucket
+func (e *eventStorage) buckets(now time.Time) []*BucketStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	list := make([]*BucketStatus, 0)
+	for _, bucket := range e.m {
+		list = append(list, newBucketStatus(bucket, now))
+	}
+	return list
+}
+
+func (e *eventStorage) bucketStatus(ruleID string, now time.Time) *BucketStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bucket, ok := e.m[ruleID]
+	if !ok {
+		return nil
+	}
+	return newBucketStatus(bucket, now)
+}
+
+// removeBucket discards the rule's open bucket, if any, and its dropped late events
+func (e *eventStorage) removeBucket(ruleID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.m, ruleID)
+	delete(e.late, ruleID)
+}
+
 func (e *eventStorage) bucketExists(ruleID string) bool {
 	_, ok := e.m[ruleID]
 	return ok
@@ -102,6 +293,31 @@ func (e *eventStorage) clone() map[string]*events.Bucket {
 	return clone
 }
 
-func (e *eventStorage) restore(m map[string]*events.Bucket) {
+// snapshot returns a copy of every bucket along with its flush timing state
+func (e *eventStorage) snapshot() map[string]*events.BucketSnapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	snapshot := make(map[string]*events.BucketSnapshot)
+	for k, v := range e.m {
+		snapshot[k] = v.Snapshot()
+	}
+	return snapshot
+}
+
+// lateSnapshot returns a copy of the dropped late events counts
+func (e *eventStorage) lateSnapshot() map[string]int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	late := make(map[string]int)
+	for k, v := range e.late {
+		late[k] = v
+	}
+	return late
+}
+
+func (e *eventStorage) restore(m map[string]*events.Bucket, late map[string]int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.m = m
+	e.late = late
 }
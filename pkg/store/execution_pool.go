@@ -0,0 +1,231 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/events"
+)
+
+// ExecutionPoolStats is a snapshot of the execution worker pool
+type ExecutionPoolStats struct {
+	Workers       int            `json:"workers"`
+	QueueCapacity int            `json:"queue_capacity"`
+	Queued        int            `json:"queued"`
+	Running       int            `json:"running"`
+	Executed      uint64         `json:"executed"`
+	Deferred      uint64         `json:"deferred"`     // flushes deferred to the next tick since the queue was full
+	Backpressure  bool           `json:"backpressure"` // true if the queue is full
+	QueuedByRule  map[string]int `json:"queued_by_rule"`
+	RunningByRule map[string]int `json:"running_by_rule"`
+}
+
+type queuedBucket struct {
+	bucket *events.Bucket
+	seq    uint64
+}
+
+// executionPool runs flushed buckets on a fixed number of workers. buckets of higher priority rules
+// are picked first and a rule never runs more than its max_concurrency buckets at a time.
+type executionPool struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	queue    []*queuedBucket
+	running  map[string]int // [ruleID]
+	workers  int
+	capacity int
+	reserved int // slots held for flushed buckets
+	seq      uint64
+	executed uint64
+	deferred uint64
+	quit     bool
+	wg       sync.WaitGroup
+	execute  func(rb *events.Bucket)
+}
+
+func newExecutionPool(workers, capacity int, execute func(rb *events.Bucket)) *executionPool {
+	p := &executionPool{
+		running:  make(map[string]int),
+		workers:  workers,
+		capacity: capacity,
+		execute:  execute,
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+func (p *executionPool) start() {
+	p.mu.Lock()
+	p.quit = false
+	p.mu.Unlock()
+
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+}
+
+// stop waits for the running executions to finish. queued buckets are kept for the next start.
+func (p *executionPool) stop() {
+	p.mu.Lock()
+	p.quit = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func (p *executionPool) worker() {
+	defer p.wg.Done()
+	for {
+		rb := p.next()
+		if rb == nil {
+			return
+		}
+
+		p.execute(rb)
+		p.done(rb.Rule.ID)
+	}
+}
+
+// full returns true if the queued and the reserved buckets take the whole capacity. expects the lock to be held.
+func (p *executionPool) full() bool {
+	return len(p.queue)+p.reserved >= p.capacity
+}
+
+// reserve holds a queue slot for a bucket queued later with pushReserved. returns false and records the deferred
+// flush if the queue is full
+func (p *executionPool) reserve() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.full() {
+		p.deferred++
+		return false
+	}
+
+	p.reserved++
+	return true
+}
+
+// release frees a slot held by reserve
+func (p *executionPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reserved--
+}
+
+// push queues the bucket for execution. returns false if the queue is full
+func (p *executionPool) push(rb *events.Bucket) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.full() {
+		p.deferred++
+		glog.Errorf("execution queue is full, deferring bucket %v", rb.Rule.ID)
+		return false
+	}
+
+	p.enqueue(rb)
+	return true
+}
+
+// pushReserved queues the bucket in a slot held by reserve
+func (p *executionPool) pushReserved(rb *events.Bucket) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reserved--
+	p.enqueue(rb)
+}
+
+// enqueue expects the lock to be held
+func (p *executionPool) enqueue(rb *events.Bucket) {
+	p.seq++
+	p.queue = append(p.queue, &queuedBucket{bucket: rb, seq: p.seq})
+	p.cond.Signal()
+}
+
+// next blocks until a runnable bucket is available. returns nil if the pool is stopped
+func (p *executionPool) next() *events.Bucket {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if p.quit {
+			return nil
+		}
+
+		if i := p.runnable(); i >= 0 {
+			qb := p.queue[i]
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			p.running[qb.bucket.Rule.ID]++
+			return qb.bucket
+		}
+
+		p.cond.Wait()
+	}
+}
+
+// runnable returns the index of the highest priority, oldest bucket whose rule is within its concurrency limit.
+// expects the lock to be held.
+func (p *executionPool) runnable() int {
+	best := -1
+	for i, qb := range p.queue {
+		rule := qb.bucket.Rule
+		if rule.MaxConcurrency > 0 && p.running[rule.ID] >= rule.MaxConcurrency {
+			continue
+		}
+
+		if best == -1 {
+			best = i
+			continue
+		}
+
+		current := p.queue[best]
+		if rule.Priority > current.bucket.Rule.Priority ||
+			(rule.Priority == current.bucket.Rule.Priority && qb.seq < current.seq) {
+			best = i
+		}
+	}
+	return best
+}
+
+func (p *executionPool) done(ruleID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running[ruleID]--
+	if p.running[ruleID] <= 0 {
+		delete(p.running, ruleID)
+	}
+	p.executed++
+	// a rule at its concurrency limit may be runnable again
+	p.cond.Broadcast()
+}
+
+func (p *executionPool) stats() *ExecutionPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := &ExecutionPoolStats{
+		Workers:       p.workers,
+		QueueCapacity: p.capacity,
+		Queued:        len(p.queue),
+		Executed:      p.executed,
+		Deferred:      p.deferred,
+		Backpressure:  p.full(),
+		QueuedByRule:  make(map[string]int),
+		RunningByRule: make(map[string]int),
+	}
+
+	for _, qb := range p.queue {
+		stats.QueuedByRule[qb.bucket.Rule.ID]++
+	}
+
+	for ruleID, n := range p.running {
+		stats.Running += n
+		stats.RunningByRule[ruleID] = n
+	}
+
+	return stats
+}
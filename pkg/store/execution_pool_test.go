@@ -0,0 +1,172 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/require"
+
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/rules"
+)
+
+func newPoolTestBucket(ruleID string, priority, maxConcurrency int) *events.Bucket {
+	return events.NewBucket(rules.Rule{ID: ruleID, Priority: priority, MaxConcurrency: maxConcurrency})
+}
+
+func TestExecutionPoolPriority(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+
+	pool := newExecutionPool(1, 10, func(rb *events.Bucket) {
+		if rb.Rule.ID == "blocker" {
+			<-release
+		}
+		mu.Lock()
+		order = append(order, rb.Rule.ID)
+		mu.Unlock()
+	})
+	pool.start()
+	defer pool.stop()
+
+	// occupy the only worker so the rest are queued
+	require.True(t, pool.push(newPoolTestBucket("blocker", 0, 0)))
+	time.Sleep(time.Millisecond * 100)
+
+	require.True(t, pool.push(newPoolTestBucket("low", 0, 0)))
+	require.True(t, pool.push(newPoolTestBucket("high", 10, 0)))
+	require.True(t, pool.push(newPoolTestBucket("low2", 0, 0)))
+
+	stats := pool.stats()
+	require.Equal(t, 3, stats.Queued)
+	require.Equal(t, 1, stats.Running)
+	require.Equal(t, 1, stats.RunningByRule["blocker"])
+
+	close(release)
+
+	err := backoff.Retry(func() error {
+		if pool.stats().Executed != 4 {
+			return fmt.Errorf("executions pending")
+		}
+		return nil
+	}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Millisecond*10), 100))
+	require.NoError(t, err)
+	require.Equal(t, []string{"blocker", "high", "low", "low2"}, order)
+}
+
+func TestExecutionPoolMaxConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+
+	pool := newExecutionPool(4, 10, func(rb *events.Bucket) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		<-release
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	pool.start()
+	defer pool.stop()
+
+	for i := 0; i < 4; i++ {
+		require.True(t, pool.push(newPoolTestBucket("limited", 0, 2)))
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	stats := pool.stats()
+	require.Equal(t, 2, stats.Running)
+	require.Equal(t, 2, stats.QueuedByRule["limited"])
+
+	close(release)
+
+	err := backoff.Retry(func() error {
+		if pool.stats().Executed != 4 {
+			return fmt.Errorf("executions pending")
+		}
+		return nil
+	}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Millisecond*10), 100))
+	require.NoError(t, err)
+	require.Equal(t, 2, maxRunning)
+}
+
+func TestExecutionPoolBackpressure(t *testing.T) {
+	pool := newExecutionPool(1, 2, func(rb *events.Bucket) {})
+
+	// not started, so nothing is dequeued
+	require.True(t, pool.push(newPoolTestBucket("a", 0, 0)))
+	require.True(t, pool.push(newPoolTestBucket("b", 0, 0)))
+	require.False(t, pool.reserve())
+	require.False(t, pool.push(newPoolTestBucket("c", 0, 0)))
+
+	stats := pool.stats()
+	require.True(t, stats.Backpressure)
+	require.Equal(t, uint64(2), stats.Deferred)
+	require.Equal(t, 2, stats.Queued)
+}
+
+func TestExecutionPoolReserve(t *testing.T) {
+	pool := newExecutionPool(1, 2, func(rb *events.Bucket) {})
+
+	require.True(t, pool.push(newPoolTestBucket("a", 0, 0)))
+	require.True(t, pool.reserve())
+
+	// a late re-execution filling the queue between the reservation and the push of the flushed bucket
+	require.False(t, pool.push(newPoolTestBucket("late", 0, 0)))
+	pool.pushReserved(newPoolTestBucket("flushed", 0, 0))
+
+	stats := pool.stats()
+	require.Equal(t, 2, stats.Queued)
+	require.Equal(t, 1, stats.QueuedByRule["flushed"])
+
+	// a released reservation frees the slot
+	pool.next()
+	require.True(t, pool.reserve())
+	require.False(t, pool.push(newPoolTestBucket("late", 0, 0)))
+	pool.release()
+	require.True(t, pool.push(newPoolTestBucket("late", 0, 0)))
+}
+
+func TestExecutionPending(t *testing.T) {
+	store, err := newStore(&config.Config{ExecutionQueueSize: 1})
+	require.NoError(t, err)
+
+	// the pool is not started, so nothing is dequeued
+	require.True(t, store.queueExecution(newPoolTestBucket("a", 0, 0)))
+	require.False(t, store.queueExecution(newPoolTestBucket("late", 0, 0)))
+	require.Len(t, store.pending, 1)
+
+	// the pending bucket waits while the queue is full
+	store.executePending()
+	require.Len(t, store.pending, 1)
+
+	require.Equal(t, "a", store.executionPool.next().Rule.ID)
+	store.executePending()
+	require.Empty(t, store.pending)
+	require.Equal(t, "late", store.executionPool.next().Rule.ID)
+}
+
+func TestStashResultDeferred(t *testing.T) {
+	store, err := newStore(&config.Config{ExecutionQueueSize: 1})
+	require.NoError(t, err)
+	require.True(t, store.executionPool.push(newPoolTestBucket("a", 0, 0)))
+
+	// the late event is committed, a full queue defers its re-execution
+	resp := &StashResult{RuleID: "late", Late: true, Reexecuted: true, reexecute: newPoolTestBucket("late", 0, 0)}
+	result, err := store.stashResult(&events.Event{EventID: "1"}, resp)
+	require.NoError(t, err)
+	require.True(t, result.ReexecutionDeferred)
+	require.Len(t, store.pending, 1)
+}
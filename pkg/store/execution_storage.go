@@ -1,85 +1,521 @@
 package store
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/boltdb/bolt"
 	"github.com/golang/glog"
 
 	"github.com/myntra/cortex/pkg/executions"
 )
 
+var (
+	// recordsBucket contains a nested bucket per rule id. records are keyed by created_at and id
+	recordsBucket = []byte("records")
+	// recordIDsBucket maps a record id to its rule id and record key
+	recordIDsBucket = []byte("record_ids")
+)
+
+const restoreBatchSize = 1000 // records written per transaction on restore
+
+// executionStorage stores the execution history in a bolt index so that it is neither held in memory nor
+// copied for every snapshot
 type executionStorage struct {
-	mu sync.RWMutex
-	m  map[string]*executions.Record
+	mu        sync.RWMutex // write locked to set the retention and to replace the db on restore
+	db        *bolt.DB
+	path      string
+	retention *executions.Retention // nil until set at runtime
 }
 
-func (e *executionStorage) add(r *executions.Record) error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
+func openHistory(path string, readOnly bool) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, err
+	}
+
+	if readOnly {
+		return db, nil
+	}
 
-	e.m[r.ID] = r
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(recordsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(recordIDsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (e *executionStorage) open(path string) error {
+	db, err := openHistory(path, false)
+	if err != nil {
+		return err
+	}
+
+	e.db = db
+	e.path = path
 	return nil
 }
 
-func (e *executionStorage) remove(id string) error {
+func (e *executionStorage) close() error {
+	if e.db == nil {
+		return nil
+	}
+	return e.db.Close()
+}
+
+func (e *executionStorage) view(fn func(tx *bolt.Tx) error) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.db.View(fn)
+}
+
+func (e *executionStorage) update(fn func(tx *bolt.Tx) error) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.db.Update(fn)
+}
+
+// recordKey sorts the records of a rule by created_at
+func recordKey(r *executions.Record) []byte {
+	key := make([]byte, 8, 8+len(r.ID))
+	binary.BigEndian.PutUint64(key, uint64(r.CreatedAt.UnixNano()))
+	return append(key, r.ID...)
+}
+
+// timeKey is the smallest record key created at t
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+func putRecord(tx *bolt.Tx, r *executions.Record) error {
+	b, err := r.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+
+	ruleID := r.Bucket.Rule.ID
+	rb, err := tx.Bucket(recordsBucket).CreateBucketIfNotExists([]byte(ruleID))
+	if err != nil {
+		return err
+	}
+
+	key := recordKey(r)
+	if err := rb.Put(key, b); err != nil {
+		return err
+	}
+
+	return tx.Bucket(recordIDsBucket).Put([]byte(r.ID), append([]byte(ruleID+"\x00"), key...))
+}
+
+func getRecord(v []byte) (*executions.Record, error) {
+	var r executions.Record
+	if _, err := r.UnmarshalMsg(v); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// forEachRecord calls fn for every record in the tx
+func forEachRecord(tx *bolt.Tx, fn func(r *executions.Record) error) error {
+	return tx.Bucket(recordsBucket).ForEach(func(ruleID, _ []byte) error {
+		return tx.Bucket(recordsBucket).Bucket(ruleID).ForEach(func(k, v []byte) error {
+			r, err := getRecord(v)
+			if err != nil {
+				return err
+			}
+			return fn(r)
+		})
+	})
+}
+
+func (e *executionStorage) add(r *executions.Record) error {
+	return e.update(func(tx *bolt.Tx) error {
+		return putRecord(tx, r)
+	})
+}
+
+func (e *executionStorage) remove(ids ...string) error {
+	return e.update(func(tx *bolt.Tx) error {
+		idx := tx.Bucket(recordIDsBucket)
+		for _, id := range ids {
+			v := idx.Get([]byte(id))
+			if v == nil {
+				continue
+			}
+
+			sep := bytes.IndexByte(v, 0)
+			if rb := tx.Bucket(recordsBucket).Bucket(v[:sep]); rb != nil {
+				if err := rb.Delete(v[sep+1:]); err != nil {
+					return err
+				}
+			}
+
+			if err := idx.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *executionStorage) setRetention(retention *executions.Retention) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	delete(e.m, id)
+	e.retention = retention
 	return nil
 }
 
-func (e *executionStorage) getRecords(ruleID string) []*executions.Record {
+func (e *executionStorage) getRetention() *executions.Retention {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.retention == nil {
+		return nil
+	}
+
+	retention := *e.retention
+	return &retention
+}
+
+// index returns every record with only the id, rule id and created_at set
+func (e *executionStorage) index() []*executions.Record {
+	var records []*executions.Record
+	err := e.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(recordsBucket).ForEach(func(ruleID, _ []byte) error {
+			return tx.Bucket(recordsBucket).Bucket(ruleID).ForEach(func(k, _ []byte) error {
+				record := &executions.Record{
+					ID:        string(k[8:]),
+					CreatedAt: time.Unix(0, int64(binary.BigEndian.Uint64(k[:8]))),
+				}
+				record.Bucket.Rule.ID = string(ruleID)
+				records = append(records, record)
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		glog.Errorf("index records err %v", err)
+	}
+	return records
+}
+
+// getRecords returns all the records of the rule, newest first
+func (e *executionStorage) getRecords(ruleID string) []*executions.Record {
 	glog.Infof("getRecords %v", ruleID)
 
 	var exs []*executions.Record
-	for _, record := range e.m {
-		if record.Bucket.Rule.ID == ruleID {
-			exs = append(exs, record)
+	err := e.view(func(tx *bolt.Tx) error {
+		rb := tx.Bucket(recordsBucket).Bucket([]byte(ruleID))
+		if rb == nil {
+			return nil
 		}
+
+		c := rb.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			r, err := getRecord(v)
+			if err != nil {
+				return err
+			}
+			exs = append(exs, r)
+		}
+		return nil
+	})
+	if err != nil {
+		glog.Errorf("getRecords %v err %v", ruleID, err)
 	}
 	return exs
 }
 
-func (e *executionStorage) getRecordsCount(ruleID string) int {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	count := 0
-	for _, record := range e.m {
-		if record.Bucket.Rule.ID == ruleID {
-			count++
+// latest returns the rule's newest record
+func (e *executionStorage) latest(ruleID string) *executions.Record {
+	var record *executions.Record
+	err := e.view(func(tx *bolt.Tx) error {
+		rb := tx.Bucket(recordsBucket).Bucket([]byte(ruleID))
+		if rb == nil {
+			return nil
+		}
+
+		_, v := rb.Cursor().Last()
+		if v == nil {
+			return nil
+		}
+
+		var err error
+		record, err = getRecord(v)
+		return err
+	})
+	if err != nil {
+		glog.Errorf("latest record %v err %v", ruleID, err)
+	}
+	return record
+}
+
+// query returns a page of the rule's records matching the query. expects a validated query
+func (e *executionStorage) query(q *executions.Query) (*executions.Page, error) {
+	var cursorKey []byte
+	if q.Cursor != "" {
+		var err error
+		cursorKey, err = base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil || len(cursorKey) < 8 {
+			return nil, fmt.Errorf("invalid cursor %v", q.Cursor)
 		}
 	}
 
+	page := &executions.Page{Records: make([]*executions.Record, 0)}
+	err := e.view(func(tx *bolt.Tx) error {
+		rb := tx.Bucket(recordsBucket).Bucket([]byte(q.RuleID))
+		if rb == nil {
+			return nil
+		}
+
+		c := rb.Cursor()
+		var k, v []byte
+		var next func() ([]byte, []byte)
+		if q.Order == executions.OrderAsc {
+			next = c.Next
+			switch {
+			case cursorKey != nil:
+				k, v = c.Seek(cursorKey)
+				if bytes.Equal(k, cursorKey) {
+					k, v = c.Next()
+				}
+			case !q.From.IsZero():
+				k, v = c.Seek(timeKey(q.From))
+			default:
+				k, v = c.First()
+			}
+		} else {
+			next = c.Prev
+			// seek to the first key past the upper bound and step back
+			var upper []byte
+			switch {
+			case cursorKey != nil:
+				upper = cursorKey
+			case !q.To.IsZero():
+				upper = timeKey(q.To.Add(time.Nanosecond))
+			}
+
+			if upper == nil {
+				k, v = c.Last()
+			} else if k, v = c.Seek(upper); k == nil {
+				k, v = c.Last()
+			} else {
+				k, v = c.Prev()
+			}
+		}
+
+		var lastKey []byte
+		for ; k != nil; k, v = next() {
+			r, err := getRecord(v)
+			if err != nil {
+				return err
+			}
+
+			// past the time range, no more matches
+			if (q.Order == executions.OrderAsc && !q.To.IsZero() && r.CreatedAt.After(q.To)) ||
+				(q.Order == executions.OrderDesc && !q.From.IsZero() && r.CreatedAt.Before(q.From)) {
+				break
+			}
+
+			if !q.Matches(r) {
+				continue
+			}
+
+			if len(page.Records) == q.Limit {
+				page.NextCursor = base64.RawURLEncoding.EncodeToString(lastKey)
+				break
+			}
+
+			page.Records = append(page.Records, r)
+			lastKey = append([]byte(nil), k...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (e *executionStorage) getRecordsCount(ruleID string) int {
+	count := 0
+	e.view(func(tx *bolt.Tx) error {
+		if rb := tx.Bucket(recordsBucket).Bucket([]byte(ruleID)); rb != nil {
+			count = rb.Stats().KeyN
+		}
+		return nil
+	})
 	return count
 }
 
 func (e *executionStorage) getTotalRecordsCount() int {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	return len(e.m)
+	count := 0
+	e.view(func(tx *bolt.Tx) error {
+		count = tx.Bucket(recordIDsBucket).Stats().KeyN
+		return nil
+	})
+	return count
 }
 
-func (e *executionStorage) flush(id string) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
+// recordsSnapshot is a copy of the history, so that a snapshot doesn't hold a read transaction while it is
+// persisted
+type recordsSnapshot struct {
+	db   *bolt.DB
+	path string
+}
+
+// snapshot copies the history to a temporary file. the caller must release the snapshot
+func (e *executionStorage) snapshot() (*recordsSnapshot, error) {
+	f, err := ioutil.TempFile(filepath.Dir(e.path), filepath.Base(e.path)+".snapshot")
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.view(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(f)
+		return err
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	db, err := openHistory(f.Name(), true)
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return &recordsSnapshot{db: db, path: f.Name()}, nil
+}
+
+// forEach calls fn for every record of the snapshot
+func (s *recordsSnapshot) forEach(fn func(r *executions.Record) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return forEachRecord(tx, fn)
+	})
+}
 
-	delete(e.m, id)
+func (s *recordsSnapshot) release() {
+	if err := s.db.Close(); err != nil {
+		glog.Errorf("close records snapshot err %v", err)
+	}
+	os.Remove(s.path)
+}
+
+// recordsRestore writes the restored records to a new history file in transactions of restoreBatchSize records,
+// so that a restore doesn't hold the history in memory
+type recordsRestore struct {
+	db   *bolt.DB
+	path string
+	tx   *bolt.Tx
+	n    int
+}
+
+// beginRestore returns the writer of the restored records. the restore is either aborted or passed to restore
+func (e *executionStorage) beginRestore() (*recordsRestore, error) {
+	path := e.path + ".restore"
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	db, err := openHistory(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordsRestore{db: db, path: path}, nil
+}
+
+func (r *recordsRestore) put(record *executions.Record) error {
+	if r.tx == nil {
+		tx, err := r.db.Begin(true)
+		if err != nil {
+			return err
+		}
+		r.tx = tx
+	}
+
+	if err := putRecord(r.tx, record); err != nil {
+		return err
+	}
+
+	r.n++
+	if r.n%restoreBatchSize == 0 {
+		return r.commit()
+	}
+	return nil
 }
 
-func (e *executionStorage) clone() map[string]*executions.Record {
+func (r *recordsRestore) commit() error {
+	if r.tx == nil {
+		return nil
+	}
+
+	err := r.tx.Commit()
+	r.tx = nil
+	return err
+}
+
+func (r *recordsRestore) abort() {
+	if r.tx != nil {
+		r.tx.Rollback()
+	}
+	r.db.Close()
+	os.Remove(r.path)
+}
+
+// restore replaces the history with the restored records
+func (e *executionStorage) restore(r *recordsRestore, retention *executions.Retention) error {
+	if err := r.commit(); err != nil {
+		r.abort()
+		return err
+	}
+	if err := r.db.Close(); err != nil {
+		os.Remove(r.path)
+		return err
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	clone := make(map[string]*executions.Record)
-	for k, v := range e.m {
-		clone[k] = v
+
+	e.retention = retention
+
+	if err := e.db.Close(); err != nil {
+		return err
 	}
-	return clone
-}
+	if err := os.Rename(r.path, e.path); err != nil {
+		return err
+	}
+
+	db, err := openHistory(e.path, false)
+	if err != nil {
+		return err
+	}
+	e.db = db
 
-func (e *executionStorage) restore(m map[string]*executions.Record) {
-	e.m = m
+	glog.Infof("restored %v records", r.n)
+	return nil
 }
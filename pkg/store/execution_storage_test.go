@@ -0,0 +1,108 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/rules"
+)
+
+func newTestExecutionStorage(t *testing.T) (*executionStorage, func()) {
+	dir, err := ioutil.TempDir("", "executions")
+	require.NoError(t, err)
+
+	e := &executionStorage{}
+	require.NoError(t, e.open(filepath.Join(dir, "executions.db")))
+
+	return e, func() {
+		e.close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestExecutionStorageQuery(t *testing.T) {
+	e, cleanup := newTestExecutionStorage(t)
+	defer cleanup()
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		status := 200
+		scriptStatus := executions.ScriptStatusOK
+		if i%2 == 1 {
+			status = 500
+			scriptStatus = executions.ScriptStatusError
+		}
+
+		require.NoError(t, e.add(&executions.Record{
+			ID:             fmt.Sprintf("record-%d", i),
+			Bucket:         *events.NewBucket(rules.Rule{ID: "rule"}),
+			HookStatusCode: status,
+			ScriptStatus:   scriptStatus,
+			CreatedAt:      start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	require.NoError(t, e.add(&executions.Record{
+		ID:        "other",
+		Bucket:    *events.NewBucket(rules.Rule{ID: "other"}),
+		CreatedAt: start,
+	}))
+
+	require.Equal(t, 10, e.getRecordsCount("rule"))
+	require.Equal(t, 11, e.getTotalRecordsCount())
+
+	query := func(q *executions.Query) *executions.Page {
+		require.NoError(t, q.Validate())
+		page, err := e.query(q)
+		require.NoError(t, err)
+		return page
+	}
+
+	ids := func(page *executions.Page) []string {
+		var ids []string
+		for _, r := range page.Records {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	// newest first by default
+	page := query(&executions.Query{RuleID: "rule", Limit: 3})
+	require.Equal(t, []string{"record-9", "record-8", "record-7"}, ids(page))
+	require.NotEmpty(t, page.NextCursor)
+
+	page = query(&executions.Query{RuleID: "rule", Limit: 3, Cursor: page.NextCursor})
+	require.Equal(t, []string{"record-6", "record-5", "record-4"}, ids(page))
+
+	// oldest first within the time range
+	page = query(&executions.Query{
+		RuleID: "rule",
+		Order:  executions.OrderAsc,
+		From:   start.Add(2 * time.Minute),
+		To:     start.Add(5 * time.Minute),
+	})
+	require.Equal(t, []string{"record-2", "record-3", "record-4", "record-5"}, ids(page))
+	require.Empty(t, page.NextCursor)
+
+	page = query(&executions.Query{RuleID: "rule", HookStatusCode: 500, Order: executions.OrderAsc, Limit: 2})
+	require.Equal(t, []string{"record-1", "record-3"}, ids(page))
+
+	page = query(&executions.Query{RuleID: "rule", ScriptStatus: executions.ScriptStatusError, Order: executions.OrderAsc, Limit: 2, Cursor: page.NextCursor})
+	require.Equal(t, []string{"record-5", "record-7"}, ids(page))
+
+	require.NoError(t, e.remove("record-9", "other"))
+	require.Equal(t, 9, e.getRecordsCount("rule"))
+	require.Equal(t, 9, e.getTotalRecordsCount())
+	require.Len(t, e.getRecords("other"), 0)
+
+	_, err := e.query(&executions.Query{RuleID: "rule", Cursor: "!"})
+	require.Error(t, err)
+}
@@ -3,13 +3,18 @@ package store
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/hashicorp/raft"
+	"github.com/myntra/cortex/pkg/enrichment"
 	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
 	"github.com/tinylib/msgp/msgp"
 )
 
@@ -42,9 +47,15 @@ func (f *fsm) Apply(l *raft.Log) interface{} {
 	// 	panic(fmt.Sprintf("failed to unmarshal command: %s", err.Error()))
 	// }
 
+	return f.applyCommand(c)
+}
+
+func (f *fsm) applyCommand(c Command) interface{} {
 	switch c.Op {
+	case "batch":
+		return f.applyBatch(c.Commands)
 	case "stash":
-		return f.applyStash(c.RuleID, c.Event)
+		return f.applyStash(c.RuleID, c.Event, c.Timestamp)
 	case "add_rule":
 		return f.applyAddRule(c.Rule)
 	case "update_rule":
@@ -55,24 +66,129 @@ func (f *fsm) Apply(l *raft.Log) interface{} {
 		return f.applyFlushBucket(c.RuleID)
 	case "flush_lock":
 		return f.applyFlushLock(c.RuleID)
+	case "discard_bucket":
+		return f.applyDiscardBucket(c.RuleID)
+	case "extend_bucket":
+		return f.applyExtendBucket(c.RuleID, c.Extension)
 	case "add_script":
 		return f.applyAddScript(c.Script)
 	case "update_script":
 		return f.applyUpdateScript(c.Script)
 	case "remove_script":
 		return f.applyRemoveScript(c.ScriptID)
+	case "add_sink":
+		return f.applyAddSink(c.Sink)
+	case "update_sink":
+		return f.applyUpdateSink(c.Sink)
+	case "remove_sink":
+		return f.applyRemoveSink(c.SinkID)
+	case "add_schema":
+		return f.applyAddSchema(c.Schema)
+	case "update_schema":
+		return f.applyUpdateSchema(c.Schema)
+	case "remove_schema":
+		return f.applyRemoveSchema(c.SchemaID)
+	case "add_table":
+		return f.applyAddTable(c.Table)
+	case "update_table":
+		return f.applyUpdateTable(c.Table)
+	case "remove_table":
+		return f.applyRemoveTable(c.TableID)
+	case "set_pipeline":
+		return f.applySetPipeline(c.Pipeline)
 	case "add_record":
 		return f.applyAddRecord(c.Record)
 	case "remove_record":
-		return f.applyRemoveRecord(c.RecordID)
+		return f.applyRemoveRecord(c.RecordID, c.RecordIDs)
+	case "set_retention":
+		return f.applySetRetention(c.Retention)
+	case "set_checkpoint":
+		return f.applySetCheckpoint(c.InputID, c.ReadUntil)
+	case "upsert_incident":
+		return f.applyUpsertIncident(c.Incident)
+	case "ack_incident":
+		return f.applyAckIncident(c.Incident)
+	case "resolve_incident":
+		return f.applyResolveIncident(c.Incident)
+	case "remove_incidents":
+		return f.applyRemoveIncidents(c.IncidentIDs)
 	default:
 		panic(fmt.Sprintf("unrecognized command op: %s", c.Op))
 	}
 
 }
 
-func (f *fsm) applyStash(ruleID string, event *events.Event) interface{} {
-	return f.bucketStorage.stash(ruleID, event)
+// applyBatch applies the commands of a batch in order and returns their responses
+func (f *fsm) applyBatch(commands []Command) interface{} {
+	responses := make([]interface{}, len(commands))
+	for i, c := range commands {
+		if c.Op == "batch" {
+			responses[i] = fmt.Errorf("nested batch commands are not supported")
+			continue
+		}
+		responses[i] = f.applyCommand(c)
+	}
+	return responses
+}
+
+// legacyStashTime stamps the stash commands without a timestamp and without an event time
+var legacyStashTime = time.Unix(0, 0).UTC()
+
+func (f *fsm) applyStash(ruleID string, event *events.Event, timestamp time.Time) interface{} {
+	if timestamp.IsZero() {
+		// log entries written before the leader stamped the stash commands. the replicas agree on the event time, or
+		// else on the epoch, which flushes the bucket on the next tick
+		timestamp = legacyStashTime
+		if !event.EventTime.IsZero() {
+			timestamp = event.EventTime
+		}
+		glog.Warningf("stash of event %v for rule %v has no timestamp, using %v", event.EventID, ruleID, timestamp)
+	}
+
+	rule := f.bucketStorage.rs.getRule(ruleID)
+	if rule == nil || !rule.IsEventTime() || event.EventTime.IsZero() {
+		return stashResponse(f.bucketStorage.stash(ruleID, event, timestamp))
+	}
+
+	if f.bucketStorage.es.isLate(*rule, event.EventTime, timestamp) {
+		return stashResponse(f.applyLateEvent(rule, event, timestamp))
+	}
+
+	return stashResponse(f.bucketStorage.stash(ruleID, event, event.EventTime))
+}
+
+// stashResponse returns the error, if any, or the result as the fsm response
+func stashResponse(result *StashResult, err error) interface{} {
+	if err != nil {
+		return err
+	}
+	return result
+}
+
+// applyLateEvent applies the rule's late policy to an event which arrived after its event time window. a bucket to be
+// executed again is returned to the leader.
+func (f *fsm) applyLateEvent(rule *rules.Rule, event *events.Event, timestamp time.Time) (*StashResult, error) {
+	glog.Infof("late event %v for rule %v, policy %v", event.EventID, rule.ID, rule.GetLatePolicy())
+
+	switch rule.GetLatePolicy() {
+	case rules.LatePolicyNextBucket:
+		return f.bucketStorage.es.stashLate(*rule, event, timestamp)
+	case rules.LatePolicyReexecute:
+		record := f.executionStorage.latest(rule.ID)
+		if record == nil {
+			return f.bucketStorage.es.stashLate(*rule, event, timestamp)
+		}
+
+		rb := record.Bucket
+		rb.Rule = *rule
+		rb.Events = append(rb.Events, event)
+		rb.FlushLock = false
+		rb.LateEvents = 1
+		return &StashResult{RuleID: rule.ID, Late: true, Reexecuted: true, reexecute: &rb}, nil
+	default:
+		f.bucketStorage.es.dropLate(rule.ID)
+		return &StashResult{RuleID: rule.ID, Late: true}, nil
+	}
 }
 
 func (f *fsm) applyAddRule(rule *rules.Rule) interface{} {
@@ -80,21 +196,43 @@ func (f *fsm) applyAddRule(rule *rules.Rule) interface{} {
 }
 
 func (f *fsm) applyUpdateRule(rule *rules.Rule) interface{} {
-	return f.bucketStorage.rs.updateRule(rule)
+	if err := f.bucketStorage.rs.updateRule(rule); err != nil {
+		return err
+	}
+
+	f.bucketStorage.es.updateRule(rule)
+	return nil
 }
 
 func (f *fsm) applyRemoveRule(ruleID string) interface{} {
-	return f.bucketStorage.rs.removeRule(ruleID)
+	if err := f.bucketStorage.rs.removeRule(ruleID); err != nil {
+		return err
+	}
+
+	f.bucketStorage.es.removeBucket(ruleID)
+	return nil
 }
 
 func (f *fsm) applyFlushBucket(ruleID string) interface{} {
-	return f.bucketStorage.es.flushBucket(ruleID)
+	window, err := f.bucketStorage.es.flushBucket(ruleID, f.bucketStorage.rs.getRule(ruleID))
+	if err != nil {
+		return err
+	}
+	return window
 }
 
 func (f *fsm) applyFlushLock(ruleID string) interface{} {
 	return f.bucketStorage.es.flushLock(ruleID)
 }
 
+func (f *fsm) applyDiscardBucket(ruleID string) interface{} {
+	return f.bucketStorage.es.discardBucket(ruleID)
+}
+
+func (f *fsm) applyExtendBucket(ruleID string, extension uint64) interface{} {
+	return f.bucketStorage.es.extendBucket(ruleID, extension)
+}
+
 func (f *fsm) applyAddScript(script *js.Script) interface{} {
 	return f.scriptStorage.addScript(script)
 }
@@ -107,12 +245,87 @@ func (f *fsm) applyRemoveScript(id string) interface{} {
 	return f.scriptStorage.removeScript(id)
 }
 
+func (f *fsm) applyAddSink(sink *sinks.GenericSink) interface{} {
+	return f.sinkStorage.addSink(sink)
+}
+
+func (f *fsm) applyUpdateSink(sink *sinks.GenericSink) interface{} {
+	return f.sinkStorage.updateSink(sink)
+}
+
+func (f *fsm) applyRemoveSink(id string) interface{} {
+	return f.sinkStorage.removeSink(id)
+}
+
+func (f *fsm) applyAddSchema(schema *schemas.Schema) interface{} {
+	return f.schemaStorage.addSchema(schema)
+}
+
+func (f *fsm) applyUpdateSchema(schema *schemas.Schema) interface{} {
+	return f.schemaStorage.updateSchema(schema)
+}
+
+func (f *fsm) applyRemoveSchema(id string) interface{} {
+	return f.schemaStorage.removeSchema(id)
+}
+
+func (f *fsm) applyAddTable(table *enrichment.Table) interface{} {
+	return f.enrichmentStorage.addTable(table)
+}
+
+func (f *fsm) applyUpdateTable(table *enrichment.Table) interface{} {
+	return f.enrichmentStorage.updateTable(table)
+}
+
+func (f *fsm) applyRemoveTable(id string) interface{} {
+	return f.enrichmentStorage.removeTable(id)
+}
+
+func (f *fsm) applySetPipeline(pipeline *enrichment.Pipeline) interface{} {
+	return f.enrichmentStorage.setPipeline(pipeline)
+}
+
 func (f *fsm) applyAddRecord(r *executions.Record) interface{} {
 	return f.executionStorage.add(r)
 }
 
-func (f *fsm) applyRemoveRecord(id string) interface{} {
-	return f.executionStorage.remove(id)
+func (f *fsm) applyRemoveRecord(id string, ids []string) interface{} {
+	if id != "" {
+		ids = append(ids, id)
+	}
+	return f.executionStorage.remove(ids...)
+}
+
+func (f *fsm) applySetRetention(retention *executions.Retention) interface{} {
+	return f.executionStorage.setRetention(retention)
+}
+
+func (f *fsm) applySetCheckpoint(inputID string, readUntil time.Time) interface{} {
+	return f.checkpointStorage.setCheckpoint(inputID, readUntil)
+}
+
+func (f *fsm) applyUpsertIncident(incident *incidents.Incident) interface{} {
+	notification, err := f.incidentStorage.upsert(incident)
+	if err != nil {
+		return err
+	}
+	return notification
+}
+
+func (f *fsm) applyAckIncident(incident *incidents.Incident) interface{} {
+	return f.incidentStorage.acknowledge(incident)
+}
+
+func (f *fsm) applyResolveIncident(incident *incidents.Incident) interface{} {
+	notification, err := f.incidentStorage.resolve(incident)
+	if err != nil {
+		return err
+	}
+	return notification
+}
+
+func (f *fsm) applyRemoveIncidents(ids []string) interface{} {
+	return f.incidentStorage.remove(ids...)
 }
 
 func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
@@ -120,14 +333,33 @@ func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
 
 	rules := f.bucketStorage.rs.clone()
 	scripts := f.scriptStorage.clone()
-	records := f.executionStorage.clone()
+	records, err := f.executionStorage.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	retention := f.executionStorage.getRetention()
+	incidents := f.incidentStorage.clone()
+	buckets := f.bucketStorage.es.snapshot()
+	sinkDefs := f.sinkStorage.clone()
+	schemaDefs := f.schemaStorage.clone()
+	tables, pipeline := f.enrichmentStorage.clone()
+	checkpoints := f.checkpointStorage.clone()
 
 	return &fsmSnapShot{
 		persisters: f.persisters,
 		messages: &Messages{
-			Rules:   rules,
-			Scripts: scripts,
-			Records: records,
+			Rules:       rules,
+			Scripts:     scripts,
+			Incidents:   incidents,
+			Retention:   retention,
+			Buckets:     buckets,
+			Late:        f.bucketStorage.es.lateSnapshot(),
+			Sinks:       sinkDefs,
+			Schemas:     schemaDefs,
+			Tables:      tables,
+			Pipeline:    pipeline,
+			Checkpoints: checkpoints,
+			records:     records,
 		}}, nil
 }
 
@@ -140,10 +372,22 @@ func (f *fsm) Restore(rc io.ReadCloser) error {
 	// body, _ := ioutil.ReadAll(rc)
 	// glog.Infoln(string(body))
 
+	restored, err := f.executionStorage.beginRestore()
+	if err != nil {
+		return err
+	}
+
 	messages := &Messages{
-		Rules:   make(map[string]*rules.Rule),
-		Scripts: make(map[string]*js.Script),
-		Records: make(map[string]*executions.Record),
+		Rules:       make(map[string]*rules.Rule),
+		Scripts:     make(map[string]*js.Script),
+		Incidents:   make(map[string]*incidents.Incident),
+		Buckets:     make(map[string]*events.BucketSnapshot),
+		Late:        make(map[string]int),
+		Sinks:       make(map[string]*sinks.GenericSink),
+		Schemas:     make(map[string]*schemas.Schema),
+		Tables:      make(map[string]*enrichment.Table),
+		Checkpoints: make(map[string]time.Time),
+		restored:    restored,
 	}
 
 	msgpReader := msgp.NewReader(rc)
@@ -157,6 +401,7 @@ func (f *fsm) Restore(rc io.ReadCloser) error {
 			break
 		} else if err != nil {
 			glog.Error(err)
+			restored.abort()
 			return err
 		}
 
@@ -166,10 +411,12 @@ func (f *fsm) Restore(rc io.ReadCloser) error {
 		if fn := f.restorers[msg]; fn != nil {
 			if err := fn(messages, msgpReader); err != nil {
 				glog.Error(err)
+				restored.abort()
 				return err
 			}
 		} else {
 			glog.Error(fmt.Errorf("Unrecognized msg type %d", msg))
+			restored.abort()
 			return fmt.Errorf("Unrecognized msg type %d", msg)
 		}
 
@@ -177,7 +424,20 @@ func (f *fsm) Restore(rc io.ReadCloser) error {
 
 	f.bucketStorage.rs.restore(messages.Rules)
 	f.scriptStorage.restore(messages.Scripts)
-	f.executionStorage.restore(messages.Records)
+	f.sinkStorage.restore(messages.Sinks)
+	f.schemaStorage.restore(messages.Schemas)
+	f.enrichmentStorage.restore(messages.Tables, messages.Pipeline)
+	if err := f.executionStorage.restore(restored, messages.Retention); err != nil {
+		return err
+	}
+	f.incidentStorage.restore(messages.Incidents)
+	f.checkpointStorage.restore(messages.Checkpoints)
+
+	buckets := make(map[string]*events.Bucket)
+	for ruleID, snapshot := range messages.Buckets {
+		buckets[ruleID] = snapshot.Restore()
+	}
+	f.bucketStorage.es.restore(buckets, messages.Late)
 
 	return nil
 }
@@ -224,6 +484,66 @@ func restoreScripts(messages *Messages, reader *msgp.Reader) error {
 	return nil
 }
 
+func restoreSinks(messages *Messages, reader *msgp.Reader) error {
+	var sink sinks.GenericSink
+	err := sink.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreSinks %+v\n", sink)
+
+	messages.Sinks[sink.ID] = &sink
+	return nil
+}
+
+func restoreSchemas(messages *Messages, reader *msgp.Reader) error {
+	var schema schemas.Schema
+	err := schema.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreSchemas %+v\n", schema.ID)
+
+	messages.Schemas[schema.ID] = &schema
+	return nil
+}
+
+func restoreTables(messages *Messages, reader *msgp.Reader) error {
+	var table enrichment.Table
+	err := table.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreTables %+v\n", table.ID)
+
+	if err := table.Validate(); err != nil {
+		return err
+	}
+
+	messages.Tables[table.ID] = &table
+	return nil
+}
+
+func restorePipeline(messages *Messages, reader *msgp.Reader) error {
+	var pipeline enrichment.Pipeline
+	err := pipeline.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restorePipeline %+v\n", pipeline)
+
+	messages.Pipeline = &pipeline
+	return nil
+}
+
 func restoreRecords(messages *Messages, reader *msgp.Reader) error {
 	var record executions.Record
 	err := record.DecodeMsg(reader)
@@ -232,13 +552,94 @@ func restoreRecords(messages *Messages, reader *msgp.Reader) error {
 		return err
 	}
 
-	glog.Infof("restoreRecords %+v\n", record)
+	glog.Infof("restoreRecords %+v\n", record.ID)
+
+	return messages.restored.put(&record)
+}
+
+func restoreIncidents(messages *Messages, reader *msgp.Reader) error {
+	var incident incidents.Incident
+	err := incident.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreIncidents %+v\n", incident)
+
+	messages.Incidents[incident.ID] = &incident
+
+	return nil
+}
+
+func restoreRetention(messages *Messages, reader *msgp.Reader) error {
+	var retention executions.Retention
+	err := retention.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreRetention %+v\n", retention)
+
+	messages.Retention = &retention
+
+	return nil
+}
+
+func restoreLate(messages *Messages, reader *msgp.Reader) error {
+	ruleID, err := reader.ReadString()
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	count, err := reader.ReadInt()
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreLate %v %v\n", ruleID, count)
 
-	if &record == nil {
-		return fmt.Errorf("restored record nil")
+	messages.Late[ruleID] = count
+	return nil
+}
+
+func restoreCheckpoints(messages *Messages, reader *msgp.Reader) error {
+	inputID, err := reader.ReadString()
+	if err != nil {
+		glog.Error(err)
+		return err
 	}
 
-	messages.Records[record.ID] = &record
+	readUntil, err := reader.ReadTime()
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	glog.Infof("restoreCheckpoints %v %v\n", inputID, readUntil)
+
+	messages.Checkpoints[inputID] = readUntil
+	return nil
+}
+
+func restoreBuckets(messages *Messages, reader *msgp.Reader) error {
+	var snapshot events.BucketSnapshot
+	err := snapshot.DecodeMsg(reader)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	if snapshot.Bucket == nil {
+		return fmt.Errorf("restored bucket nil")
+	}
+
+	glog.Infof("restoreBuckets %+v\n", snapshot)
+
+	messages.Buckets[snapshot.Bucket.Rule.ID] = &snapshot
 
 	return nil
 }
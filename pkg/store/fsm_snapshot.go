@@ -2,6 +2,7 @@ package store
 
 import (
 	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/executions"
 	"github.com/tinylib/msgp/msgp"
 
 	"github.com/hashicorp/raft"
@@ -16,6 +17,9 @@ type fsmSnapShot struct {
 
 func (f *fsmSnapShot) Release() {
 	glog.Info("release =>")
+	if f.messages.records != nil {
+		f.messages.records.release()
+	}
 }
 
 func (f *fsmSnapShot) Persist(sink raft.SnapshotSink) error {
@@ -81,12 +85,97 @@ func persistScripts(messages *Messages, writer *msgp.Writer, sink raft.SnapshotS
 	return nil
 }
 
+func persistSinks(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for _, s := range messages.Sinks {
+		if _, err := sink.Write([]byte{byte(SinkType)}); err != nil {
+			glog.Errorf("persistSinks %v", err)
+			continue
+		}
+
+		// Encode message.
+		err := s.EncodeMsg(writer)
+		if err != nil {
+			glog.Errorf("persistSinks %v", err)
+			continue
+		}
+
+		err = writer.Flush()
+		glog.Infof("persistSinks %+v %v \n", s, err)
+	}
+	return nil
+}
+
+func persistSchemas(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for _, s := range messages.Schemas {
+		if _, err := sink.Write([]byte{byte(SchemaType)}); err != nil {
+			glog.Errorf("persistSchemas %v", err)
+			continue
+		}
+
+		// Encode message.
+		err := s.EncodeMsg(writer)
+		if err != nil {
+			glog.Errorf("persistSchemas %v", err)
+			continue
+		}
+
+		err = writer.Flush()
+		glog.Infof("persistSchemas %+v %v \n", s.ID, err)
+	}
+	return nil
+}
+
+func persistTables(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for _, t := range messages.Tables {
+		if _, err := sink.Write([]byte{byte(TableType)}); err != nil {
+			glog.Errorf("persistTables %v", err)
+			continue
+		}
+
+		// Encode message.
+		err := t.EncodeMsg(writer)
+		if err != nil {
+			glog.Errorf("persistTables %v", err)
+			continue
+		}
+
+		err = writer.Flush()
+		glog.Infof("persistTables %+v %v \n", t.ID, err)
+	}
+	return nil
+}
+
+func persistPipeline(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+	if messages.Pipeline == nil {
+		return nil
+	}
+
+	if _, err := sink.Write([]byte{byte(PipelineType)}); err != nil {
+		glog.Errorf("persistPipeline %v", err)
+		return nil
+	}
+
+	// Encode message.
+	err := messages.Pipeline.EncodeMsg(writer)
+	if err != nil {
+		glog.Errorf("persistPipeline %v", err)
+		return nil
+	}
+
+	err = writer.Flush()
+	glog.Infof("persistPipeline %+v %v \n", messages.Pipeline, err)
+	return nil
+}
+
 func persistRecords(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
 
-	for _, record := range messages.Records {
+	return messages.records.forEach(func(record *executions.Record) error {
 		if _, err := sink.Write([]byte{byte(RecordType)}); err != nil {
 			glog.Errorf("persistRecords %v", err)
-			continue
+			return nil
 		}
 
 		glog.Info("persist record msg size ", record.Msgsize())
@@ -94,11 +183,125 @@ func persistRecords(messages *Messages, writer *msgp.Writer, sink raft.SnapshotS
 		err := record.EncodeMsg(writer)
 		if err != nil {
 			glog.Errorf("persistRecords %v", err)
-			continue
+			return nil
 		}
 
 		err = writer.Flush()
 		glog.Infof("persistRecords %+v %v \n", record, err)
+		return nil
+	})
+}
+
+func persistIncidents(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for _, incident := range messages.Incidents {
+		if _, err := sink.Write([]byte{byte(IncidentType)}); err != nil {
+			glog.Errorf("persistIncidents %v", err)
+			continue
+		}
+
+		glog.Info("persist incident msg size ", incident.Msgsize())
+		// Encode message.
+		err := incident.EncodeMsg(writer)
+		if err != nil {
+			glog.Errorf("persistIncidents %v", err)
+			continue
+		}
+
+		err = writer.Flush()
+		glog.Infof("persistIncidents %+v %v \n", incident, err)
+	}
+	return nil
+}
+
+func persistRetention(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+	if messages.Retention == nil {
+		return nil
+	}
+
+	if _, err := sink.Write([]byte{byte(RetentionType)}); err != nil {
+		glog.Errorf("persistRetention %v", err)
+		return nil
+	}
+
+	// Encode message.
+	err := messages.Retention.EncodeMsg(writer)
+	if err != nil {
+		glog.Errorf("persistRetention %v", err)
+		return nil
+	}
+
+	err = writer.Flush()
+	glog.Infof("persistRetention %+v %v \n", messages.Retention, err)
+	return nil
+}
+
+func persistLate(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for ruleID, count := range messages.Late {
+		if _, err := sink.Write([]byte{byte(LateType)}); err != nil {
+			glog.Errorf("persistLate %v", err)
+			continue
+		}
+
+		if err := writer.WriteString(ruleID); err != nil {
+			glog.Errorf("persistLate %v", err)
+			continue
+		}
+
+		if err := writer.WriteInt(count); err != nil {
+			glog.Errorf("persistLate %v", err)
+			continue
+		}
+
+		err := writer.Flush()
+		glog.Infof("persistLate %v %v %v \n", ruleID, count, err)
+	}
+	return nil
+}
+
+func persistCheckpoints(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for inputID, readUntil := range messages.Checkpoints {
+		if _, err := sink.Write([]byte{byte(CheckpointType)}); err != nil {
+			glog.Errorf("persistCheckpoints %v", err)
+			continue
+		}
+
+		if err := writer.WriteString(inputID); err != nil {
+			glog.Errorf("persistCheckpoints %v", err)
+			continue
+		}
+
+		if err := writer.WriteTime(readUntil); err != nil {
+			glog.Errorf("persistCheckpoints %v", err)
+			continue
+		}
+
+		err := writer.Flush()
+		glog.Infof("persistCheckpoints %v %v %v \n", inputID, readUntil, err)
+	}
+	return nil
+}
+
+func persistBuckets(messages *Messages, writer *msgp.Writer, sink raft.SnapshotSink) error {
+
+	for _, bucket := range messages.Buckets {
+		if _, err := sink.Write([]byte{byte(BucketType)}); err != nil {
+			glog.Errorf("persistBuckets %v", err)
+			continue
+		}
+
+		glog.Info("persist bucket msg size ", bucket.Msgsize())
+		// Encode message.
+		err := bucket.EncodeMsg(writer)
+		if err != nil {
+			glog.Errorf("persistBuckets %v", err)
+			continue
+		}
+
+		err = writer.Flush()
+		glog.Infof("persistBuckets %+v %v \n", bucket, err)
 	}
 	return nil
 }
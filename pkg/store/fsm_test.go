@@ -0,0 +1,688 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/enrichment"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
+	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
+)
+
+type testSnapshotSink struct {
+	bytes.Buffer
+}
+
+func (s *testSnapshotSink) ID() string    { return "test" }
+func (s *testSnapshotSink) Cancel() error { return nil }
+func (s *testSnapshotSink) Close() error  { return nil }
+
+func newTestFSM(t *testing.T) (*fsm, func()) {
+	dir, err := ioutil.TempDir("", "fsm_test")
+	require.NoError(t, err)
+
+	store, err := newStore(&config.Config{})
+	require.NoError(t, err)
+	require.NoError(t, store.executionStorage.open(filepath.Join(dir, "executions.db")))
+
+	return (*fsm)(store), func() {
+		store.executionStorage.close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFSMSnapshotBuckets(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	rule := rules.Rule{ID: "dwelling", Dwell: 60000, DwellDeadline: 50000, MaxDwell: 120000}
+	event := &events.Event{EventType: "acme.prod.cpu", EventID: "1", Source: "/test"}
+	_, err := f1.bucketStorage.es.stash(rule, event, time.Now())
+	require.NoError(t, err)
+
+	before := f1.bucketStorage.es.getBucket(rule.ID)
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+
+	after := f2.bucketStorage.es.getBucket(rule.ID)
+	require.NotNil(t, after)
+	require.Len(t, after.Events, 1)
+	require.Equal(t, event.EventID, after.Events[0].EventID)
+	require.Equal(t, rule.Dwell, after.Rule.Dwell)
+
+	// the restored bucket keeps the original flush schedule
+	require.False(t, after.CanFlush())
+	require.InDelta(t, float64(before.CanFlushIn()), float64(after.CanFlushIn()), float64(time.Second))
+	require.Equal(t, before.Snapshot().DwellResetAt.UnixNano(), after.Snapshot().DwellResetAt.UnixNano())
+}
+
+func TestFSMSnapshotRecords(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := restoreBatchSize + 10
+	for i := 0; i < n; i++ {
+		require.NoError(t, f1.executionStorage.add(&executions.Record{
+			ID:        fmt.Sprintf("record-%d", i),
+			Bucket:    *events.NewBucket(rules.Rule{ID: fmt.Sprintf("rule-%d", i%3)}),
+			CreatedAt: start.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	// the snapshot is a copy of the history taken by Snapshot
+	require.NoError(t, f1.executionStorage.add(&executions.Record{ID: "later", Bucket: *events.NewBucket(rules.Rule{ID: "rule-0"})}))
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	require.NoError(t, f2.executionStorage.add(&executions.Record{ID: "stale", Bucket: *events.NewBucket(rules.Rule{ID: "stale"})}))
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+
+	require.Equal(t, n, f2.executionStorage.getTotalRecordsCount())
+	require.Equal(t, 0, f2.executionStorage.getRecordsCount("stale"))
+	require.Equal(t, "record-1008", f2.executionStorage.latest("rule-0").ID)
+
+	// the restored history is writable
+	require.NoError(t, f2.executionStorage.add(&executions.Record{ID: "new", Bucket: *events.NewBucket(rules.Rule{ID: "rule-0"}), CreatedAt: time.Now()}))
+	require.Equal(t, "new", f2.executionStorage.latest("rule-0").ID)
+}
+
+func TestFSMSinks(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	nagios := &sinks.GenericSink{ID: "nagios", Path: "nagios", EventType: "nagios.{{.host}}"}
+	require.NoError(t, nagios.Validate())
+	applyTestCommand(t, f1, 1, Command{Op: "add_sink", Sink: nagios})
+
+	// paths are unique across sinks
+	err, _ := f1.applyCommand(Command{Op: "add_sink", Sink: &sinks.GenericSink{ID: "other", Path: "nagios", EventType: "x"}}).(error)
+	require.Error(t, err)
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+	require.Equal(t, nagios, f2.sinkStorage.getSinkByPath("nagios"))
+
+	// an update moves the sink to its new path
+	moved := &sinks.GenericSink{ID: "nagios", Path: "nagios-v2", EventType: "nagios.{{.host}}"}
+	require.NoError(t, moved.Validate())
+	applyTestCommand(t, f2, 2, Command{Op: "update_sink", Sink: moved})
+	require.Nil(t, f2.sinkStorage.getSinkByPath("nagios"))
+	require.Equal(t, moved, f2.sinkStorage.getSinkByPath("nagios-v2"))
+
+	applyTestCommand(t, f2, 3, Command{Op: "remove_sink", SinkID: nagios.ID})
+	require.Nil(t, f2.sinkStorage.getSinkByPath("nagios-v2"))
+	require.Nil(t, f2.sinkStorage.getSink(nagios.ID))
+}
+
+func TestFSMCheckpoints(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	readUntil := time.Date(2018, 11, 1, 10, 0, 0, 0, time.UTC)
+	applyTestCommand(t, f1, 1, Command{Op: "set_checkpoint", InputID: "k8s.prod", ReadUntil: readUntil})
+
+	// checkpoints only move forward
+	applyTestCommand(t, f1, 2, Command{Op: "set_checkpoint", InputID: "k8s.prod", ReadUntil: readUntil.Add(-time.Minute)})
+	require.True(t, readUntil.Equal(f1.checkpointStorage.getCheckpoint("k8s.prod")))
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+	require.True(t, readUntil.Equal(f2.checkpointStorage.getCheckpoint("k8s.prod")))
+	require.True(t, f2.checkpointStorage.getCheckpoint("k8s.staging").IsZero())
+}
+
+func TestFSMIncidents(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	start := time.Date(2018, 11, 1, 10, 0, 0, 0, time.UTC)
+	for i, groupKey := range []string{"/a", "/b", "/c"} {
+		applyTestCommand(t, f1, uint64(i+1), Command{Op: "upsert_incident", Incident: &incidents.Incident{
+			ID: "incident" + groupKey, RuleID: "rule", GroupKey: groupKey, UpdatedAt: start,
+		}})
+	}
+
+	for i, groupKey := range []string{"/a", "/b"} {
+		applyTestCommand(t, f1, uint64(i+4), Command{Op: "resolve_incident", Incident: &incidents.Incident{
+			RuleID: "rule", GroupKey: groupKey, UpdatedAt: start.Add(time.Duration(i+1) * time.Minute),
+		}})
+	}
+
+	require.Nil(t, f1.incidentStorage.getActive("rule", "/a"))
+	require.Equal(t, "incident/c", f1.incidentStorage.getActive("rule", "/c").ID)
+
+	// a new incident opens for a resolved group key
+	applyTestCommand(t, f1, 6, Command{Op: "upsert_incident", Incident: &incidents.Incident{
+		ID: "incident/a2", RuleID: "rule", GroupKey: "/a", UpdatedAt: start.Add(3 * time.Minute),
+	}})
+	require.Equal(t, "incident/a2", f1.incidentStorage.getActive("rule", "/a").ID)
+
+	// the oldest resolved incidents exceeding the count or the age expire first, the active ones never expire
+	now := start.Add(time.Hour)
+	require.Empty(t, f1.incidentStorage.expired(2, 0, now))
+	require.Equal(t, []string{"incident/a"}, f1.incidentStorage.expired(1, 0, now))
+	require.Equal(t, []string{"incident/a", "incident/b"}, f1.incidentStorage.expired(0, 0, now))
+	require.Equal(t, []string{"incident/a"}, f1.incidentStorage.expired(2, 58*time.Minute+30*time.Second, now))
+
+	applyTestCommand(t, f1, 7, Command{Op: "remove_incidents", IncidentIDs: []string{"incident/a", "incident/c"}})
+	require.Nil(t, f1.incidentStorage.getIncident("incident/a"))
+	require.NotNil(t, f1.incidentStorage.getIncident("incident/c"))
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	// the restore rebuilds the index of the active incidents
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+	require.Len(t, f2.incidentStorage.getIncidents(""), 3)
+	require.Equal(t, "incident/a2", f2.incidentStorage.getActive("rule", "/a").ID)
+	require.Equal(t, "incident/c", f2.incidentStorage.getActive("rule", "/c").ID)
+	require.Nil(t, f2.incidentStorage.getActive("rule", "/b"))
+}
+
+func TestFSMSchemas(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	disk := &schemas.Schema{
+		ID:                "disk",
+		URL:               "https://schemas.acme.com/disk.json",
+		EventTypePatterns: []string{"acme.*.disk_full"},
+		Schema:            map[string]interface{}{"type": "object", "required": []interface{}{"host"}},
+	}
+	applyTestCommand(t, f1, 1, Command{Op: "add_schema", Schema: disk})
+
+	// urls are unique across schemas
+	err, _ := f1.applyCommand(Command{Op: "add_schema", Schema: &schemas.Schema{ID: "other", URL: disk.URL, Schema: map[string]interface{}{}}}).(error)
+	require.Error(t, err)
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+	require.Equal(t, []string{"acme.*.disk_full"}, f2.schemaStorage.getSchema(disk.ID).EventTypePatterns)
+
+	// the restored schema validates the events matching its patterns or with its url
+	store := (*defaultStore)(f2)
+	store.opt.SchemaValidation = schemas.ModeReject
+	require.True(t, schemas.IsInvalidEvent(store.validate(&events.Event{EventType: "acme.prod.disk_full", EventID: "1"})))
+	require.True(t, schemas.IsInvalidEvent(store.validate(&events.Event{EventType: "acme.prod.cpu", EventID: "2", SchemaURL: disk.URL})))
+	require.NoError(t, store.validate(&events.Event{EventType: "acme.prod.disk_full", EventID: "3", Data: map[string]interface{}{"host": "web-1"}}))
+	require.NoError(t, store.validate(&events.Event{EventType: "acme.prod.cpu", EventID: "4"}))
+
+	store.opt.SchemaValidation = schemas.ModeTag
+	event := &events.Event{EventType: "acme.prod.disk_full", EventID: "5"}
+	require.NoError(t, store.validate(event))
+	require.Equal(t, true, event.Extensions.(map[string]interface{})[schemas.ExtensionInvalid])
+
+	applyTestCommand(t, f2, 2, Command{Op: "remove_schema", SchemaID: disk.ID})
+	require.Nil(t, f2.schemaStorage.getSchema(disk.ID))
+
+	// a schema whose remote $ref can't be loaded fails the event in both modes, without tagging or rejecting it
+	remote := &schemas.Schema{
+		ID:                "remote",
+		EventTypePatterns: []string{"acme.*.disk_full"},
+		Schema:            map[string]interface{}{"$ref": "http://127.0.0.1:1/disk.json"},
+	}
+	applyTestCommand(t, f2, 3, Command{Op: "add_schema", Schema: remote})
+	for _, mode := range []string{schemas.ModeReject, schemas.ModeTag} {
+		store.opt.SchemaValidation = mode
+		event := &events.Event{EventType: "acme.prod.disk_full", EventID: "6"}
+		err := store.validate(event)
+		require.True(t, schemas.IsSchemaError(err))
+		require.False(t, schemas.IsInvalidEvent(err))
+		require.Nil(t, event.Extensions)
+	}
+}
+
+func TestFSMEnrichment(t *testing.T) {
+	f1, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+
+	hosts, err := enrichment.ParseCSV("hosts", "host", strings.NewReader("host,team,service\nweb-1,checkout,cart\n"))
+	require.NoError(t, err)
+	services, err := enrichment.ParseJSON("services", "", strings.NewReader(`{"cart": {"tier": 1}}`))
+	require.NoError(t, err)
+	applyTestCommand(t, f1, 1, Command{Op: "add_table", Table: hosts})
+	applyTestCommand(t, f1, 2, Command{Op: "add_table", Table: services})
+
+	// steps must reference existing tables
+	err, _ = f1.applyCommand(Command{Op: "set_pipeline", Pipeline: &enrichment.Pipeline{Steps: []enrichment.Step{{Table: "dcs", Key: "data.ip"}}}}).(error)
+	require.Error(t, err)
+
+	applyTestCommand(t, f1, 3, Command{Op: "set_pipeline", Pipeline: &enrichment.Pipeline{Steps: []enrichment.Step{
+		{Table: "hosts", Key: "data.host"},
+		{Table: "services", Key: "extensions.service", Target: enrichment.TargetData},
+	}}})
+
+	// tables used by a step can't be removed
+	err, _ = f1.applyCommand(Command{Op: "remove_table", TableID: "hosts"}).(error)
+	require.Error(t, err)
+
+	snapshot, err := f1.Snapshot()
+	require.NoError(t, err)
+
+	sink := &testSnapshotSink{}
+	require.NoError(t, snapshot.Persist(sink))
+	snapshot.Release()
+
+	f2, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	require.NoError(t, f2.Restore(ioutil.NopCloser(sink)))
+	require.Len(t, f2.enrichmentStorage.getPipeline().Steps, 2)
+
+	// the restored steps enrich the events in order
+	event := &events.Event{EventType: "acme.prod.disk_full", EventID: "1", Data: map[string]interface{}{"host": "web-1"}}
+	f2.enrichmentStorage.enrich(event)
+	require.Equal(t, map[string]interface{}{"team": "checkout", "service": "cart"}, event.Extensions)
+	require.Equal(t, map[string]interface{}{"host": "web-1", "tier": float64(1)}, event.Data)
+
+	applyTestCommand(t, f2, 4, Command{Op: "set_pipeline", Pipeline: &enrichment.Pipeline{}})
+	applyTestCommand(t, f2, 5, Command{Op: "remove_table", TableID: "hosts"})
+	require.Nil(t, f2.enrichmentStorage.getTable("hosts"))
+}
+
+func applyTestCommand(t *testing.T, f *fsm, index uint64, cmd Command) {
+	b, err := cmd.MarshalMsg(nil)
+	require.NoError(t, err)
+
+	if err, ok := f.Apply(&raft.Log{Index: index, Data: b}).(error); ok {
+		require.NoError(t, err)
+	}
+}
+
+func TestFSMStashTimestamp(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rule := &rules.Rule{ID: "timed", EventTypePatterns: []string{"acme.*"}, Dwell: 1000, DwellDeadline: 800, MaxDwell: 3000}
+	log := []Command{
+		{Op: "add_rule", Rule: rule},
+		{Op: "stash", RuleID: rule.ID, Event: &events.Event{EventID: "1", Source: "/a"}, Timestamp: start},
+		{Op: "stash", RuleID: rule.ID, Event: &events.Event{EventID: "2", Source: "/b"}, Timestamp: start.Add(900 * time.Millisecond)},
+	}
+
+	// the deadlines only depend on the stamps: a replica replaying the log later computes the same deadlines
+	var deadlines []time.Time
+	for i := 0; i < 2; i++ {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		for j, cmd := range log {
+			applyTestCommand(t, f, uint64(j+1), cmd)
+		}
+
+		rb := f.bucketStorage.es.getBucket(rule.ID)
+		require.NotNil(t, rb)
+		require.True(t, start.Equal(rb.CreatedAt))
+		deadlines = append(deadlines, rb.FlushAt())
+	}
+
+	require.True(t, start.Add(2*time.Second).Equal(deadlines[0]))
+	require.True(t, deadlines[0].Equal(deadlines[1]))
+
+	// log entries of nodes which didn't stamp the stash commands replay with the event time, else the epoch
+	legacy := []Command{
+		{Op: "add_rule", Rule: rule},
+		{Op: "stash", RuleID: rule.ID, Event: &events.Event{EventID: "3", EventTime: start}},
+		{Op: "add_rule", Rule: &rules.Rule{ID: "untimed", EventTypePatterns: []string{"acme.*"}, Dwell: 1000, DwellDeadline: 800, MaxDwell: 3000}},
+		{Op: "stash", RuleID: "untimed", Event: &events.Event{EventID: "4"}},
+	}
+
+	for i := 0; i < 2; i++ {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		for j, cmd := range legacy {
+			applyTestCommand(t, f, uint64(j+1), cmd)
+		}
+
+		rb := f.bucketStorage.es.getBucket(rule.ID)
+		require.NotNil(t, rb)
+		require.True(t, start.Equal(rb.CreatedAt))
+
+		rb = f.bucketStorage.es.getBucket("untimed")
+		require.NotNil(t, rb)
+		require.True(t, legacyStashTime.Equal(rb.CreatedAt))
+		require.True(t, rb.CanFlushAt(start))
+	}
+}
+
+func TestFSMStashLeaderChange(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := &rules.Rule{ID: "timed", EventTypePatterns: []string{"acme.*"}, Dwell: 1000, DwellDeadline: 800, MaxDwell: 3000}
+
+	leader, cleanup1 := newTestFSM(t)
+	defer cleanup1()
+	follower, cleanup2 := newTestFSM(t)
+	defer cleanup2()
+
+	// the clocks of the nodes disagree
+	leader.clock = func() time.Time { return start }
+	follower.clock = func() time.Time { return start.Add(time.Hour) }
+
+	apply := func(stamper *fsm, index uint64, cmd Command) {
+		if cmd.Op == "stash" {
+			cmd.Timestamp = stamper.clock()
+		}
+		for _, f := range []*fsm{leader, follower} {
+			applyTestCommand(t, f, index, cmd)
+		}
+	}
+
+	apply(leader, 1, Command{Op: "add_rule", Rule: rule})
+	apply(leader, 2, Command{Op: "stash", RuleID: rule.ID, Event: &events.Event{EventID: "1", Source: "/a"}})
+	require.True(t, leader.bucketStorage.es.getBucket(rule.ID).FlushAt().Equal(follower.bucketStorage.es.getBucket(rule.ID).FlushAt()))
+
+	// the follower takes over the leadership and stamps the next stash, past the dwell deadline, with its clock
+	follower.clock = func() time.Time { return start.Add(900 * time.Millisecond) }
+	apply(follower, 3, Command{Op: "stash", RuleID: rule.ID, Event: &events.Event{EventID: "2", Source: "/b"}})
+
+	old := leader.bucketStorage.es.getBucket(rule.ID)
+	current := follower.bucketStorage.es.getBucket(rule.ID)
+	require.Len(t, current.Events, 2)
+	require.True(t, old.FlushAt().Equal(current.FlushAt()))
+	require.True(t, start.Add(2*time.Second).Equal(current.FlushAt()))
+
+	// the new leader flushes the bucket when the old one would have
+	require.False(t, current.CanFlushAt(start.Add(1999*time.Millisecond)))
+	require.True(t, current.CanFlushAt(start.Add(2*time.Second)))
+}
+
+func TestFSMEventTimeWindows(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newRule := func(policy string) *rules.Rule {
+		return &rules.Rule{
+			ID:                "late",
+			EventTypePatterns: []string{"acme.*"},
+			Dwell:             1000,
+			DwellDeadline:     800,
+			MaxDwell:          3000,
+			WindowTime:        rules.WindowTimeEvent,
+			AllowedLateness:   5000,
+			LatePolicy:        policy,
+		}
+	}
+
+	stash := func(id string, eventTime, timestamp time.Time) Command {
+		return Command{
+			Op:        "stash",
+			RuleID:    "late",
+			Event:     &events.Event{EventID: id, Source: "/" + id, EventTime: eventTime},
+			Timestamp: timestamp,
+		}
+	}
+
+	t.Run("window by event time", func(t *testing.T) {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 1, Command{Op: "add_rule", Rule: newRule("")})
+		// buffered event received a minute later, still within the open window
+		applyTestCommand(t, f, 2, stash("1", start, start.Add(3*time.Second)))
+		applyTestCommand(t, f, 3, stash("2", start.Add(500*time.Millisecond), start.Add(time.Minute)))
+
+		rb := f.bucketStorage.es.getBucket("late")
+		require.Len(t, rb.Events, 2)
+		require.True(t, start.Equal(rb.CreatedAt))
+		// dwell plus allowed lateness
+		require.True(t, start.Add(6*time.Second).Equal(rb.FlushAt()))
+		require.Equal(t, 0, rb.LateEvents)
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 1, Command{Op: "add_rule", Rule: newRule(rules.LatePolicyDrop)})
+		require.NoError(t, f.executionStorage.add(&executions.Record{
+			ID:        "previous",
+			Bucket:    *events.NewBucketAt(*newRule(""), start),
+			CreatedAt: start.Add(6 * time.Second),
+		}))
+
+		applyTestCommand(t, f, 2, stash("1", start, start.Add(time.Minute)))
+
+		// the executed record is left as is, the dropped event is counted in the next bucket
+		require.Nil(t, f.bucketStorage.es.getBucket("late"))
+		require.Equal(t, 0, f.executionStorage.latest("late").LateEvents)
+
+		// the count survives a snapshot
+		snapshot, err := f.Snapshot()
+		require.NoError(t, err)
+		sink := &testSnapshotSink{}
+		require.NoError(t, snapshot.Persist(sink))
+		snapshot.Release()
+
+		restored, cleanupRestored := newTestFSM(t)
+		defer cleanupRestored()
+		require.NoError(t, restored.Restore(ioutil.NopCloser(sink)))
+
+		for _, f := range []*fsm{f, restored} {
+			applyTestCommand(t, f, 3, stash("2", start.Add(time.Minute), start.Add(time.Minute)))
+			applyTestCommand(t, f, 4, stash("3", start, start.Add(time.Minute)))
+
+			rb := f.bucketStorage.es.getBucket("late")
+			require.Len(t, rb.Events, 1)
+			require.Equal(t, 2, rb.LateEvents)
+
+			window, ok := f.applyCommand(Command{Op: "flush_bucket", RuleID: "late"}).(*events.Bucket)
+			require.True(t, ok)
+			require.Equal(t, 2, window.LateEvents)
+		}
+	})
+
+	t.Run("next bucket", func(t *testing.T) {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 1, Command{Op: "add_rule", Rule: newRule(rules.LatePolicyNextBucket)})
+		applyTestCommand(t, f, 2, stash("1", start.Add(time.Minute), start.Add(time.Minute)))
+		applyTestCommand(t, f, 3, stash("2", start, start.Add(time.Minute)))
+
+		rb := f.bucketStorage.es.getBucket("late")
+		require.Len(t, rb.Events, 2)
+		require.Equal(t, 1, rb.LateEvents)
+	})
+
+	t.Run("reexecute", func(t *testing.T) {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 1, Command{Op: "add_rule", Rule: newRule(rules.LatePolicyReexecute)})
+		previous := events.NewBucketAt(*newRule(""), start)
+		previous.Events = []*events.Event{{EventID: "0", Source: "/0"}}
+		require.NoError(t, f.executionStorage.add(&executions.Record{
+			ID:        "previous",
+			Bucket:    *previous,
+			CreatedAt: start.Add(6 * time.Second),
+		}))
+
+		cmd := stash("1", start, start.Add(time.Minute))
+		b, err := cmd.MarshalMsg(nil)
+		require.NoError(t, err)
+
+		result, ok := f.Apply(&raft.Log{Index: 2, Data: b}).(*StashResult)
+		require.True(t, ok)
+		require.True(t, result.Late)
+		require.True(t, result.Reexecuted)
+		rb := result.reexecute
+		require.Len(t, rb.Events, 2)
+		require.Equal(t, 1, rb.LateEvents)
+		require.Nil(t, f.bucketStorage.es.getBucket("late"))
+	})
+}
+
+func TestFSMRuleUpdateBuckets(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newRule := func(policy, hook string) *rules.Rule {
+		return &rules.Rule{
+			ID:                "updated",
+			EventTypePatterns: []string{"acme.*"},
+			HookEndpoint:      hook,
+			Dwell:             1000,
+			DwellDeadline:     800,
+			MaxDwell:          3000,
+			UpdatePolicy:      policy,
+		}
+	}
+
+	setup := func(t *testing.T) (*fsm, func()) {
+		f, cleanup := newTestFSM(t)
+		applyTestCommand(t, f, 1, Command{Op: "add_rule", Rule: newRule("", "http://old")})
+		applyTestCommand(t, f, 2, Command{
+			Op:        "stash",
+			RuleID:    "updated",
+			Event:     &events.Event{EventID: "1", Source: "/1"},
+			Timestamp: start,
+		})
+		require.NotNil(t, f.bucketStorage.es.getBucket("updated"))
+		return f, cleanup
+	}
+
+	t.Run("rebind", func(t *testing.T) {
+		f, cleanup := setup(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 3, Command{Op: "update_rule", Rule: newRule(rules.UpdatePolicyRebind, "http://new")})
+
+		rb := f.bucketStorage.es.getBucket("updated")
+		require.Equal(t, "http://new", rb.Rule.HookEndpoint)
+		require.Len(t, rb.Events, 1)
+		require.False(t, rb.FlushLock)
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		f, cleanup := setup(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 3, Command{Op: "update_rule", Rule: newRule(rules.UpdatePolicyFlush, "http://new")})
+
+		rb := f.bucketStorage.es.getBucket("updated")
+		require.Equal(t, "http://old", rb.Rule.HookEndpoint)
+		require.True(t, rb.FlushLock)
+	})
+
+	t.Run("flush next window", func(t *testing.T) {
+		f, cleanup := newTestFSM(t)
+		defer cleanup()
+
+		hopping := func(policy, hook string) *rules.Rule {
+			rule := newRule(policy, hook)
+			rule.WindowType = rules.WindowTypeHopping
+			rule.WindowSize = 1000
+			rule.WindowHop = 500
+			return rule
+		}
+
+		applyTestCommand(t, f, 1, Command{Op: "add_rule", Rule: hopping("", "http://old")})
+		applyTestCommand(t, f, 2, Command{
+			Op:        "stash",
+			RuleID:    "updated",
+			Event:     &events.Event{EventID: "1", Source: "/1"},
+			Timestamp: start.Add(600 * time.Millisecond),
+		})
+		applyTestCommand(t, f, 3, Command{Op: "update_rule", Rule: hopping(rules.UpdatePolicyFlush, "http://new")})
+
+		window, ok := f.applyCommand(Command{Op: "flush_bucket", RuleID: "updated"}).(*events.Bucket)
+		require.True(t, ok)
+		require.Equal(t, "http://old", window.Rule.HookEndpoint)
+
+		rb := f.bucketStorage.es.getBucket("updated")
+		require.NotNil(t, rb)
+		require.Equal(t, "http://new", rb.Rule.HookEndpoint)
+		require.False(t, rb.FlushLock)
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		f, cleanup := setup(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 3, Command{Op: "update_rule", Rule: newRule(rules.UpdatePolicyDiscard, "http://new")})
+		require.Nil(t, f.bucketStorage.es.getBucket("updated"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		f, cleanup := setup(t)
+		defer cleanup()
+
+		disabled := newRule(rules.UpdatePolicyRebind, "http://old")
+		disabled.Disabled = true
+		applyTestCommand(t, f, 3, Command{Op: "update_rule", Rule: disabled})
+		require.Nil(t, f.bucketStorage.es.getBucket("updated"))
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		f, cleanup := setup(t)
+		defer cleanup()
+
+		applyTestCommand(t, f, 3, Command{Op: "remove_rule", RuleID: "updated"})
+		require.Nil(t, f.bucketStorage.es.getBucket("updated"))
+	})
+}
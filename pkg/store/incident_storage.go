@@ -0,0 +1,203 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/myntra/cortex/pkg/incidents"
+)
+
+type incidentStorage struct {
+	mu      sync.RWMutex
+	m       map[string]*incidents.Incident // [incidentID]
+	actives map[string]string              // [activeKey]incidentID of the unresolved incidents
+}
+
+// activeKey is the key of the unresolved incident of the rule and group key
+func activeKey(ruleID, groupKey string) string {
+	return ruleID + "\x00" + groupKey
+}
+
+// active returns the unresolved incident for the rule and group key. expects the lock to be held.
+func (i *incidentStorage) active(ruleID, groupKey string) *incidents.Incident {
+	id, ok := i.actives[activeKey(ruleID, groupKey)]
+	if !ok {
+		return nil
+	}
+	return i.m[id]
+}
+
+// upsert opens a new incident or updates the active incident for the rule and group key
+func (i *incidentStorage) upsert(incident *incidents.Incident) (*incidents.Notification, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if existing := i.active(incident.RuleID, incident.GroupKey); existing != nil {
+		existing.RecordIDs = append(existing.RecordIDs, incident.RecordIDs...)
+		existing.UpdatedAt = incident.UpdatedAt
+		return &incidents.Notification{Action: incidents.ActionUpdate, Incident: existing.Clone()}, nil
+	}
+
+	if _, ok := i.m[incident.ID]; ok {
+		return nil, fmt.Errorf("incident id already exists")
+	}
+
+	incident.Status = incidents.StatusOpen
+	incident.OpenedAt = incident.UpdatedAt
+	i.m[incident.ID] = incident
+	i.actives[activeKey(incident.RuleID, incident.GroupKey)] = incident.ID
+
+	return &incidents.Notification{Action: incidents.ActionOpen, Incident: incident.Clone()}, nil
+}
+
+func (i *incidentStorage) acknowledge(incident *incidents.Incident) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	existing, ok := i.m[incident.ID]
+	if !ok {
+		return fmt.Errorf("incident id does not exist")
+	}
+
+	if existing.Status != incidents.StatusOpen {
+		return fmt.Errorf("incident is %s, can't acknowledge", existing.Status)
+	}
+
+	existing.Status = incidents.StatusAcknowledged
+	existing.AcknowledgedAt = incident.UpdatedAt
+	existing.UpdatedAt = incident.UpdatedAt
+
+	return nil
+}
+
+// resolve resolves the incident by id, or the active incident for the rule and group key if the id is empty
+func (i *incidentStorage) resolve(incident *incidents.Incident) (*incidents.Notification, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var existing *incidents.Incident
+	if incident.ID != "" {
+		existing = i.m[incident.ID]
+	} else {
+		existing = i.active(incident.RuleID, incident.GroupKey)
+	}
+
+	if existing == nil {
+		return nil, fmt.Errorf("incident does not exist")
+	}
+
+	if !existing.IsActive() {
+		return nil, fmt.Errorf("incident is already resolved")
+	}
+
+	existing.Status = incidents.StatusResolved
+	delete(i.actives, activeKey(existing.RuleID, existing.GroupKey))
+	existing.ResolvedBy = incident.ResolvedBy
+	existing.ResolvedAt = incident.UpdatedAt
+	existing.UpdatedAt = incident.UpdatedAt
+
+	return &incidents.Notification{Action: incidents.ActionResolve, Incident: existing.Clone()}, nil
+}
+
+// remove the resolved incidents with the ids
+func (i *incidentStorage) remove(ids ...string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		if incident, ok := i.m[id]; ok && !incident.IsActive() {
+			delete(i.m, id)
+		}
+	}
+
+	return nil
+}
+
+// expired returns the ids of the resolved incidents resolved longer than maxAge ago, or not among the newest
+// maxResolved resolved incidents, oldest first. a zero maxAge keeps the incidents regardless of their age
+func (i *incidentStorage) expired(maxResolved int, maxAge time.Duration, now time.Time) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var resolved []*incidents.Incident
+	for _, incident := range i.m {
+		if !incident.IsActive() {
+			resolved = append(resolved, incident)
+		}
+	}
+
+	sort.Slice(resolved, func(a, b int) bool { return resolved[a].ResolvedAt.After(resolved[b].ResolvedAt) })
+
+	var ids []string
+	for n := len(resolved) - 1; n >= 0; n-- {
+		incident := resolved[n]
+		if n >= maxResolved || (maxAge > 0 && now.Sub(incident.ResolvedAt) > maxAge) {
+			ids = append(ids, incident.ID)
+		}
+	}
+
+	return ids
+}
+
+func (i *incidentStorage) getActive(ruleID, groupKey string) *incidents.Incident {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	incident := i.active(ruleID, groupKey)
+	if incident == nil {
+		return nil
+	}
+
+	return incident.Clone()
+}
+
+func (i *incidentStorage) getIncident(id string) *incidents.Incident {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	incident, ok := i.m[id]
+	if !ok {
+		return nil
+	}
+
+	return incident.Clone()
+}
+
+// getIncidents returns the incidents with the status. an empty status returns all incidents
+func (i *incidentStorage) getIncidents(status string) []*incidents.Incident {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var list []*incidents.Incident
+	for _, incident := range i.m {
+		if status == "" || incident.Status == status {
+			list = append(list, incident.Clone())
+		}
+	}
+
+	return list
+}
+
+func (i *incidentStorage) clone() map[string]*incidents.Incident {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	clone := make(map[string]*incidents.Incident)
+	for k, v := range i.m {
+		clone[k] = v.Clone()
+	}
+	return clone
+}
+
+func (i *incidentStorage) restore(m map[string]*incidents.Incident) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.m = m
+	i.actives = make(map[string]string)
+	for id, incident := range m {
+		if incident.IsActive() {
+			i.actives[activeKey(incident.RuleID, incident.GroupKey)] = id
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/myntra/cortex/pkg/events"
+)
+
+// IngestResult acknowledges an event once it is stashed in the buckets of the matching rules
+type IngestResult struct {
+	EventID      string         `json:"event_id"`
+	MatchedRules []string       `json:"matched_rules"`
+	Buckets      []*StashResult `json:"buckets"`
+	Error        string         `json:"error,omitempty"` // set if stashing the event for a rule failed in a batch
+}
+
+// StashResult is the outcome of stashing an event for a matching rule
+type StashResult struct {
+	RuleID          string    `json:"rule_id"`
+	Joined          bool      `json:"joined"`       // true if the event was added to the rule's bucket
+	NewBucket       bool      `json:"new_bucket"`   // true if the event opened the bucket
+	Deduplicated    bool      `json:"deduplicated"` // true if an identical event from the source was already in the bucket
+	Late            bool      `json:"late"`         // true if the event arrived after its event time window
+	Reexecuted      bool      `json:"reexecuted"`   // true if the late event re-executes the rule's latest bucket
+	BucketCreatedAt time.Time `json:"bucket_created_at,omitempty"`
+	// true if the execution queue was full, the re-execution waits for the next flusher tick
+	ReexecutionDeferred bool `json:"reexecution_deferred"`
+	reexecute           *events.Bucket
+}
+
+// IsUnavailable returns true if the error is temporary, i.e the node lost leadership or raft timed out, and the
+// request can be retried
+func IsUnavailable(err error) bool {
+	switch err {
+	case errNotLeader, raft.ErrNotLeader, raft.ErrLeadershipLost, raft.ErrEnqueueTimeout, raft.ErrRaftShutdown:
+		return true
+	}
+	return false
+}
@@ -1,9 +1,16 @@
 package store
 
 import (
+	"time"
+
+	"github.com/myntra/cortex/pkg/enrichment"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
 )
 
 // MessageType of the data entry
@@ -16,11 +23,39 @@ const (
 	ScriptType = 1
 	// RecordType denotes the executions.Record type
 	RecordType = 2
+	// IncidentType denotes the incidents.Incident type
+	IncidentType = 3
+	// RetentionType denotes the executions.Retention type
+	RetentionType = 4
+	// BucketType denotes the events.BucketSnapshot type
+	BucketType = 5
+	// SinkType denotes the sinks.GenericSink type
+	SinkType = 6
+	// SchemaType denotes the schemas.Schema type
+	SchemaType = 7
+	// TableType denotes the enrichment.Table type
+	TableType = 8
+	// PipelineType denotes the enrichment.Pipeline type
+	PipelineType = 9
+	// LateType denotes a rule id and its count of dropped late events
+	LateType = 10
+	// CheckpointType denotes an input id and its checkpoint
+	CheckpointType = 11
 )
 
 // Messages store entries to the underlying storage
 type Messages struct {
-	Rules   map[string]*rules.Rule        `json:"rules"`
-	Records map[string]*executions.Record `json:"records"`
-	Scripts map[string]*js.Script         `json:"script"`
+	Rules       map[string]*rules.Rule            `json:"rules"`
+	Scripts     map[string]*js.Script             `json:"script"`
+	Incidents   map[string]*incidents.Incident    `json:"incidents"`
+	Retention   *executions.Retention             `json:"retention"`
+	Buckets     map[string]*events.BucketSnapshot `json:"buckets"` // in-flight buckets
+	Late        map[string]int                    `json:"late"`    // dropped late events of the rules without a bucket
+	Sinks       map[string]*sinks.GenericSink     `json:"sinks"`
+	Schemas     map[string]*schemas.Schema        `json:"schemas"`
+	Tables      map[string]*enrichment.Table      `json:"tables"`
+	Pipeline    *enrichment.Pipeline              `json:"pipeline"`
+	Checkpoints map[string]time.Time              `json:"checkpoints"` // time the inputs have read up to
+	records     *recordsSnapshot                  // copy of the execution history, set for snapshots
+	restored    *recordsRestore                   // writer of the restored execution history, set for restores
 }
@@ -11,13 +11,17 @@ import (
 	"time"
 
 	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 
 	"github.com/golang/glog"
 	"github.com/hashicorp/raft"
 	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/enrichment"
 	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
 	"github.com/myntra/cortex/pkg/util"
 )
 
@@ -63,8 +67,23 @@ func (n *Node) Shutdown() error {
 	return nil
 }
 
+// IsLeader returns true if the node is the leader of the cluster
+func (n *Node) IsLeader() bool {
+	return n.store.raft.State() == raft.Leader
+}
+
 // LeaderAddr returns the http addr of the leader of the cluster. If empty, the current node is the leader
 func (n *Node) LeaderAddr() string {
+	return n.leaderAddr(1)
+}
+
+// LeaderGRPCAddr returns the grpc addr of the leader of the cluster. If empty, the current node is the leader
+func (n *Node) LeaderGRPCAddr() string {
+	return n.leaderAddr(2)
+}
+
+// leaderAddr returns the leader's raft host with the raft port incremented by the offset
+func (n *Node) leaderAddr(portOffset int) string {
 
 	if n.store.raft.State() == raft.Leader {
 		return ""
@@ -86,7 +105,7 @@ func (n *Node) LeaderAddr() string {
 		return ""
 	}
 
-	tcpPort := raftPort + 1
+	tcpPort := raftPort + portOffset
 	tcpURL := fields[0]
 	if tcpURL == "" {
 		tcpURL = "0.0.0.0"
@@ -115,8 +134,20 @@ func (n *Node) UpdateRule(rule *rules.Rule) error {
 	return n.store.updateRule(rule)
 }
 
-// Stash adds a event to the store
+// Stash adds a event to the store. An event failing the validation of its schemas is rejected with a
+// schemas.InvalidEventError, or tagged as invalid if the schema_validation mode is tag
 func (n *Node) Stash(event *events.Event) error {
+	_, err := n.store.matchAndStash(event)
+	return err
+}
+
+// IngestBatch adds the events to the buckets of the matching rules using batched raft applies
+func (n *Node) IngestBatch(batch []*events.Event) ([]*IngestResult, error) {
+	return n.store.matchAndStashBatch(batch)
+}
+
+// Ingest adds a event to the buckets of the matching rules and returns the match results once the raft applies finish
+func (n *Node) Ingest(event *events.Event) (*IngestResult, error) {
 	return n.store.matchAndStash(event)
 }
 
@@ -135,6 +166,71 @@ func (n *Node) GetRuleExectutions(ruleID string) []*executions.Record {
 	return n.store.getRecords(ruleID)
 }
 
+// GetExecutionPoolStats returns the queue depth and running executions of the execution worker pool
+func (n *Node) GetExecutionPoolStats() *ExecutionPoolStats {
+	return n.store.getExecutionPoolStats()
+}
+
+// GetBuckets returns the status of the open buckets
+func (n *Node) GetBuckets() []*BucketStatus {
+	return n.store.getBuckets()
+}
+
+// GetBucket returns the status of the rule's open bucket
+func (n *Node) GetBucket(ruleID string) *BucketStatus {
+	return n.store.getBucket(ruleID)
+}
+
+// FlushBucket executes the rule's open bucket on the next flush tick
+func (n *Node) FlushBucket(ruleID string) error {
+	return n.store.requestFlush(ruleID)
+}
+
+// DiscardBucket discards the rule's open bucket without executing it
+func (n *Node) DiscardBucket(ruleID string) error {
+	return n.store.discardBucket(ruleID)
+}
+
+// ExtendBucket pushes the flush of the rule's open bucket out by extension milliseconds
+func (n *Node) ExtendBucket(ruleID string, extension uint64) error {
+	if extension == 0 {
+		return fmt.Errorf("extension must be greater than 0")
+	}
+	return n.store.extendBucket(ruleID, extension)
+}
+
+// GetRetention returns the execution history retention
+func (n *Node) GetRetention() *executions.Retention {
+	return n.store.getRetention()
+}
+
+// SetRetention updates the execution history retention and expires the records exceeding it
+func (n *Node) SetRetention(retention *executions.Retention) error {
+	if err := retention.Validate(); err != nil {
+		return err
+	}
+	return n.store.setRetention(retention)
+}
+
+// GetCheckpoint returns the time the input has read up to, zero if the input has not set it yet
+func (n *Node) GetCheckpoint(inputID string) time.Time {
+	return n.store.getCheckpoint(inputID)
+}
+
+// SetCheckpoint replicates the time the input has read up to, so that the input resumes from it on a new leader.
+// Checkpoints only move forward
+func (n *Node) SetCheckpoint(inputID string, readUntil time.Time) error {
+	return n.store.setCheckpoint(inputID, readUntil)
+}
+
+// QueryRuleExecutions returns a page of the executions for a rule matching the query
+func (n *Node) QueryRuleExecutions(q *executions.Query) (*executions.Page, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return n.store.queryRecords(q)
+}
+
 // GetRules returns all the stored rules
 func (n *Node) GetRules() []*rules.Rule {
 	return n.store.getRules()
@@ -165,6 +261,131 @@ func (n *Node) GetScript(id string) *js.Script {
 	return n.store.getScript(id)
 }
 
+// AddSink adds a generic sink definition
+func (n *Node) AddSink(sink *sinks.GenericSink) error {
+	return n.store.addSink(sink)
+}
+
+// UpdateSink updates an already added sink definition
+func (n *Node) UpdateSink(sink *sinks.GenericSink) error {
+	return n.store.updateSink(sink)
+}
+
+// RemoveSink removes a sink definition
+func (n *Node) RemoveSink(id string) error {
+	return n.store.removeSink(id)
+}
+
+// GetSinks returns all the sink definitions sorted by id
+func (n *Node) GetSinks() []*sinks.GenericSink {
+	return n.store.getSinks()
+}
+
+// GetSink returns the sink definition with the id
+func (n *Node) GetSink(id string) *sinks.GenericSink {
+	return n.store.getSink(id)
+}
+
+// GetSinkByPath returns the sink definition served at the path
+func (n *Node) GetSinkByPath(path string) *sinks.GenericSink {
+	return n.store.getSinkByPath(path)
+}
+
+// AddSchema adds a json schema validating the events
+func (n *Node) AddSchema(schema *schemas.Schema) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+	return n.store.addSchema(schema)
+}
+
+// UpdateSchema updates an already added schema
+func (n *Node) UpdateSchema(schema *schemas.Schema) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+	return n.store.updateSchema(schema)
+}
+
+// RemoveSchema removes a schema
+func (n *Node) RemoveSchema(id string) error {
+	return n.store.removeSchema(id)
+}
+
+// GetSchemas returns all the schemas sorted by id
+func (n *Node) GetSchemas() []*schemas.Schema {
+	return n.store.getSchemas()
+}
+
+// GetSchema returns the schema with the id
+func (n *Node) GetSchema(id string) *schemas.Schema {
+	return n.store.getSchema(id)
+}
+
+// AddTable adds an enrichment lookup table
+func (n *Node) AddTable(table *enrichment.Table) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+	return n.store.addTable(table)
+}
+
+// UpdateTable replaces the rows of an already added table
+func (n *Node) UpdateTable(table *enrichment.Table) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+	return n.store.updateTable(table)
+}
+
+// RemoveTable removes a table not used by the enrichment pipeline
+func (n *Node) RemoveTable(id string) error {
+	return n.store.removeTable(id)
+}
+
+// GetTables returns all the tables sorted by id
+func (n *Node) GetTables() []*enrichment.Table {
+	return n.store.getTables()
+}
+
+// GetTable returns the table with the id
+func (n *Node) GetTable(id string) *enrichment.Table {
+	return n.store.getTable(id)
+}
+
+// SetPipeline replaces the enrichment steps applied to the events before they are matched
+func (n *Node) SetPipeline(pipeline *enrichment.Pipeline) error {
+	if err := pipeline.Validate(); err != nil {
+		return err
+	}
+	return n.store.setPipeline(pipeline)
+}
+
+// GetPipeline returns the enrichment steps
+func (n *Node) GetPipeline() *enrichment.Pipeline {
+	return n.store.getPipeline()
+}
+
+// GetIncidents returns the incidents with the status. an empty status returns all incidents
+func (n *Node) GetIncidents(status string) []*incidents.Incident {
+	return n.store.getIncidents(status)
+}
+
+// GetIncident returns the incident
+func (n *Node) GetIncident(id string) *incidents.Incident {
+	return n.store.getIncident(id)
+}
+
+// AcknowledgeIncident acknowledges an open incident
+func (n *Node) AcknowledgeIncident(id string) error {
+	return n.store.acknowledgeIncident(id)
+}
+
+// ResolveIncident resolves an incident and notifies the rule's incident hook endpoint
+func (n *Node) ResolveIncident(id string) error {
+	return n.store.resolveIncident(id)
+}
+
 // Join a remote node at the addr
 func (n *Node) Join(nodeID, addr string) error {
 	return n.store.acceptJoin(nodeID, addr)
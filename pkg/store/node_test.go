@@ -20,6 +20,8 @@ import (
 
 	"github.com/myntra/cortex/pkg/config"
 	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/js"
 	"github.com/myntra/cortex/pkg/rules"
 )
@@ -81,7 +83,7 @@ func newTestRule(key string) rules.Rule {
 	}
 }
 
-func singleNode(t *testing.T, httpAddr, raftAddr string, f func(node *Node)) {
+func singleNode(t testing.TB, httpAddr, raftAddr string, f func(node *Node)) {
 
 	tmpDir, _ := ioutil.TempDir("", "store_test")
 	defer os.RemoveAll(tmpDir)
@@ -191,20 +193,11 @@ func TestOrphanEventSingleNode(t *testing.T) {
 		err := node.Stash(&testevent)
 		require.NoError(t, err)
 
-		var rb *events.Bucket
-	loop:
-		for {
-			select {
-			case rb = <-node.store.executionBucketQueue:
-				fmt.Println("rb=>", rb)
+		time.Sleep(time.Millisecond * time.Duration(node.store.opt.DefaultDwell+1000))
 
-			case <-time.After(time.Millisecond * time.Duration(node.store.opt.DefaultDwell+1000)):
-				break loop
-			}
-
-		}
-
-		require.Nil(t, rb)
+		stats := node.GetExecutionPoolStats()
+		require.Zero(t, stats.Executed)
+		require.Zero(t, stats.Queued)
 	})
 }
 
@@ -441,3 +434,347 @@ func TestNodeSnapshot(t *testing.T) {
 	err = httpListener.Close()
 	require.NoError(t, err)
 }
+
+func TestIncidentSingleNode(t *testing.T) {
+	raftAddr := ":29878"
+	httpAddr := ":29879"
+	singleNode(t, httpAddr, raftAddr, func(node *Node) {
+		incidentRule := rules.Rule{
+			ID:                "incident-rule-id-1",
+			EventTypePatterns: []string{"acme.prod.icinga.check_disk"},
+			ResolvePatterns:   []string{"acme.prod.icinga.check_disk_ok"},
+			GroupKey:          "source",
+			Dwell:             1000,
+			DwellDeadline:     800,
+			MaxDwell:          2000,
+		}
+
+		err := node.AddRule(&incidentRule)
+		require.NoError(t, err)
+		err = node.Stash(&testevent)
+		require.NoError(t, err)
+
+		var openIncidents []*incidents.Incident
+		operation := func() error {
+			openIncidents = node.GetIncidents(incidents.StatusOpen)
+			if len(openIncidents) == 0 {
+				return fmt.Errorf("no open incidents")
+			}
+			return nil
+		}
+
+		err = backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 10))
+		require.NoError(t, err)
+		require.Len(t, openIncidents, 1)
+		require.Equal(t, incidentRule.ID, openIncidents[0].RuleID)
+		require.Equal(t, testevent.Source, openIncidents[0].GroupKey)
+		require.Len(t, openIncidents[0].RecordIDs, 1)
+
+		// a later execution updates the same incident
+		updateEvent := newTestEvent("update", "")
+		err = node.Stash(&updateEvent)
+		require.NoError(t, err)
+
+		operation = func() error {
+			incident := node.GetIncident(openIncidents[0].ID)
+			if len(incident.RecordIDs) != 2 {
+				return fmt.Errorf("incident not updated")
+			}
+			return nil
+		}
+
+		err = backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 10))
+		require.NoError(t, err)
+		require.Len(t, node.GetIncidents(""), 1)
+
+		// a matching resolve event resolves it
+		resolveEvent := newTestEvent("resolve", "")
+		resolveEvent.EventType = "acme.prod.icinga.check_disk_ok"
+		err = node.Stash(&resolveEvent)
+		require.NoError(t, err)
+
+		operation = func() error {
+			incident := node.GetIncident(openIncidents[0].ID)
+			if incident.Status != incidents.StatusResolved {
+				return fmt.Errorf("incident not resolved")
+			}
+			return nil
+		}
+
+		err = backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5))
+		require.NoError(t, err)
+		require.Equal(t, resolveEvent.EventID, node.GetIncident(openIncidents[0].ID).ResolvedBy)
+
+		err = node.ResolveIncident(openIncidents[0].ID)
+		require.Error(t, err)
+	})
+}
+
+func TestRetentionSingleNode(t *testing.T) {
+	raftAddr := ":31878"
+	httpAddr := ":31879"
+	singleNode(t, httpAddr, raftAddr, func(node *Node) {
+		require.Equal(t, node.store.opt.MaxHistory, node.GetRetention().MaxRecords)
+
+		for i := 0; i < 5; i++ {
+			err := node.store.addRecord(&executions.Record{
+				ID:        "record" + strconv.Itoa(i),
+				Bucket:    events.Bucket{Rule: testRule},
+				CreatedAt: time.Now().Add(time.Duration(i) * time.Second),
+			})
+			require.NoError(t, err)
+		}
+
+		require.Len(t, node.GetRuleExectutions(testRule.ID), 5)
+
+		err := node.SetRetention(&executions.Retention{MaxRecordsPerRule: 2})
+		require.NoError(t, err)
+
+		records := node.GetRuleExectutions(testRule.ID)
+		require.Len(t, records, 2)
+		for _, record := range records {
+			require.Contains(t, []string{"record3", "record4"}, record.ID)
+		}
+
+		require.Equal(t, 2, node.GetRetention().MaxRecordsPerRule)
+		require.Error(t, node.SetRetention(&executions.Retention{MaxRecords: -1}))
+	})
+}
+
+func TestBucketControlSingleNode(t *testing.T) {
+	raftAddr := ":33878"
+	httpAddr := ":33879"
+	singleNode(t, httpAddr, raftAddr, func(node *Node) {
+		bucketRule := rules.Rule{
+			ID:                "bucket-rule-id-1",
+			EventTypePatterns: []string{"acme.prod.icinga.check_disk"},
+			Dwell:             60000,
+			DwellDeadline:     50000,
+			MaxDwell:          120000,
+		}
+
+		err := node.AddRule(&bucketRule)
+		require.NoError(t, err)
+
+		stash := func(id string) *BucketStatus {
+			event := newTestEvent(id, "")
+			require.NoError(t, node.Stash(&event))
+
+			var bucket *BucketStatus
+			operation := func() error {
+				bucket = node.GetBucket(bucketRule.ID)
+				if bucket == nil {
+					return fmt.Errorf("bucket not found")
+				}
+				return nil
+			}
+
+			err := backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Millisecond*100), 50))
+			require.NoError(t, err)
+			return bucket
+		}
+
+		bucket := stash("dwelling")
+		require.Equal(t, 1, bucket.Events)
+		require.Len(t, node.GetBuckets(), 1)
+		require.True(t, bucket.CanFlushIn > 0)
+
+		err = node.ExtendBucket(bucketRule.ID, 30000)
+		require.NoError(t, err)
+		extended := node.GetBucket(bucketRule.ID)
+		require.Equal(t, uint64(30000), extended.Extension)
+		require.Equal(t, bucket.FlushAt.Add(30*time.Second), extended.FlushAt)
+
+		err = node.DiscardBucket(bucketRule.ID)
+		require.NoError(t, err)
+		require.Nil(t, node.GetBucket(bucketRule.ID))
+		require.Error(t, node.DiscardBucket(bucketRule.ID))
+		require.Error(t, node.ExtendBucket(bucketRule.ID, 30000))
+
+		// flush executes the bucket long before the dwell
+		stash("flushed")
+		err = node.FlushBucket(bucketRule.ID)
+		require.NoError(t, err)
+
+		operation := func() error {
+			if len(node.GetRuleExectutions(bucketRule.ID)) == 0 {
+				return fmt.Errorf("bucket not executed")
+			}
+			return nil
+		}
+
+		err = backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 10))
+		require.NoError(t, err)
+		require.Nil(t, node.GetBucket(bucketRule.ID))
+	})
+}
+
+func TestIngestSingleNode(t *testing.T) {
+	raftAddr := ":34878"
+	httpAddr := ":34879"
+	singleNode(t, httpAddr, raftAddr, func(node *Node) {
+		ingestRule := rules.Rule{
+			ID:                "ingest-rule-id-1",
+			EventTypePatterns: []string{"acme.prod.icinga.*"},
+			Dwell:             60000,
+			DwellDeadline:     50000,
+			MaxDwell:          120000,
+		}
+
+		err := node.AddRule(&ingestRule)
+		require.NoError(t, err)
+
+		event := newTestEvent("ingest", "")
+		result, err := node.Ingest(&event)
+		require.NoError(t, err)
+		require.Equal(t, event.EventID, result.EventID)
+		require.Equal(t, []string{ingestRule.ID}, result.MatchedRules)
+		require.Len(t, result.Buckets, 1)
+		require.True(t, result.Buckets[0].Joined)
+		require.True(t, result.Buckets[0].NewBucket)
+		require.False(t, result.Buckets[0].Deduplicated)
+
+		// the bucket is stored once ingest returns
+		require.Equal(t, 1, node.GetBucket(ingestRule.ID).Events)
+
+		result, err = node.Ingest(&event)
+		require.NoError(t, err)
+		require.False(t, result.Buckets[0].Joined)
+		require.True(t, result.Buckets[0].Deduplicated)
+
+		unmatched := newTestEvent("unmatched", "apple.")
+		result, err = node.Ingest(&unmatched)
+		require.NoError(t, err)
+		require.Empty(t, result.MatchedRules)
+		require.Empty(t, result.Buckets)
+
+		// events are normalized by the store, whichever api or input stashes them
+		icinga := newTestEvent("icinga", "")
+		icinga.Source = "icinga"
+		icinga.Data = map[string]interface{}{"ServiceState": "CRITICAL", "NotificationType": "PROBLEM", "HostDisplayName": "web-1"}
+		require.NoError(t, node.Stash(&icinga))
+		normalized := icinga.Extensions.(map[string]interface{})[sinks.NormalizedExtension].(map[string]interface{})
+		require.Equal(t, sinks.SeverityCritical, normalized["severity"])
+		require.Equal(t, sinks.StatusFiring, normalized["status"])
+		require.Equal(t, "web-1", normalized["resource"])
+	})
+}
+
+func TestIngestBatchSingleNode(t *testing.T) {
+	raftAddr := ":36878"
+	httpAddr := ":36879"
+	singleNode(t, httpAddr, raftAddr, func(node *Node) {
+		batchRule := rules.Rule{
+			ID:                "batch-rule-id-1",
+			EventTypePatterns: []string{"acme.prod.icinga.*"},
+			Dwell:             60000,
+			DwellDeadline:     50000,
+			MaxDwell:          120000,
+		}
+
+		err := node.AddRule(&batchRule)
+		require.NoError(t, err)
+
+		first := newTestEvent("first", "")
+		second := newTestEvent("second", "")
+		unmatched := newTestEvent("unmatched", "apple.")
+
+		// the duplicate is deduplicated against the first event of the same batch
+		results, err := node.IngestBatch([]*events.Event{&first, &second, &unmatched, &first})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		require.Equal(t, []string{batchRule.ID}, results[0].MatchedRules)
+		require.True(t, results[0].Buckets[0].NewBucket)
+		require.True(t, results[1].Buckets[0].Joined)
+		require.Empty(t, results[2].Buckets)
+		require.True(t, results[3].Buckets[0].Deduplicated)
+		for _, result := range results {
+			require.Empty(t, result.Error)
+		}
+
+		require.Equal(t, 2, node.GetBucket(batchRule.ID).Events)
+	})
+}
+
+func TestFailUnapplied(t *testing.T) {
+	results := []*IngestResult{{EventID: "1"}, {EventID: "2"}, {EventID: "3"}}
+	owners := []int{0, 1, 1, 2} // the second event matched two rules
+
+	// the batch command of the first three stashes committed, the second event is partly stashed
+	failUnapplied(results, owners, 2, errNotLeader)
+	require.Empty(t, results[0].Error)
+	require.Equal(t, errNotLeader.Error(), results[1].Error)
+	require.Equal(t, errNotLeader.Error(), results[2].Error)
+}
+
+func benchmarkEvents(n int) []*events.Event {
+	batch := make([]*events.Event, n)
+	for i := range batch {
+		event := newTestEvent(fmt.Sprintf("bench%d", i), "")
+		batch[i] = &event
+	}
+	return batch
+}
+
+var benchmarkRule = rules.Rule{
+	ID:                "bench-rule-id-1",
+	EventTypePatterns: []string{"acme.prod.icinga.*"},
+	Dwell:             600000,
+	DwellDeadline:     500000,
+	MaxDwell:          1200000,
+}
+
+// benchmarkBucketSize is the number of events stashed before the benchmarks discard the bucket, so that the
+// deduplication against the bucket's events doesn't dominate the stash cost as b.N grows
+const benchmarkBucketSize = 100
+
+func discardBenchmarkBucket(b *testing.B, node *Node) {
+	b.StopTimer()
+	if err := node.DiscardBucket(benchmarkRule.ID); err != nil {
+		b.Fatal(err)
+	}
+	b.StartTimer()
+}
+
+// BenchmarkIngest stashes an event per raft apply
+func BenchmarkIngest(b *testing.B) {
+	singleNode(b, ":37879", ":37878", func(node *Node) {
+		rule := benchmarkRule
+		require.NoError(b, node.AddRule(&rule))
+		batch := benchmarkEvents(b.N)
+
+		b.ResetTimer()
+		for i, event := range batch {
+			if _, err := node.Ingest(event); err != nil {
+				b.Fatal(err)
+			}
+			if (i+1)%benchmarkBucketSize == 0 {
+				discardBenchmarkBucket(b, node)
+			}
+		}
+	})
+}
+
+// BenchmarkIngestBatch stashes batches of 100 events per raft apply
+func BenchmarkIngestBatch(b *testing.B) {
+	singleNode(b, ":38879", ":38878", func(node *Node) {
+		rule := benchmarkRule
+		require.NoError(b, node.AddRule(&rule))
+		batch := benchmarkEvents(b.N)
+
+		b.ResetTimer()
+		for start := 0; start < len(batch); start += benchmarkBucketSize {
+			end := start + benchmarkBucketSize
+			if end > len(batch) {
+				end = len(batch)
+			}
+			if _, err := node.IngestBatch(batch[start:end]); err != nil {
+				b.Fatal(err)
+			}
+			if end-start == benchmarkBucketSize {
+				discardBenchmarkBucket(b, node)
+			}
+		}
+	})
+}
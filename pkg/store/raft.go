@@ -76,6 +76,12 @@ func (d *defaultStore) open() error {
 	stableStore = boltDB
 
 	glog.Info("created boltdb store \n")
+
+	err = d.executionStorage.open(filepath.Join(d.opt.Dir, "executions.db"))
+	if err != nil {
+		return fmt.Errorf("execution history store: %s", err)
+	}
+
 	// Instantiate the Raft systemd.
 	ra, err := raft.NewRaft(config, (*fsm)(d), logStore, stableStore, snapshots, transport)
 	if err != nil {
@@ -120,6 +126,8 @@ func (d *defaultStore) open() error {
 	}
 
 	go d.flusher()
+	go d.expirer()
+	go d.resolver()
 
 	return nil
 }
@@ -131,6 +139,8 @@ func (d *defaultStore) snapshot() error {
 
 func (d *defaultStore) close() error {
 	d.quitFlusherChan <- struct{}{}
+	d.quitExpirerChan <- struct{}{}
+	d.quitResolverChan <- struct{}{}
 	f := d.raft.Shutdown()
 	if f.Error() != nil {
 		return f.Error()
@@ -141,6 +151,11 @@ func (d *defaultStore) close() error {
 		d.boltDB.Close()
 	}
 
+	// close the execution history
+	if err := d.executionStorage.close(); err != nil {
+		return err
+	}
+
 	glog.Info("raft shut down")
 	glog.Flush()
 	return nil
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/schemas"
+)
+
+type schemaStorage struct {
+	mu sync.RWMutex
+	m  map[string]*schemas.Schema
+}
+
+// urlTaken returns true if another schema already has the url
+func (s *schemaStorage) urlTaken(schema *schemas.Schema) bool {
+	if schema.URL == "" {
+		return false
+	}
+
+	for id, v := range s.m {
+		if id != schema.ID && v.URL == schema.URL {
+			return true
+		}
+	}
+	return false
+}
+
+// compileSchema compiles the schema without loading its remote $refs, the raft applies must not depend on the network.
+// a schema which doesn't compile is kept and compiled with its remote $refs by the first event it validates.
+func compileSchema(schema *schemas.Schema) {
+	if err := schema.Compile(); err != nil {
+		glog.Errorf("schema %v does not compile %v", schema.ID, err)
+	}
+}
+
+func (s *schemaStorage) addSchema(schema *schemas.Schema) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.m[schema.ID]; ok {
+		return fmt.Errorf("schema id already exists. schema id must be unique")
+	}
+
+	if s.urlTaken(schema) {
+		return fmt.Errorf("schema url %s already exists. schema url must be unique", schema.URL)
+	}
+
+	compileSchema(schema)
+	s.m[schema.ID] = schema
+
+	return nil
+}
+
+func (s *schemaStorage) updateSchema(schema *schemas.Schema) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.m[schema.ID]; !ok {
+		return fmt.Errorf("schema id not found. can't update")
+	}
+
+	if s.urlTaken(schema) {
+		return fmt.Errorf("schema url %s already exists. schema url must be unique", schema.URL)
+	}
+
+	compileSchema(schema)
+	s.m[schema.ID] = schema
+	return nil
+}
+
+func (s *schemaStorage) removeSchema(id string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.m[id]; !ok {
+		return fmt.Errorf("schema id not found. can't remove")
+	}
+
+	delete(s.m, id)
+
+	return nil
+}
+
+func (s *schemaStorage) getSchema(id string) *schemas.Schema {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m[id]
+}
+
+func (s *schemaStorage) getSchemas() []*schemas.Schema {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]*schemas.Schema, 0, len(s.m))
+	for _, schema := range s.m {
+		list = append(list, schema)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	return list
+}
+
+// match returns the schemas validating the event: the schema with the event's schemaURL if there is one, else the
+// schemas whose event type patterns match
+func (s *schemaStorage) match(event *events.Event) []*schemas.Schema {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if event.SchemaURL != "" {
+		for _, schema := range s.m {
+			if schema.URL == event.SchemaURL {
+				return []*schemas.Schema{schema}
+			}
+		}
+	}
+
+	var matched []*schemas.Schema
+	for _, schema := range s.m {
+		if schema.HasMatching(event.EventType) {
+			matched = append(matched, schema)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+
+	return matched
+}
+
+func (s *schemaStorage) clone() map[string]*schemas.Schema {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m := make(map[string]*schemas.Schema)
+	for k, v := range s.m {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *schemaStorage) restore(m map[string]*schemas.Schema) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m = m
+	for _, schema := range m {
+		compileSchema(schema)
+	}
+}
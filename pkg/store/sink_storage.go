@@ -0,0 +1,144 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/myntra/cortex/pkg/events/sinks"
+)
+
+type sinkStorage struct {
+	mu    sync.RWMutex
+	m     map[string]*sinks.GenericSink
+	paths map[string]string // [path]sinkID
+}
+
+// pathTaken returns true if another sink is already served at the path
+func (s *sinkStorage) pathTaken(sink *sinks.GenericSink) bool {
+	id, ok := s.paths[sink.Path]
+	return ok && id != sink.ID
+}
+
+// compileSink compiles the sink's expressions once, so that decoding a payload doesn't compile them again. a sink
+// which doesn't compile is kept and fails its payloads with the compile error.
+func compileSink(sink *sinks.GenericSink) {
+	if err := sink.Validate(); err != nil {
+		glog.Errorf("sink %v does not compile %v", sink.ID, err)
+	}
+}
+
+func (s *sinkStorage) addSink(sink *sinks.GenericSink) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.m[sink.ID]; ok {
+		return fmt.Errorf("sink id already exists. sink id must be unique")
+	}
+
+	if s.pathTaken(sink) {
+		return fmt.Errorf("sink path %s already exists. sink path must be unique", sink.Path)
+	}
+
+	compileSink(sink)
+	s.m[sink.ID] = sink
+	s.paths[sink.Path] = sink.ID
+
+	return nil
+}
+
+func (s *sinkStorage) updateSink(sink *sinks.GenericSink) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.m[sink.ID]
+	if !ok {
+		return fmt.Errorf("sink id not found. can't update")
+	}
+
+	if s.pathTaken(sink) {
+		return fmt.Errorf("sink path %s already exists. sink path must be unique", sink.Path)
+	}
+
+	compileSink(sink)
+	delete(s.paths, old.Path)
+	s.m[sink.ID] = sink
+	s.paths[sink.Path] = sink.ID
+	return nil
+}
+
+func (s *sinkStorage) removeSink(id string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sink, ok := s.m[id]
+	if !ok {
+		return fmt.Errorf("sink id not found. can't remove")
+	}
+
+	delete(s.m, id)
+	delete(s.paths, sink.Path)
+
+	return nil
+}
+
+func (s *sinkStorage) getSink(id string) *sinks.GenericSink {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m[id]
+}
+
+func (s *sinkStorage) getSinkByPath(path string) *sinks.GenericSink {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.paths[path]
+	if !ok {
+		return nil
+	}
+
+	return s.m[id]
+}
+
+func (s *sinkStorage) getSinks() []*sinks.GenericSink {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]*sinks.GenericSink, 0, len(s.m))
+	for _, sink := range s.m {
+		list = append(list, sink)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	return list
+}
+
+func (s *sinkStorage) clone() map[string]*sinks.GenericSink {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m := make(map[string]*sinks.GenericSink)
+	for k, v := range s.m {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *sinkStorage) restore(m map[string]*sinks.GenericSink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m = m
+	s.paths = make(map[string]string)
+	for id, sink := range m {
+		compileSink(sink)
+		s.paths[sink.Path] = id
+	}
+}
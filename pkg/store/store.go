@@ -1,7 +1,10 @@
 package store
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/raft-boltdb"
@@ -10,9 +13,13 @@ import (
 	"github.com/golang/glog"
 	"github.com/hashicorp/raft"
 	"github.com/myntra/cortex/pkg/config"
+	"github.com/myntra/cortex/pkg/enrichment"
 	"github.com/myntra/cortex/pkg/events"
+	"github.com/myntra/cortex/pkg/events/sinks"
 	"github.com/myntra/cortex/pkg/executions"
+	"github.com/myntra/cortex/pkg/incidents"
 	"github.com/myntra/cortex/pkg/rules"
+	"github.com/myntra/cortex/pkg/schemas"
 
 	"net/url"
 
@@ -23,39 +30,105 @@ import (
 const (
 	retainSnapshotCount = 2
 	raftTimeout         = 10 * time.Second
+
+	// DefaultExecutionWorkers is the number of workers executing the flushed buckets when none is configured
+	DefaultExecutionWorkers = 10
+	// DefaultExecutionQueueSize is the number of flushed buckets waiting for a worker when none is configured
+	DefaultExecutionQueueSize = 1000
+
+	// DefaultMaxResolvedIncidents is the number of resolved incidents kept when none is configured
+	DefaultMaxResolvedIncidents = 1000
+
+	defaultExpireInterval = 60  // minutes
+	expireBatchSize       = 100 // records removed per remove_record command
+
+	maxBatchCommands = 1000 // stash commands per batch command
+	resolveQueueSize = 1000 // incidents waiting for the resolver
 )
 
+var errNotLeader = errors.New("not leader")
+
 type defaultStore struct {
-	opt                  *config.Config
-	boltDB               *raftboltdb.BoltStore
-	raft                 *raft.Raft
-	scriptStorage        *scriptStorage
-	bucketStorage        *bucketStorage
-	executionStorage     *executionStorage
-	executionBucketQueue chan *events.Bucket
-	quitFlusherChan      chan struct{}
-	persisters           []persister
-	restorers            map[MessageType]restorer
+	opt               *config.Config
+	boltDB            *raftboltdb.BoltStore
+	raft              *raft.Raft
+	scriptStorage     *scriptStorage
+	sinkStorage       *sinkStorage
+	schemaStorage     *schemaStorage
+	enrichmentStorage *enrichmentStorage
+	normalizer        *sinks.Normalizer
+	bucketStorage     *bucketStorage
+	executionStorage  *executionStorage
+	incidentStorage   *incidentStorage
+	checkpointStorage *checkpointStorage
+	executionPool     *executionPool
+	pendingMu         sync.Mutex
+	pending           []*events.Bucket // buckets waiting for room in the execution queue
+	quitFlusherChan   chan struct{}
+	quitExpirerChan   chan struct{}
+	quitResolverChan  chan struct{}
+	resolveQueue      chan resolveTask // incidents resolved by the resolver
+	persisters        []persister
+	restorers         map[MessageType]restorer
+	clock             func() time.Time // stamps stash commands and drives the flusher
 }
 
 func newStore(opt *config.Config) (*defaultStore, error) {
 
+	var mappings map[string]*sinks.NormalizeMapping
+	if opt.NormalizeMappings != "" {
+		loaded, err := sinks.LoadNormalizeMappings(opt.NormalizeMappings)
+		if err != nil {
+			return nil, err
+		}
+		mappings = loaded
+	}
+
+	normalizer, err := sinks.NewNormalizer(mappings)
+	if err != nil {
+		return nil, err
+	}
+
 	// register persisters
 	var persisters []persister
-	persisters = append(persisters, persistRules, persistRecords, persistScripts)
+	persisters = append(persisters, persistRules, persistRecords, persistScripts, persistIncidents, persistRetention, persistBuckets, persistLate, persistSinks, persistSchemas, persistTables, persistPipeline, persistCheckpoints)
 
 	restorers := make(map[MessageType]restorer)
 
 	restorers[RuleType] = restoreRules
 	restorers[RecordType] = restoreRecords
 	restorers[ScriptType] = restoreScripts
+	restorers[IncidentType] = restoreIncidents
+	restorers[RetentionType] = restoreRetention
+	restorers[BucketType] = restoreBuckets
+	restorers[LateType] = restoreLate
+	restorers[SinkType] = restoreSinks
+	restorers[SchemaType] = restoreSchemas
+	restorers[TableType] = restoreTables
+	restorers[PipelineType] = restorePipeline
+	restorers[CheckpointType] = restoreCheckpoints
 
 	store := &defaultStore{
 		scriptStorage: &scriptStorage{
 			m: make(map[string]*js.Script),
 		},
-		executionStorage: &executionStorage{
-			m: make(map[string]*executions.Record),
+		sinkStorage: &sinkStorage{
+			m:     make(map[string]*sinks.GenericSink),
+			paths: make(map[string]string),
+		},
+		schemaStorage: &schemaStorage{
+			m: make(map[string]*schemas.Schema),
+		},
+		enrichmentStorage: &enrichmentStorage{
+			m: make(map[string]*enrichment.Table),
+		},
+		executionStorage: &executionStorage{},
+		checkpointStorage: &checkpointStorage{
+			m: make(map[string]time.Time),
+		},
+		incidentStorage: &incidentStorage{
+			m:       make(map[string]*incidents.Incident),
+			actives: make(map[string]string),
 		},
 		bucketStorage: &bucketStorage{
 			es: &eventStorage{
@@ -65,59 +138,84 @@ func newStore(opt *config.Config) (*defaultStore, error) {
 				m: make(map[string]*rules.Rule),
 			},
 		},
-		opt:                  opt,
-		quitFlusherChan:      make(chan struct{}),
-		executionBucketQueue: make(chan *events.Bucket, 1000),
-		persisters:           persisters,
-		restorers:            restorers,
+		opt:              opt,
+		quitFlusherChan:  make(chan struct{}),
+		quitExpirerChan:  make(chan struct{}),
+		quitResolverChan: make(chan struct{}),
+		resolveQueue:     make(chan resolveTask, resolveQueueSize),
+		persisters:       persisters,
+		restorers:        restorers,
+		normalizer:       normalizer,
+		clock:            time.Now,
 	}
 
+	workers := opt.ExecutionWorkers
+	if workers == 0 {
+		workers = DefaultExecutionWorkers
+	}
+
+	queueSize := opt.ExecutionQueueSize
+	if queueSize == 0 {
+		queueSize = DefaultExecutionQueueSize
+	}
+
+	store.executionPool = newExecutionPool(workers, queueSize, store.execute)
+
 	return store, nil
 }
 
-func (d *defaultStore) executor() {
-	for {
-		select {
-		case rb := <-d.executionBucketQueue:
-			glog.Infof("received bucket %+v\n", rb)
-			go func(rb *events.Bucket) {
-				statusCode := 0
-				var noScriptResult bool
-				result := js.Execute(d.getScript(rb.Rule.ScriptID), rb)
-				glog.Infof("Result of the script execution \n%v", result)
-				if result == nil {
-					noScriptResult = true
-				}
-				if _, err := url.ParseRequestURI(rb.Rule.HookEndpoint); err != nil {
-					glog.Infoln("Invalid HookEndpoint. Skipping post request")
-				} else {
-					if noScriptResult {
-						statusCode = util.RetryPost(rb, rb.Rule.HookEndpoint, rb.Rule.HookRetry)
-					} else {
-						statusCode = util.RetryPost(result, rb.Rule.HookEndpoint, rb.Rule.HookRetry)
-					}
-				}
+// execute runs the rule script on the bucket, posts the result to the hook endpoint and records the execution
+func (d *defaultStore) execute(rb *events.Bucket) {
+	glog.Infof("received bucket %+v\n", rb)
+	statusCode := 0
+	var noScriptResult bool
+	result := js.Execute(d.getScript(rb.Rule.ScriptID), rb)
+	glog.Infof("Result of the script execution \n%v", result)
+	if result == nil {
+		noScriptResult = true
+	}
+	if _, err := url.ParseRequestURI(rb.Rule.HookEndpoint); err != nil {
+		glog.Infoln("Invalid HookEndpoint. Skipping post request")
+	} else {
+		if noScriptResult {
+			statusCode = util.RetryPost(rb, rb.Rule.HookEndpoint, rb.Rule.HookRetry)
+		} else {
+			statusCode = util.RetryPost(result, rb.Rule.HookEndpoint, rb.Rule.HookRetry)
+		}
+	}
 
-				id := uuid.NewV4()
-				record := &executions.Record{
-					ID:             id.String(),
-					Bucket:         *rb,
-					ScriptResult:   result,
-					HookStatusCode: statusCode,
-					CreatedAt:      time.Now(),
-				}
+	scriptStatus := executions.ScriptStatusOK
+	if noScriptResult {
+		scriptStatus = executions.ScriptStatusNone
+	} else if err, ok := result.(error); ok {
+		scriptStatus = executions.ScriptStatusError
+		result = err.Error()
+	}
 
-				glog.Infof("addRecord %v\n", record)
-				glog.Infoln("err => ", d.addRecord(record))
+	id := uuid.NewV4()
+	record := &executions.Record{
+		ID:             id.String(),
+		Bucket:         *rb,
+		ScriptResult:   result,
+		ScriptStatus:   scriptStatus,
+		HookStatusCode: statusCode,
+		LateEvents:     rb.LateEvents,
+		CreatedAt:      time.Now(),
+	}
 
-			}(rb)
-		}
+	glog.Infof("addRecord %v\n", record)
+	glog.Infoln("err => ", d.addRecord(record))
+
+	// a bucket can hold the events of several group keys, e.g. hosts, each has its own incident
+	for _, groupKey := range incidents.GroupKeys(rb.Rule.GroupKey, rb.Events) {
+		glog.Infoln("upsert incident err => ", d.upsertIncident(&rb.Rule, groupKey, record.ID))
 	}
 }
 
 func (d *defaultStore) flusher() {
 
-	go d.executor()
+	d.executionPool.start()
+	defer d.executionPool.stop()
 
 	ticker := time.NewTicker(time.Millisecond * time.Duration(d.opt.FlushInterval))
 loop:
@@ -131,11 +229,14 @@ loop:
 
 			glog.Infof("rule flusher started ===============================> \n")
 
+			d.executePending()
+
+			now := d.clock()
 			for ruleID, bucket := range d.bucketStorage.es.clone() {
 				glog.Infof("rule flusher ==> %v with size %v canflush ? %v, can flush in %v, has flush lock ? %v",
-					ruleID, len(bucket.Events), bucket.CanFlush(), bucket.CanFlushIn(), bucket.FlushLock)
+					ruleID, len(bucket.Events), bucket.CanFlushAt(now), bucket.FlushAt().Sub(now), bucket.FlushLock)
 
-				if bucket.CanFlush() && !bucket.FlushLock {
+				if bucket.CanFlushAt(now) && !bucket.FlushLock {
 					go func(currRuleID string) {
 						err := d.flushLock(currRuleID)
 						if err != nil {
@@ -147,15 +248,23 @@ loop:
 				}
 
 				if bucket.FlushLock {
-					go func(currRuleID string, currBucket *events.Bucket) {
-						glog.Infof("post bucket to execution %+v\n", currBucket.Rule.ID)
-						d.executionBucketQueue <- currBucket
+					// backpressure: keep the bucket in the store until the execution queue has room. the slot is
+					// reserved before the flush removes the bucket from the store
+					if !d.executionPool.reserve() {
+						glog.Errorf("execution queue is full, deferring flush of bucket %v", ruleID)
+						continue
+					}
 
-						err := d.flushBucket(currRuleID)
-						if err != nil {
-							glog.Errorf("error flushing bucket %v %v\n", currRuleID, err)
-						}
-					}(ruleID, bucket)
+					// tumbling and hopping buckets move to the next window on flush, execute the flushed window
+					window, err := d.flushBucket(ruleID)
+					if err != nil {
+						d.executionPool.release()
+						glog.Errorf("error flushing bucket %v %v\n", ruleID, err)
+						continue
+					}
+
+					glog.Infof("post bucket to execution %+v\n", bucket.Rule.ID)
+					d.executionPool.pushReserved(window)
 				}
 			}
 
@@ -169,7 +278,13 @@ loop:
 }
 
 func (d *defaultStore) expirer() {
-	ticker := time.NewTicker(time.Hour)
+	interval := d.opt.ExpireInterval
+	if interval == 0 {
+		interval = defaultExpireInterval
+	}
+
+	ticker := time.NewTicker(time.Minute * time.Duration(interval))
+	defer ticker.Stop()
 
 	for {
 		select {
@@ -178,17 +293,103 @@ func (d *defaultStore) expirer() {
 				glog.Info("node is not leader, skipping expire")
 				continue
 			}
-			if d.executionStorage.getTotalRecordsCount() > d.opt.MaxHistory {
-				// TODO, remove oldest records
+
+			if err := d.expire(); err != nil {
+				glog.Errorf("error expiring records %v", err)
+			}
+
+			if err := d.expireIncidents(); err != nil {
+				glog.Errorf("error expiring incidents %v", err)
 			}
+
+		case <-d.quitExpirerChan:
+			return
+		}
+
+	}
+}
+
+// expire removes the records exceeding the retention in batches of remove_record commands
+func (d *defaultStore) expire() error {
+	ids := d.getRetention().Expired(d.executionStorage.index(), time.Now())
+	glog.Infof("expiring %v records", len(ids))
+
+	for len(ids) > 0 {
+		n := expireBatchSize
+		if len(ids) < n {
+			n = len(ids)
+		}
+
+		if err := d.removeRecords(ids[:n]); err != nil {
+			return err
+		}
+
+		ids = ids[n:]
+	}
+
+	return nil
+}
+
+// expireIncidents removes the resolved incidents exceeding the configured count or age in batches of
+// remove_incidents commands
+func (d *defaultStore) expireIncidents() error {
+	maxResolved := d.opt.MaxResolvedIncidents
+	if maxResolved == 0 {
+		maxResolved = DefaultMaxResolvedIncidents
+	}
+
+	ids := d.incidentStorage.expired(maxResolved, time.Duration(d.opt.MaxIncidentAge)*time.Millisecond, time.Now())
+	glog.Infof("expiring %v incidents", len(ids))
+
+	for len(ids) > 0 {
+		n := expireBatchSize
+		if len(ids) < n {
+			n = len(ids)
 		}
 
+		if err := d.applyCMD(Command{Op: "remove_incidents", IncidentIDs: ids[:n]}); err != nil {
+			return err
+		}
+
+		ids = ids[n:]
 	}
+
+	return nil
 }
 
+// resolveTask is an incident to resolve by the resolver
+type resolveTask struct {
+	rule     *rules.Rule
+	groupKey string
+	eventID  string
+}
+
+// resolver resolves the queued incidents one at a time
+func (d *defaultStore) resolver() {
+	for {
+		select {
+		case task := <-d.resolveQueue:
+			d.resolveIncidentByKey(task.rule, task.groupKey, task.eventID)
+		case <-d.quitResolverChan:
+			return
+		}
+	}
+}
+
+// applyCMD applies the command. errors returned by the fsm are ignored
 func (d *defaultStore) applyCMD(cmd Command) error {
+	resp, err := d.applyCMDResponse(cmd)
+	if _, ok := resp.(error); ok {
+		return nil
+	}
+	return err
+}
+
+// applyCMDResponse applies the command and returns the fsm response. an error returned by the fsm is returned as err,
+// and as the response
+func (d *defaultStore) applyCMDResponse(cmd Command) (interface{}, error) {
 	if d.raft.State() != raft.Leader {
-		return fmt.Errorf("not leader")
+		return nil, errNotLeader
 	}
 
 	glog.Infof("apply cmd %v\n marshalling", cmd)
@@ -196,38 +397,267 @@ func (d *defaultStore) applyCMD(cmd Command) error {
 	b, err := cmd.MarshalMsg(nil)
 	if err != nil {
 		glog.Errorf("stash %v err %v\n", cmd, err)
-		return err
+		return nil, err
 	}
 
 	glog.Infof("==> apply %+v\n", cmd)
 	f := d.raft.Apply(b, raftTimeout)
-	return f.Error()
+	if err := f.Error(); err != nil {
+		return nil, err
+	}
+
+	if err, ok := f.Response().(error); ok {
+		return err, err
+	}
+
+	return f.Response(), nil
 }
 
-func (d *defaultStore) matchAndStash(event *events.Event) error {
-	glog.Info("match and stash event ==>  ", event)
+// match returns the rules, sorted by id, whose event type patterns match the event. incidents of the rules whose
+// resolve patterns match are queued for the resolver, blocking while the queue is full.
+func (d *defaultStore) match(event *events.Event) []*rules.Rule {
+	glog.Info("match event ==>  ", event)
+
+	var matched []*rules.Rule
 	for _, rule := range d.getRules() {
-		go d.match(rule, event)
+		if rule.HasMatching(event.EventType) {
+			matched = append(matched, rule)
+		}
+		if rule.HasResolveMatching(event.EventType) {
+			d.resolveQueue <- resolveTask{rule: rule, groupKey: incidents.GroupKey(rule.GroupKey, event), eventID: event.EventID}
+		}
 	}
-	return nil
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+	return matched
 }
 
-func (d *defaultStore) match(rule *rules.Rule, event *events.Event) error {
-	glog.Info("match event ==>  ", event)
-	if rule.HasMatching(event.EventType) {
-		go d.stash(rule.ID, event)
+func newIngestResult(event *events.Event, matched []*rules.Rule) *IngestResult {
+	result := &IngestResult{
+		EventID:      event.EventID,
+		MatchedRules: make([]string, 0),
+		Buckets:      make([]*StashResult, 0),
 	}
-	return nil
 
+	for _, rule := range matched {
+		result.MatchedRules = append(result.MatchedRules, rule.ID)
+	}
+
+	return result
+}
+
+// validate the event's data with the matching schemas. an invalid event is tagged in the tag mode, else a
+// schemas.InvalidEventError is returned. a schema which can't be compiled returns its schemas.SchemaError
+func (d *defaultStore) validate(event *events.Event) error {
+	var errs []string
+	for _, schema := range d.schemaStorage.match(event) {
+		schemaErrs, err := schema.ValidateEvent(event)
+		if err != nil {
+			glog.Errorf("event %v not validated %v", event.EventID, err)
+			return err
+		}
+		errs = append(errs, schemaErrs...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	glog.Infof("event %v failed validation %v", event.EventID, errs)
+
+	if d.opt.SchemaValidation == schemas.ModeTag {
+		schemas.Tag(event, errs)
+		return nil
+	}
+
+	return &schemas.InvalidEventError{EventID: event.EventID, Errors: errs}
+}
+
+// matchAndStash stashes the event in the bucket of every matching rule and waits for the raft applies
+func (d *defaultStore) matchAndStash(event *events.Event) (*IngestResult, error) {
+	glog.Info("match and stash event ==>  ", event)
+
+	d.normalizer.Normalize(event)
+
+	if err := d.validate(event); err != nil {
+		return nil, err
+	}
+
+	d.enrichmentStorage.enrich(event)
+
+	matched := d.match(event)
+	result := newIngestResult(event, matched)
+
+	results := make([]*StashResult, len(matched))
+	errs := make([]error, len(matched))
+	var wg sync.WaitGroup
+	for i, rule := range matched {
+		wg.Add(1)
+		go func(i int, ruleID string) {
+			defer wg.Done()
+			results[i], errs[i] = d.stash(ruleID, event)
+		}(i, rule.ID)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			glog.Errorf("stash event %v for rule %v err %v", event.EventID, matched[i].ID, err)
+			return nil, err
+		}
+		result.Buckets = append(result.Buckets, results[i])
+	}
+
+	return result, nil
+}
+
+// matchAndStashBatch stashes the events in the buckets of the matching rules. the stash commands are grouped into
+// batch commands of up to maxBatchCommands, so a batch costs a raft apply per maxBatchCommands stashes rather than one
+// per stash. a stash failure is reported in the event's result and an invalid event fails the whole batch. an apply
+// failure fails the whole batch only if nothing was committed yet, otherwise the earlier batch commands stay committed
+// and the failure is reported in the results of the events which were not, or only partly, stashed. the producer
+// retries those events, which are deduplicated by the buckets still open.
+func (d *defaultStore) matchAndStashBatch(batch []*events.Event) ([]*IngestResult, error) {
+	glog.Infof("match and stash batch of %v events", len(batch))
+
+	for _, event := range batch {
+		d.normalizer.Normalize(event)
+		if err := d.validate(event); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, event := range batch {
+		d.enrichmentStorage.enrich(event)
+	}
+
+	results := make([]*IngestResult, len(batch))
+	var commands []Command
+	var owners []int // [command index] index of the event
+	for i, event := range batch {
+		matched := d.match(event)
+		results[i] = newIngestResult(event, matched)
+
+		for _, rule := range matched {
+			commands = append(commands, Command{
+				Op:        "stash",
+				RuleID:    rule.ID,
+				Event:     event,
+				Timestamp: d.clock(),
+			})
+			owners = append(owners, i)
+		}
+	}
+
+	for start := 0; start < len(commands); start += maxBatchCommands {
+		end := start + maxBatchCommands
+		if end > len(commands) {
+			end = len(commands)
+		}
+
+		resp, err := d.applyCMDResponse(Command{
+			Op:       "batch",
+			Commands: commands[start:end],
+		})
+		if err != nil {
+			if start == 0 {
+				return nil, err
+			}
+			glog.Errorf("batch apply failed after %v of %v stashes err %v", start, len(commands), err)
+			failUnapplied(results, owners, start, err)
+			return results, nil
+		}
+
+		responses, ok := resp.([]interface{})
+		if !ok || len(responses) != end-start {
+			return nil, fmt.Errorf("unexpected batch response %v", resp)
+		}
+
+		for j, resp := range responses {
+			result := results[owners[start+j]]
+			stashResult, err := d.stashResult(commands[start+j].Event, resp)
+			if err != nil {
+				glog.Errorf("stash event %v for rule %v err %v", result.EventID, commands[start+j].RuleID, err)
+				result.Error = err.Error()
+				continue
+			}
+			result.Buckets = append(result.Buckets, stashResult)
+		}
+	}
+
+	return results, nil
 }
 
-func (d *defaultStore) stash(ruleID string, event *events.Event) error {
+// failUnapplied reports err in the results of the events owning the stash commands from the command index from on,
+// which were not applied
+func failUnapplied(results []*IngestResult, owners []int, from int, err error) {
+	for _, i := range owners[from:] {
+		results[i].Error = err.Error()
+	}
+}
+
+func (d *defaultStore) stash(ruleID string, event *events.Event) (*StashResult, error) {
 	glog.Info("apply stash event ==>  ", event)
-	return d.applyCMD(Command{
-		Op:     "stash",
-		RuleID: ruleID,
-		Event:  event,
+	resp, err := d.applyCMDResponse(Command{
+		Op:        "stash",
+		RuleID:    ruleID,
+		Event:     event,
+		Timestamp: d.clock(),
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.stashResult(event, resp)
+}
+
+// queueExecution queues the bucket for execution. returns false if the queue is full, the bucket then waits in
+// the pending list for the next flusher tick
+func (d *defaultStore) queueExecution(rb *events.Bucket) bool {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	// keep the order of the pending buckets
+	if len(d.pending) == 0 && d.executionPool.push(rb) {
+		return true
+	}
+
+	d.pending = append(d.pending, rb)
+	return false
+}
+
+// executePending moves the pending buckets to the execution queue while it has room
+func (d *defaultStore) executePending() {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	for len(d.pending) > 0 && d.executionPool.push(d.pending[0]) {
+		d.pending = d.pending[1:]
+	}
+}
+
+// stashResult returns the result of a stash command's fsm response and queues the late event re-execution, if any.
+// the event is committed, so a full execution queue defers the re-execution instead of failing the stash
+func (d *defaultStore) stashResult(event *events.Event, resp interface{}) (*StashResult, error) {
+	if err, ok := resp.(error); ok {
+		return nil, err
+	}
+
+	result, ok := resp.(*StashResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected stash response %v", resp)
+	}
+
+	// a late event re-executes the rule's latest bucket
+	if result.reexecute != nil {
+		glog.Infof("re-execute bucket %v with late event %v", result.RuleID, event.EventID)
+		if !d.queueExecution(result.reexecute) {
+			glog.Errorf("execution queue is full, late event %v re-execution deferred", event.EventID)
+			result.ReexecutionDeferred = true
+		}
+	}
+
+	return result, nil
 }
 
 func (d *defaultStore) addRule(rule *rules.Rule) error {
@@ -272,6 +702,86 @@ func (d *defaultStore) removeScript(id string) error {
 	})
 }
 
+func (d *defaultStore) addSink(sink *sinks.GenericSink) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:   "add_sink",
+		Sink: sink,
+	})
+	return err
+}
+
+func (d *defaultStore) updateSink(sink *sinks.GenericSink) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:   "update_sink",
+		Sink: sink,
+	})
+	return err
+}
+
+func (d *defaultStore) removeSink(id string) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:     "remove_sink",
+		SinkID: id,
+	})
+	return err
+}
+
+func (d *defaultStore) addSchema(schema *schemas.Schema) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:     "add_schema",
+		Schema: schema,
+	})
+	return err
+}
+
+func (d *defaultStore) updateSchema(schema *schemas.Schema) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:     "update_schema",
+		Schema: schema,
+	})
+	return err
+}
+
+func (d *defaultStore) removeSchema(id string) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:       "remove_schema",
+		SchemaID: id,
+	})
+	return err
+}
+
+func (d *defaultStore) addTable(table *enrichment.Table) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:    "add_table",
+		Table: table,
+	})
+	return err
+}
+
+func (d *defaultStore) updateTable(table *enrichment.Table) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:    "update_table",
+		Table: table,
+	})
+	return err
+}
+
+func (d *defaultStore) removeTable(id string) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:      "remove_table",
+		TableID: id,
+	})
+	return err
+}
+
+func (d *defaultStore) setPipeline(pipeline *enrichment.Pipeline) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:       "set_pipeline",
+		Pipeline: pipeline,
+	})
+	return err
+}
+
 func (d *defaultStore) removeRule(ruleID string) error {
 	return d.applyCMD(Command{
 		Op:     "remove_rule",
@@ -279,11 +789,21 @@ func (d *defaultStore) removeRule(ruleID string) error {
 	})
 }
 
-func (d *defaultStore) flushBucket(ruleID string) error {
-	return d.applyCMD(Command{
+// flushBucket flushes the rule's bucket and returns the window to execute, as flushed by the fsm
+func (d *defaultStore) flushBucket(ruleID string) (*events.Bucket, error) {
+	resp, err := d.applyCMDResponse(Command{
 		Op:     "flush_bucket",
 		RuleID: ruleID,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	window, ok := resp.(*events.Bucket)
+	if !ok {
+		return nil, fmt.Errorf("unexpected flush response %v", resp)
+	}
+	return window, nil
 }
 
 func (d *defaultStore) flushLock(ruleID string) error {
@@ -293,6 +813,40 @@ func (d *defaultStore) flushLock(ruleID string) error {
 	})
 }
 
+// requestFlush takes the flush lock on the bucket, the flusher executes it on the next tick
+func (d *defaultStore) requestFlush(ruleID string) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:     "flush_lock",
+		RuleID: ruleID,
+	})
+	return err
+}
+
+func (d *defaultStore) discardBucket(ruleID string) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:     "discard_bucket",
+		RuleID: ruleID,
+	})
+	return err
+}
+
+func (d *defaultStore) extendBucket(ruleID string, extension uint64) error {
+	_, err := d.applyCMDResponse(Command{
+		Op:        "extend_bucket",
+		RuleID:    ruleID,
+		Extension: extension,
+	})
+	return err
+}
+
+func (d *defaultStore) getBuckets() []*BucketStatus {
+	return d.bucketStorage.es.buckets(d.clock())
+}
+
+func (d *defaultStore) getBucket(ruleID string) *BucketStatus {
+	return d.bucketStorage.es.bucketStatus(ruleID, d.clock())
+}
+
 func (d *defaultStore) addRecord(r *executions.Record) error {
 	return d.applyCMD(Command{
 		Op:     "add_record",
@@ -307,6 +861,153 @@ func (d *defaultStore) removeRecord(id string) error {
 	})
 }
 
+func (d *defaultStore) removeRecords(ids []string) error {
+	return d.applyCMD(Command{
+		Op:        "remove_record",
+		RecordIDs: ids,
+	})
+}
+
+// getRetention returns the retention set at runtime or the configured retention
+func (d *defaultStore) getRetention() *executions.Retention {
+	if retention := d.executionStorage.getRetention(); retention != nil {
+		return retention
+	}
+
+	return &executions.Retention{
+		MaxRecords:        d.opt.MaxHistory,
+		MaxRecordsPerRule: d.opt.MaxHistoryPerRule,
+		MaxAge:            d.opt.MaxHistoryAge,
+	}
+}
+
+func (d *defaultStore) setRetention(retention *executions.Retention) error {
+	err := d.applyCMD(Command{
+		Op:        "set_retention",
+		Retention: retention,
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.expire()
+}
+
+func (d *defaultStore) getCheckpoint(inputID string) time.Time {
+	return d.checkpointStorage.getCheckpoint(inputID)
+}
+
+func (d *defaultStore) setCheckpoint(inputID string, readUntil time.Time) error {
+	return d.applyCMD(Command{
+		Op:        "set_checkpoint",
+		InputID:   inputID,
+		ReadUntil: readUntil,
+	})
+}
+
+func (d *defaultStore) upsertIncident(rule *rules.Rule, groupKey, recordID string) error {
+	resp, err := d.applyCMDResponse(Command{
+		Op: "upsert_incident",
+		Incident: &incidents.Incident{
+			ID:        uuid.NewV4().String(),
+			RuleID:    rule.ID,
+			GroupKey:  groupKey,
+			RecordIDs: []string{recordID},
+			UpdatedAt: time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	d.notifyIncident(rule, resp)
+	return nil
+}
+
+func (d *defaultStore) acknowledgeIncident(id string) error {
+	_, err := d.applyCMDResponse(Command{
+		Op: "ack_incident",
+		Incident: &incidents.Incident{
+			ID:        id,
+			UpdatedAt: time.Now(),
+		},
+	})
+	return err
+}
+
+func (d *defaultStore) resolveIncident(id string) error {
+	incident := d.incidentStorage.getIncident(id)
+	if incident == nil {
+		return fmt.Errorf("incident id does not exist")
+	}
+
+	resp, err := d.applyCMDResponse(Command{
+		Op: "resolve_incident",
+		Incident: &incidents.Incident{
+			ID:         id,
+			ResolvedBy: "api",
+			UpdatedAt:  time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	d.notifyIncident(d.getRule(incident.RuleID), resp)
+	return nil
+}
+
+func (d *defaultStore) resolveIncidentByKey(rule *rules.Rule, groupKey, eventID string) error {
+	if d.incidentStorage.getActive(rule.ID, groupKey) == nil {
+		return nil
+	}
+
+	resp, err := d.applyCMDResponse(Command{
+		Op: "resolve_incident",
+		Incident: &incidents.Incident{
+			RuleID:     rule.ID,
+			GroupKey:   groupKey,
+			ResolvedBy: eventID,
+			UpdatedAt:  time.Now(),
+		},
+	})
+	if err != nil {
+		glog.Errorf("resolve incident for rule %v group key %v err %v", rule.ID, groupKey, err)
+		return err
+	}
+
+	d.notifyIncident(rule, resp)
+	return nil
+}
+
+// notifyIncident posts the incident notification returned by the fsm to the rule's incident hook endpoint
+func (d *defaultStore) notifyIncident(rule *rules.Rule, resp interface{}) {
+	notification, ok := resp.(*incidents.Notification)
+	if !ok || rule == nil {
+		return
+	}
+
+	if _, err := url.ParseRequestURI(rule.IncidentHookEndpoint); err != nil {
+		glog.Infoln("Invalid IncidentHookEndpoint. Skipping incident notification")
+		return
+	}
+
+	statusCode := util.RetryPost(notification, rule.IncidentHookEndpoint, rule.HookRetry)
+	glog.Infof("incident %v notification %v posted with status %v", notification.Incident.ID, notification.Action, statusCode)
+}
+
+func (d *defaultStore) getIncidents(status string) []*incidents.Incident {
+	return d.incidentStorage.getIncidents(status)
+}
+
+func (d *defaultStore) getIncident(id string) *incidents.Incident {
+	return d.incidentStorage.getIncident(id)
+}
+
+func (d *defaultStore) getExecutionPoolStats() *ExecutionPoolStats {
+	return d.executionPool.stats()
+}
+
 func (d *defaultStore) getScripts() []string {
 	return d.scriptStorage.getScripts()
 }
@@ -315,6 +1016,38 @@ func (d *defaultStore) getScript(id string) *js.Script {
 	return d.scriptStorage.getScript(id)
 }
 
+func (d *defaultStore) getSinks() []*sinks.GenericSink {
+	return d.sinkStorage.getSinks()
+}
+
+func (d *defaultStore) getSink(id string) *sinks.GenericSink {
+	return d.sinkStorage.getSink(id)
+}
+
+func (d *defaultStore) getSinkByPath(path string) *sinks.GenericSink {
+	return d.sinkStorage.getSinkByPath(path)
+}
+
+func (d *defaultStore) getSchemas() []*schemas.Schema {
+	return d.schemaStorage.getSchemas()
+}
+
+func (d *defaultStore) getSchema(id string) *schemas.Schema {
+	return d.schemaStorage.getSchema(id)
+}
+
+func (d *defaultStore) getTables() []*enrichment.Table {
+	return d.enrichmentStorage.getTables()
+}
+
+func (d *defaultStore) getTable(id string) *enrichment.Table {
+	return d.enrichmentStorage.getTable(id)
+}
+
+func (d *defaultStore) getPipeline() *enrichment.Pipeline {
+	return d.enrichmentStorage.getPipeline()
+}
+
 func (d *defaultStore) getRules() []*rules.Rule {
 	return d.bucketStorage.rs.getRules()
 }
@@ -327,6 +1060,10 @@ func (d *defaultStore) getRecords(ruleID string) []*executions.Record {
 	return d.executionStorage.getRecords(ruleID)
 }
 
+func (d *defaultStore) queryRecords(q *executions.Query) (*executions.Page, error) {
+	return d.executionStorage.query(q)
+}
+
 func (d *defaultStore) getRecordsCount(ruleID string) int {
 	return d.executionStorage.getRecordsCount(ruleID)
 }